@@ -20,10 +20,17 @@
 //    --member $(memcoin --config /tmp/node1 ordering export)\
 //    --member $(memcoin --config /tmp/node2 ordering export)
 //
+//  # Only print the logs of the ordering service.
+//  memcoin --config /tmp/node4 start --port 2004 --log-subsystem cosipbft &
+//
 //  # Add the third after the chain is set up.
 //  memcoin --config /tmp/node1 ordering roster add\
 //    --member $(memcoin --config /tmp/node3 ordering export)
 //
+//  # And remove it again, waiting for the change to be committed.
+//  memcoin --config /tmp/node1 ordering roster remove --wait 10s\
+//    --member $(memcoin --config /tmp/node3 ordering export)
+//
 package main
 
 import (
@@ -31,6 +38,8 @@ import (
 	"io"
 	"os"
 
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/cli"
 	"go.dedis.ch/dela/cli/node"
 	access "go.dedis.ch/dela/contracts/access/controller"
 	cosipbft "go.dedis.ch/dela/core/ordering/cosipbft/controller"
@@ -61,6 +70,7 @@ func runWithCfg(args []string, cfg config) error {
 	builder := node.NewBuilderWithCfg(
 		cfg.Channel,
 		cfg.Writer,
+		logController{},
 		db.NewController(),
 		mino.NewController(),
 		cosipbft.NewController(),
@@ -79,3 +89,34 @@ func runWithCfg(args []string, cfg config) error {
 
 	return nil
 }
+
+// logController is an initializer that allows the logs to be filtered by
+// subsystem when the node starts.
+//
+// - implements node.Initializer
+type logController struct{}
+
+// SetCommands implements node.Initializer. It adds the flag to the start
+// command.
+func (logController) SetCommands(builder node.Builder) {
+	builder.SetStartFlags(
+		cli.StringSliceFlag{
+			Name:     "log-subsystem",
+			Usage:    "only print the logs of the subsystems (cosipbft, dkg, pool)",
+			Required: false,
+		},
+	)
+}
+
+// OnStart implements node.Initializer. It restricts the logs to the selected
+// subsystems, if any.
+func (logController) OnStart(flags cli.Flags, inj node.Injector) error {
+	dela.SetSubsystems(flags.StringSlice("log-subsystem")...)
+
+	return nil
+}
+
+// OnStop implements node.Initializer.
+func (logController) OnStop(node.Injector) error {
+	return nil
+}
@@ -14,6 +14,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/cli/node"
 )
 
 func TestMemcoin_Main(t *testing.T) {
@@ -158,6 +160,19 @@ func TestMemcoin_Scenario_RestartNode(t *testing.T) {
 	require.EqualError(t, err, "command error: transaction refused: duplicate in roster: 127.0.0.1:2210")
 }
 
+func TestLogController_OnStart(t *testing.T) {
+	defer dela.SetSubsystems()
+
+	ctrl := logController{}
+
+	flags := node.FlagSet{"log-subsystem": []interface{}{"cosipbft", "dkg"}}
+
+	err := ctrl.OnStart(flags, node.NewInjector())
+	require.NoError(t, err)
+
+	require.NoError(t, ctrl.OnStop(node.NewInjector()))
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
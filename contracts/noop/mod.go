@@ -0,0 +1,48 @@
+// Package noop implements a native contract that accepts every transaction
+// without side effects.
+//
+// A no-op transaction is a signed transaction that targets this contract. It
+// only consumes the nonce of the identity, which makes it useful to keep the
+// chain live or to benchmark the consensus without any application logic.
+//
+// Documentation Last Review: 15.10.2026
+//
+package noop
+
+import (
+	"go.dedis.ch/dela/core/execution"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/core/txn"
+)
+
+// ContractName is the name of the no-op contract.
+const ContractName = "go.dedis.ch/dela.Noop"
+
+// RegisterContract registers the no-op contract to the given execution service.
+func RegisterContract(exec *native.Service, c Contract) {
+	exec.Set(ContractName, c)
+}
+
+// NewArgs returns the arguments of a no-op transaction.
+func NewArgs() []txn.Arg {
+	return []txn.Arg{
+		{Key: native.ContractArg, Value: []byte(ContractName)},
+	}
+}
+
+// Contract is a smart contract that does nothing.
+//
+// - implements native.Contract
+type Contract struct{}
+
+// NewContract creates a new no-op contract.
+func NewContract() Contract {
+	return Contract{}
+}
+
+// Execute implements native.Contract. It always accepts the transaction and
+// leaves the snapshot untouched.
+func (Contract) Execute(store.Snapshot, execution.Step) error {
+	return nil
+}
@@ -0,0 +1,34 @@
+package noop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/execution"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestRegisterContract(t *testing.T) {
+	exec := native.NewExecution()
+	RegisterContract(exec, NewContract())
+
+	tx, err := signed.NewTransaction(0, fake.PublicKey{},
+		signed.WithArg(NewArgs()[0].Key, NewArgs()[0].Value))
+	require.NoError(t, err)
+
+	snap := fake.NewSnapshot()
+
+	res, err := exec.Execute(snap, execution.Step{Current: tx})
+	require.NoError(t, err)
+	require.True(t, res.Accepted)
+	require.Empty(t, res.Message)
+}
+
+func TestNewArgs(t *testing.T) {
+	args := NewArgs()
+	require.Len(t, args, 1)
+	require.Equal(t, native.ContractArg, args[0].Key)
+	require.Equal(t, []byte(ContractName), args[0].Value)
+}
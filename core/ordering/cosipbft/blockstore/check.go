@@ -0,0 +1,100 @@
+// This file contains the implementation of the integrity check of a block
+// store.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package blockstore
+
+import (
+	"errors"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"golang.org/x/xerrors"
+)
+
+// Check verifies the integrity of the blocks in the store, starting from the
+// genesis digest. The digest of a block is computed again when it is read, so
+// that a corrupted block either fails to be decoded or does not match the link
+// of the next block anymore. It only reads from the store and returns an error
+// describing the first corrupted block, if any. Only the forward links of the
+// pruned blocks can be verified.
+func Check(blocks BlockStore, genesis types.Digest) error {
+	length := blocks.Len()
+	if length == 0 {
+		return nil
+	}
+
+	prev := genesis
+
+	var checkpoints []types.Link
+
+	for i := uint64(0); i < length; i++ {
+		link, err := blocks.GetByIndex(i)
+		if errors.Is(err, ErrPruned) {
+			if checkpoints == nil {
+				chain, err := blocks.GetChain()
+				if err != nil {
+					return xerrors.Errorf("failed to read chain: %v", err)
+				}
+
+				checkpoints = chain.GetLinks()
+			}
+
+			if checkpoints[i].GetFrom() != prev {
+				return xerrors.Errorf("checkpoint %d is corrupted: mismatch digest '%v' != '%v'",
+					i, prev, checkpoints[i].GetFrom())
+			}
+
+			prev = checkpoints[i].GetTo()
+			continue
+		}
+
+		if err != nil {
+			return xerrors.Errorf("block %d is corrupted: %v", i, err)
+		}
+
+		index := link.GetBlock().GetIndex()
+		if index != i {
+			return xerrors.Errorf("block %d is corrupted: mismatch index %d", i, index)
+		}
+
+		if link.GetFrom() != prev {
+			if i == 0 {
+				return xerrors.Errorf("block 0 is corrupted: mismatch genesis '%v' != '%v'",
+					link.GetFrom(), prev)
+			}
+
+			return xerrors.Errorf("block %d is corrupted: mismatch digest '%v' != '%v'",
+				i-1, prev, link.GetFrom())
+		}
+
+		prev = link.GetTo()
+	}
+
+	// The digest of the last block is not referenced by any link on disk, so it
+	// is compared with the one known by the store, unless a block has been
+	// stored in the meantime, in which case its link is used.
+	last, err := blocks.Last()
+	if err != nil {
+		return xerrors.Errorf("failed to read last block: %v", err)
+	}
+
+	expected := last.GetTo()
+
+	if last.GetBlock().GetIndex() >= length {
+		next, err := blocks.GetByIndex(length)
+		if err != nil {
+			return xerrors.Errorf("block %d is corrupted: %v", length, err)
+		}
+
+		expected = next.GetFrom()
+	}
+
+	if expected != prev {
+		return xerrors.Errorf("block %d is corrupted: mismatch digest '%v' != '%v'",
+			length-1, prev, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package blockstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/core/store/kv"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestCheck(t *testing.T) {
+	db, clean := makeDB(t)
+	defer clean()
+
+	store := NewDiskStore(db, makeBlockFac())
+
+	err := Check(store, types.Digest{})
+	require.NoError(t, err)
+
+	links := make([]types.BlockLink, 3)
+
+	prev := types.Digest{}
+	for i := range links {
+		links[i] = makeLink(t, prev, types.WithIndex(uint64(i)))
+
+		err = store.Store(links[i])
+		require.NoError(t, err)
+
+		prev = links[i].GetTo()
+	}
+
+	err = Check(store, types.Digest{})
+	require.NoError(t, err)
+
+	err = Check(store, types.Digest{1})
+	require.EqualError(t, err,
+		"block 0 is corrupted: mismatch genesis '00000000' != '01000000'")
+
+	// The forward links of the pruned blocks are verified instead.
+	mem := NewInMemory()
+	for _, link := range links {
+		require.NoError(t, mem.Store(link))
+	}
+
+	require.NoError(t, mem.Prune(2))
+
+	err = Check(mem, types.Digest{})
+	require.NoError(t, err)
+
+	err = Check(mem, types.Digest{1})
+	require.EqualError(t, err,
+		"checkpoint 0 is corrupted: mismatch digest '01000000' != '00000000'")
+
+	// Replace the block at index 1 with a block of the same index but a
+	// different content, as a bit rot would do.
+	overwrite(t, store, makeLink(t, links[0].GetTo(), types.WithIndex(1),
+		types.WithTreeRoot(types.Digest{1})))
+
+	err = Check(store, types.Digest{})
+	require.Error(t, err)
+	require.Regexp(t, "^block 1 is corrupted: mismatch digest", err.Error())
+
+	// The last block is not referenced by any link, but it must still be
+	// detected.
+	overwrite(t, store, links[1])
+	overwrite(t, store, makeLink(t, links[1].GetTo(), types.WithIndex(2),
+		types.WithTreeRoot(types.Digest{2})))
+
+	err = Check(store, types.Digest{})
+	require.Error(t, err)
+	require.Regexp(t, "^block 2 is corrupted: mismatch digest", err.Error())
+}
+
+func TestCheck_Failures(t *testing.T) {
+	err := Check(badCheckStore{}, types.Digest{})
+	require.EqualError(t, err, fake.Err("block 0 is corrupted"))
+
+	err = Check(badCheckStore{link: makeLink(t, types.Digest{}, types.WithIndex(1))},
+		types.Digest{})
+	require.EqualError(t, err, "block 0 is corrupted: mismatch index 1")
+
+	err = Check(badCheckStore{link: makeLink(t, types.Digest{})}, types.Digest{})
+	require.EqualError(t, err, fake.Err("failed to read last block"))
+
+	// A block is stored while the store is checked.
+	first := makeLink(t, types.Digest{})
+	next := makeLink(t, first.GetTo(), types.WithIndex(1))
+
+	err = Check(badCheckStore{link: first, last: next}, types.Digest{})
+	require.NoError(t, err)
+
+	err = Check(badCheckStore{link: first, last: next, errNext: fake.GetError()},
+		types.Digest{})
+	require.EqualError(t, err, fake.Err("block 1 is corrupted"))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func overwrite(t *testing.T, store *InDisk, link types.BlockLink) {
+	data, err := link.Serialize(store.context)
+	require.NoError(t, err)
+
+	err = store.db.Update(func(tx kv.WritableTx) error {
+		key := store.makeKey(link.GetBlock().GetIndex())
+
+		return tx.GetBucket(store.bucket).Set(key, data)
+	})
+	require.NoError(t, err)
+}
+
+type badCheckStore struct {
+	BlockStore
+
+	link    types.BlockLink
+	last    types.BlockLink
+	errNext error
+}
+
+func (s badCheckStore) Len() uint64 {
+	return 1
+}
+
+func (s badCheckStore) GetByIndex(index uint64) (types.BlockLink, error) {
+	if s.link == nil {
+		return nil, fake.GetError()
+	}
+
+	if index > 0 {
+		return s.last, s.errNext
+	}
+
+	return s.link, nil
+}
+
+func (s badCheckStore) Last() (types.BlockLink, error) {
+	if s.last == nil {
+		return nil, fake.GetError()
+	}
+
+	return s.last, nil
+}
@@ -24,6 +24,7 @@ type cachedData struct {
 	sync.Mutex
 
 	length  uint64
+	pruned  uint64
 	last    types.BlockLink
 	indices map[types.Digest]uint64
 }
@@ -34,11 +35,12 @@ type cachedData struct {
 type InDisk struct {
 	*cachedData
 
-	db      kv.DB
-	bucket  []byte
-	context serde.Context
-	fac     types.LinkFactory
-	watcher core.Observable
+	db          kv.DB
+	bucket      []byte
+	checkpoints []byte
+	context     serde.Context
+	fac         types.LinkFactory
+	watcher     core.Observable
 
 	txn store.Transaction
 }
@@ -46,11 +48,12 @@ type InDisk struct {
 // NewDiskStore creates a new persistent storage.
 func NewDiskStore(db kv.DB, fac types.LinkFactory) *InDisk {
 	return &InDisk{
-		db:      db,
-		bucket:  []byte("blocks"),
-		context: json.NewContext(),
-		fac:     fac,
-		watcher: core.NewWatcher(),
+		db:          db,
+		bucket:      []byte("blocks"),
+		checkpoints: []byte("checkpoints"),
+		context:     json.NewContext(),
+		fac:         fac,
+		watcher:     core.NewWatcher(),
 		cachedData: &cachedData{
 			indices: make(map[types.Digest]uint64),
 		},
@@ -72,6 +75,33 @@ func (s *InDisk) Load() error {
 	defer s.Unlock()
 
 	return s.doView(func(tx kv.ReadableTx) error {
+		// The keys are scanned in byte order which differs from the order of
+		// the indices, so the index is decoded from the key.
+		pruned := tx.GetBucket(s.checkpoints)
+		if pruned != nil {
+			err := pruned.Scan([]byte{}, func(key, value []byte) error {
+				link, err := s.fac.LinkOf(s.context, value)
+				if err != nil {
+					return xerrors.Errorf("malformed link: %v", err)
+				}
+
+				index := binary.LittleEndian.Uint64(key)
+
+				s.length++
+				s.indices[link.GetTo()] = index
+
+				if index >= s.pruned {
+					s.pruned = index + 1
+				}
+
+				return nil
+			})
+
+			if err != nil {
+				return xerrors.Errorf("while scanning pruned: %v", err)
+			}
+		}
+
 		bucket := tx.GetBucket(s.bucket)
 		if bucket == nil {
 			return nil
@@ -84,9 +114,12 @@ func (s *InDisk) Load() error {
 			}
 
 			s.length++
-			s.last = link
 			s.indices[link.GetBlock().GetHash()] = link.GetBlock().GetIndex()
 
+			if s.last == nil || link.GetBlock().GetIndex() > s.last.GetBlock().GetIndex() {
+				s.last = link
+			}
+
 			return nil
 		})
 
@@ -163,6 +196,14 @@ func (s *InDisk) Get(id types.Digest) (types.BlockLink, error) {
 // GetByIndex implements blockstore.BlockStore. It returns the block associated
 // to the index if it exists, otherwise it returns an error.
 func (s *InDisk) GetByIndex(index uint64) (link types.BlockLink, err error) {
+	s.Lock()
+	pruned := s.pruned
+	s.Unlock()
+
+	if index < pruned {
+		return nil, xerrors.Errorf("index %d is pruned: %w", index, ErrPruned)
+	}
+
 	key := s.makeKey(index)
 
 	err = s.doView(func(tx kv.ReadableTx) error {
@@ -197,33 +238,59 @@ func (s *InDisk) GetChain() (types.Chain, error) {
 		return nil, xerrors.New("store is empty")
 	}
 
-	prevs := make([]types.Link, length-1)
+	prevs := []types.Link{}
 
-	var chain types.Chain
+	if length > 1 {
+		var err error
+		prevs, err = s.GetReducedChain(0, length-2)
+		if err != nil {
+			return nil, xerrors.Errorf("reading links: %v", err)
+		}
+	}
+
+	last, err := s.GetByIndex(length - 1)
+	if err != nil {
+		return nil, xerrors.Errorf("reading last block: %v", err)
+	}
+
+	return types.NewChain(last, prevs), nil
+}
+
+// GetReducedChain implements blockstore.BlockStore. It returns the forward
+// links of the blocks in the range of indices.
+func (s *InDisk) GetReducedChain(from, to uint64) ([]types.Link, error) {
+	s.Lock()
+	length := s.length
+	pruned := s.pruned
+	s.Unlock()
+
+	if from > to || to >= length {
+		return nil, xerrors.Errorf("invalid range [%d, %d] for %d block(s)",
+			from, to, length)
+	}
+
+	links := make([]types.Link, 0, to-from+1)
 
 	err := s.doView(func(tx kv.ReadableTx) error {
-		bucket := tx.GetBucket(s.bucket)
+		for i := from; i <= to; i++ {
+			key := s.makeKey(i)
 
-		i := uint64(0)
-		err := bucket.Scan([]byte{}, func(key, value []byte) error {
-			link, err := s.fac.BlockLinkOf(s.context, value)
-			if err != nil {
-				return xerrors.Errorf("block malformed: %v", err)
-			}
+			if i < pruned {
+				link, err := s.fac.LinkOf(s.context, tx.GetBucket(s.checkpoints).Get(key))
+				if err != nil {
+					return xerrors.Errorf("link %d malformed: %v", i, err)
+				}
 
-			if i >= length-1 {
-				chain = types.NewChain(link, prevs)
-				return nil
+				links = append(links, link)
+				continue
 			}
 
-			prevs[i] = link.Reduce()
-			i++
-
-			return nil
-		})
+			link, err := s.fac.BlockLinkOf(s.context, tx.GetBucket(s.bucket).Get(key))
+			if err != nil {
+				return xerrors.Errorf("block %d malformed: %v", i, err)
+			}
 
-		if err != nil {
-			return xerrors.Errorf("while scanning: %v", err)
+			links = append(links, link.Reduce())
 		}
 
 		return nil
@@ -233,7 +300,7 @@ func (s *InDisk) GetChain() (types.Chain, error) {
 		return nil, xerrors.Errorf("while reading database: %v", err)
 	}
 
-	return chain, nil
+	return links, nil
 }
 
 // Last implements blockstore.BlockStore. It returns the last block stored in
@@ -261,18 +328,84 @@ func (s *InDisk) Watch(ctx context.Context) <-chan types.BlockLink {
 // transaction for the operations on the database.
 func (s *InDisk) WithTx(txn store.Transaction) BlockStore {
 	store := &InDisk{
-		db:         s.db,
-		bucket:     s.bucket,
-		context:    s.context,
-		fac:        s.fac,
-		watcher:    s.watcher,
-		cachedData: s.cachedData,
-		txn:        txn,
+		db:          s.db,
+		bucket:      s.bucket,
+		checkpoints: s.checkpoints,
+		context:     s.context,
+		fac:         s.fac,
+		watcher:     s.watcher,
+		cachedData:  s.cachedData,
+		txn:         txn,
 	}
 
 	return store
 }
 
+// Prune implements blockstore.BlockStore. It removes the blocks below the index
+// from the database and only keeps their forward links. The latest block cannot
+// be pruned.
+func (s *InDisk) Prune(beforeIndex uint64) error {
+	s.Lock()
+	length := s.length
+	pruned := s.pruned
+	s.Unlock()
+
+	if beforeIndex >= length {
+		return xerrors.Errorf("cannot prune before %d with %d block(s)",
+			beforeIndex, length)
+	}
+
+	if beforeIndex <= pruned {
+		return nil
+	}
+
+	return s.doUpdate(func(tx kv.WritableTx) error {
+		bucket, err := tx.GetBucketOrCreate(s.bucket)
+		if err != nil {
+			return xerrors.Errorf("bucket failed: %v", err)
+		}
+
+		checkpoints, err := tx.GetBucketOrCreate(s.checkpoints)
+		if err != nil {
+			return xerrors.Errorf("bucket failed: %v", err)
+		}
+
+		for index := pruned; index < beforeIndex; index++ {
+			key := s.makeKey(index)
+
+			link, err := s.fac.BlockLinkOf(s.context, bucket.Get(key))
+			if err != nil {
+				return xerrors.Errorf("malformed block %d: %v", index, err)
+			}
+
+			data, err := link.Reduce().Serialize(s.context)
+			if err != nil {
+				return xerrors.Errorf("failed to serialize link: %v", err)
+			}
+
+			err = checkpoints.Set(key, data)
+			if err != nil {
+				return xerrors.Errorf("while writing link: %v", err)
+			}
+
+			err = bucket.Delete(key)
+			if err != nil {
+				return xerrors.Errorf("while deleting block: %v", err)
+			}
+		}
+
+		tx.OnCommit(func() {
+			s.Lock()
+			if beforeIndex > s.pruned {
+				s.pruned = beforeIndex
+			}
+			s.Unlock()
+		})
+
+		return nil
+	})
+}
+
 func (s *InDisk) doUpdate(fn func(tx kv.WritableTx) error) error {
 	if s.txn != nil {
 		tx, ok := s.txn.(kv.WritableTx)
@@ -2,6 +2,7 @@ package blockstore
 
 import (
 	"context"
+	"errors"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -146,7 +147,82 @@ func TestInDisk_GetChain(t *testing.T) {
 
 	store.fac = badLinkFac{}
 	_, err = store.GetChain()
-	require.EqualError(t, err, fake.Err("while reading database: while scanning: block malformed"))
+	require.EqualError(t, err,
+		fake.Err("reading links: while reading database: block 0 malformed"))
+}
+
+func TestInDisk_Long_GetChain(t *testing.T) {
+	db, clean := makeDB(t)
+	defer clean()
+
+	store := NewDiskStore(db, makeBlockFac())
+
+	genesis, err := types.NewGenesis(authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner)))
+	require.NoError(t, err)
+
+	// The keys are not ordered the same way as the indices after 256 blocks.
+	prev := genesis.GetHash()
+	for i := 0; i < 300; i++ {
+		link := makeLink(t, prev, types.WithIndex(uint64(i)))
+		require.NoError(t, store.Store(link))
+
+		prev = link.GetTo()
+	}
+
+	chain, err := store.GetChain()
+	require.NoError(t, err)
+	require.Equal(t, uint64(299), chain.GetBlock().GetIndex())
+
+	err = chain.Verify(genesis, fake.VerifierFactory{})
+	require.NoError(t, err)
+}
+
+func TestInDisk_GetReducedChain(t *testing.T) {
+	db, clean := makeDB(t)
+	defer clean()
+
+	store := NewDiskStore(db, makeBlockFac())
+
+	_, err := store.GetReducedChain(0, 0)
+	require.EqualError(t, err, "invalid range [0, 0] for 0 block(s)")
+
+	links := make([]types.BlockLink, 4)
+
+	prev := types.Digest{}
+	for i := range links {
+		links[i] = makeLink(t, prev, types.WithIndex(uint64(i)))
+		require.NoError(t, store.Store(links[i]))
+
+		prev = links[i].GetTo()
+	}
+
+	reduced, err := store.GetReducedChain(1, 2)
+	require.NoError(t, err)
+	require.Len(t, reduced, 2)
+	require.Equal(t, links[1].GetTo(), reduced[0].GetTo())
+	require.Equal(t, links[2].GetTo(), reduced[1].GetTo())
+
+	// The pruned blocks are replaced by their checkpoint.
+	require.NoError(t, store.Prune(2))
+
+	reduced, err = store.GetReducedChain(0, 3)
+	require.NoError(t, err)
+	require.Len(t, reduced, 4)
+
+	for i, link := range reduced {
+		require.Equal(t, links[i].GetFrom(), link.GetFrom())
+		require.Equal(t, links[i].GetTo(), link.GetTo())
+	}
+
+	_, err = store.GetReducedChain(2, 1)
+	require.EqualError(t, err, "invalid range [2, 1] for 4 block(s)")
+
+	_, err = store.GetReducedChain(0, 4)
+	require.EqualError(t, err, "invalid range [0, 4] for 4 block(s)")
+
+	store.fac = badLinkFac{}
+	_, err = store.GetReducedChain(2, 3)
+	require.EqualError(t, err, fake.Err("while reading database: block 2 malformed"))
 }
 
 func TestInDisk_Last(t *testing.T) {
@@ -220,6 +296,118 @@ func TestInDisk_WithTx(t *testing.T) {
 // -----------------------------------------------------------------------------
 // Utility functions
 
+func TestInDisk_Prune(t *testing.T) {
+	db, clean := makeDB(t)
+	defer clean()
+
+	store := NewDiskStore(db, makeBlockFac())
+
+	err := store.Prune(0)
+	require.EqualError(t, err, "cannot prune before 0 with 0 block(s)")
+
+	links := make([]types.BlockLink, 4)
+
+	prev := types.Digest{}
+	for i := range links {
+		links[i] = makeLink(t, prev, types.WithIndex(uint64(i)))
+
+		err = store.Store(links[i])
+		require.NoError(t, err)
+
+		prev = links[i].GetTo()
+	}
+
+	err = store.Prune(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), store.Len())
+
+	_, err = store.GetByIndex(1)
+	require.EqualError(t, err, "index 1 is pruned: block pruned")
+	require.True(t, errors.Is(err, ErrPruned))
+
+	_, err = store.Get(links[0].GetTo())
+	require.EqualError(t, err, "index 0 is pruned: block pruned")
+
+	link, err := store.GetByIndex(2)
+	require.NoError(t, err)
+	require.Equal(t, links[2].GetTo(), link.GetTo())
+
+	chain, err := store.GetChain()
+	require.NoError(t, err)
+	require.Len(t, chain.GetLinks(), 4)
+	require.Equal(t, links[1].GetTo(), chain.GetLinks()[1].GetTo())
+	require.Equal(t, uint64(3), chain.GetBlock().GetIndex())
+
+	err = store.Prune(1)
+	require.NoError(t, err)
+
+	err = store.Prune(4)
+	require.EqualError(t, err, "cannot prune before 4 with 4 block(s)")
+
+	// The pruned blocks must be known after a restart.
+	newStore := NewDiskStore(db, makeBlockFac())
+
+	err = newStore.Load()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), newStore.Len())
+	require.Equal(t, uint64(2), newStore.pruned)
+
+	_, err = newStore.GetByIndex(0)
+	require.True(t, errors.Is(err, ErrPruned))
+
+	err = Check(newStore, types.Digest{})
+	require.NoError(t, err)
+
+	newStore.fac = badLinkFac{}
+	err = newStore.Prune(3)
+	require.EqualError(t, err, fake.Err("malformed block 2"))
+}
+
+func TestInDisk_Long_Prune(t *testing.T) {
+	db, clean := makeDB(t)
+	defer clean()
+
+	store := NewDiskStore(db, makeBlockFac())
+
+	num := 300
+
+	links := make([]types.BlockLink, num)
+
+	prev := types.Digest{}
+	for i := range links {
+		links[i] = makeLink(t, prev, types.WithIndex(uint64(i)))
+
+		err := store.Store(links[i])
+		require.NoError(t, err)
+
+		prev = links[i].GetTo()
+	}
+
+	err := store.Prune(uint64(num - 1))
+	require.NoError(t, err)
+
+	// The keys of the checkpoints are not scanned in the order of the indices
+	// after 256 blocks.
+	newStore := NewDiskStore(db, makeBlockFac())
+
+	err = newStore.Load()
+	require.NoError(t, err)
+	require.Equal(t, uint64(num), newStore.Len())
+	require.Equal(t, uint64(num-1), newStore.pruned)
+	require.Equal(t, links[num-1].GetTo(), newStore.last.GetTo())
+	require.Equal(t, uint64(num-1), newStore.indices[links[num-1].GetTo()])
+
+	for i, link := range links[:num-1] {
+		require.Equal(t, uint64(i), newStore.indices[link.GetTo()])
+	}
+
+	_, err = newStore.GetByIndex(uint64(num - 2))
+	require.True(t, errors.Is(err, ErrPruned))
+
+	err = Check(newStore, types.Digest{})
+	require.NoError(t, err)
+}
+
 func makeDB(t *testing.T) (kv.DB, func()) {
 	file, err := ioutil.TempFile(os.TempDir(), "dela-blockstore")
 	require.NoError(t, err)
@@ -27,6 +27,9 @@ const sizeWarnLimit = 100
 // - implements blockstore.BlockStore
 type InMemory struct {
 	sync.Mutex
+	// pruned contains the forward links of the blocks that have been pruned,
+	// and blocks the remaining ones that follow.
+	pruned  []types.Link
 	blocks  []types.BlockLink
 	watcher core.Observable
 	withTx  bool
@@ -45,7 +48,7 @@ func (s *InMemory) Len() uint64 {
 	s.Lock()
 	defer s.Unlock()
 
-	return uint64(len(s.blocks))
+	return uint64(len(s.pruned) + len(s.blocks))
 }
 
 // Store implements blockstore.BlockStore. It stores the block only if the link
@@ -85,6 +88,12 @@ func (s *InMemory) Get(id types.Digest) (types.BlockLink, error) {
 		}
 	}
 
+	for i, link := range s.pruned {
+		if link.GetTo() == id {
+			return nil, xerrors.Errorf("block %d is pruned: %w", i, ErrPruned)
+		}
+	}
+
 	return nil, xerrors.Errorf("block not found: %w", ErrNoBlock)
 }
 
@@ -94,7 +103,13 @@ func (s *InMemory) GetByIndex(index uint64) (types.BlockLink, error) {
 	s.Lock()
 	defer s.Unlock()
 
-	if int(index) >= len(s.blocks) {
+	if index < uint64(len(s.pruned)) {
+		return nil, xerrors.Errorf("block %d is pruned: %w", index, ErrPruned)
+	}
+
+	index -= uint64(len(s.pruned))
+
+	if index >= uint64(len(s.blocks)) {
 		return nil, xerrors.Errorf("block not found: %w", ErrNoBlock)
 	}
 
@@ -113,14 +128,49 @@ func (s *InMemory) GetChain() (types.Chain, error) {
 		return nil, xerrors.New("store is empty")
 	}
 
-	prevs := make([]types.Link, num)
-	for i, block := range s.blocks[:num] {
-		prevs[i] = block.Reduce()
+	prevs := []types.Link{}
+
+	if len(s.pruned)+num > 0 {
+		var err error
+		prevs, err = s.reducedChain(0, uint64(len(s.pruned)+num-1))
+		if err != nil {
+			return nil, xerrors.Errorf("reading links: %v", err)
+		}
 	}
 
 	return types.NewChain(s.blocks[num], prevs), nil
 }
 
+// GetReducedChain implements blockstore.BlockStore. It returns the forward
+// links of the blocks in the range of indices.
+func (s *InMemory) GetReducedChain(from, to uint64) ([]types.Link, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.reducedChain(from, to)
+}
+
+func (s *InMemory) reducedChain(from, to uint64) ([]types.Link, error) {
+	length := uint64(len(s.pruned) + len(s.blocks))
+
+	if from > to || to >= length {
+		return nil, xerrors.Errorf("invalid range [%d, %d] for %d block(s)",
+			from, to, length)
+	}
+
+	links := make([]types.Link, 0, to-from+1)
+
+	for i := from; i <= to; i++ {
+		if i < uint64(len(s.pruned)) {
+			links = append(links, s.pruned[i])
+		} else {
+			links = append(links, s.blocks[i-uint64(len(s.pruned))].Reduce())
+		}
+	}
+
+	return links, nil
+}
+
 // Last implements blockstore.BlockStore. It returns the latest block of the
 // store.
 func (s *InMemory) Last() (types.BlockLink, error) {
@@ -146,19 +196,26 @@ func (s *InMemory) Watch(ctx context.Context) <-chan types.BlockLink {
 // apply the list of blocks at the end of the transaction.
 func (s *InMemory) WithTx(txn store.Transaction) BlockStore {
 	store := &InMemory{
+		pruned:  append([]types.Link{}, s.pruned...),
 		blocks:  append([]types.BlockLink{}, s.blocks...),
 		watcher: s.watcher,
 		withTx:  true,
 	}
 
-	from := len(store.blocks)
+	from := store.Len()
 
 	txn.OnCommit(func() {
 		s.Lock()
+		s.pruned = store.pruned
 		s.blocks = store.blocks
 		s.withTx = false
 
-		newBlocks := append([]types.BlockLink{}, s.blocks[from:]...)
+		offset := len(s.pruned)
+		if int(from) > offset {
+			offset = int(from)
+		}
+
+		newBlocks := append([]types.BlockLink{}, s.blocks[offset-len(s.pruned):]...)
 		s.Unlock()
 
 		for _, link := range newBlocks {
@@ -169,6 +226,30 @@ func (s *InMemory) WithTx(txn store.Transaction) BlockStore {
 	return store
 }
 
+// Prune implements blockstore.BlockStore. It removes the blocks below the index
+// and only keeps their forward links. The latest block cannot be pruned.
+func (s *InMemory) Prune(beforeIndex uint64) error {
+	s.Lock()
+	defer s.Unlock()
+
+	length := uint64(len(s.pruned) + len(s.blocks))
+
+	if beforeIndex >= length {
+		return xerrors.Errorf("cannot prune before %d with %d block(s)",
+			beforeIndex, length)
+	}
+
+	for uint64(len(s.pruned)) < beforeIndex {
+		s.pruned = append(s.pruned, s.blocks[0].Reduce())
+		s.blocks = s.blocks[1:]
+	}
+
+	// The slice is copied so that the pruned blocks can be garbage collected.
+	s.blocks = append([]types.BlockLink{}, s.blocks...)
+
+	return nil
+}
+
 // Observer is an observer that can be added to store watcher. It will announce
 // the blocks in order and without blocking the watcher even if the listener is
 // not actively emptying the queue.
@@ -2,9 +2,11 @@ package blockstore
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/store"
 	"go.dedis.ch/dela/core/validation/simple"
@@ -89,6 +91,53 @@ func TestInMemory_GetChain(t *testing.T) {
 	require.EqualError(t, err, "store is empty")
 }
 
+func TestInMemory_GetReducedChain(t *testing.T) {
+	store := NewInMemory()
+
+	_, err := store.GetReducedChain(0, 0)
+	require.EqualError(t, err, "invalid range [0, 0] for 0 block(s)")
+
+	genesis, err := types.NewGenesis(authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner)))
+	require.NoError(t, err)
+
+	prev := genesis.GetHash()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, store.Store(makeLink(t, prev, types.WithIndex(uint64(i)))))
+
+		prev = store.blocks[i].GetTo()
+	}
+
+	full, err := store.GetChain()
+	require.NoError(t, err)
+
+	reduced, err := store.GetReducedChain(0, 2)
+	require.NoError(t, err)
+	require.Len(t, reduced, 3)
+
+	last, err := store.Last()
+	require.NoError(t, err)
+
+	// The chain made of the reduced links verifies the same way as the full
+	// one.
+	compact := types.NewChain(last, reduced)
+	require.NoError(t, full.Verify(genesis, fake.VerifierFactory{}))
+	require.NoError(t, compact.Verify(genesis, fake.VerifierFactory{}))
+	require.Equal(t, full.GetLinks(), compact.GetLinks())
+
+	// Both are rejected when a link is missing.
+	compact = types.NewChain(last, reduced[1:])
+	require.Error(t, compact.Verify(genesis, fake.VerifierFactory{}))
+
+	require.NoError(t, store.Prune(3))
+
+	pruned, err := store.GetReducedChain(0, 2)
+	require.NoError(t, err)
+	require.Equal(t, reduced, pruned)
+
+	_, err = store.GetReducedChain(3, 4)
+	require.EqualError(t, err, "invalid range [3, 4] for 4 block(s)")
+}
+
 func TestInMemory_Last(t *testing.T) {
 	store := NewInMemory()
 
@@ -137,6 +186,54 @@ func TestInMemory_WithTx(t *testing.T) {
 	require.Len(t, store.blocks, 1)
 }
 
+func TestInMemory_Prune(t *testing.T) {
+	store := NewInMemory()
+
+	prev := types.Digest{}
+	for i := 0; i < 4; i++ {
+		err := store.Store(makeLink(t, prev, types.WithIndex(uint64(i))))
+		require.NoError(t, err)
+
+		prev = store.blocks[len(store.blocks)-1].GetTo()
+	}
+
+	pruned := store.blocks[1].GetTo()
+
+	err := store.Prune(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), store.Len())
+	require.Len(t, store.pruned, 2)
+	require.Len(t, store.blocks, 2)
+
+	_, err = store.GetByIndex(1)
+	require.EqualError(t, err, "block 1 is pruned: block pruned")
+	require.True(t, errors.Is(err, ErrPruned))
+
+	_, err = store.Get(pruned)
+	require.EqualError(t, err, "block 1 is pruned: block pruned")
+
+	link, err := store.GetByIndex(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), link.GetBlock().GetIndex())
+
+	chain, err := store.GetChain()
+	require.NoError(t, err)
+	require.Len(t, chain.GetLinks(), 4)
+	require.Equal(t, pruned, chain.GetLinks()[1].GetTo())
+
+	err = store.Store(makeLink(t, prev, types.WithIndex(4)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), store.Len())
+
+	// Pruning an already pruned index is a no-op.
+	err = store.Prune(1)
+	require.NoError(t, err)
+	require.Len(t, store.pruned, 2)
+
+	err = store.Prune(5)
+	require.EqualError(t, err, "cannot prune before 5 with 5 block(s)")
+}
+
 func TestObserver_NotifyCallback(t *testing.T) {
 	obs := &observer{
 		ch: make(chan types.BlockLink, 1),
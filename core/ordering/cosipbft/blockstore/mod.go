@@ -6,12 +6,18 @@
 // latest block. It is important to notice that a block is stored alongside the
 // link that has been created during the consensus.
 //
+// The blocks can be pruned to reclaim space, in which case only their forward
+// links are kept so that a chain remains verifiable from the genesis block.
+//
 // The tree cache stores the latest state of the tree, which is modified after
 // each new block.
 //
 // The genesis store allows to set a definitive genesis block and persist it so
 // that it can be reloaded later on.
 //
+// The transaction index maps the identifier of a transaction to the index of
+// the block that includes it.
+//
 // Documentation Last Review: 13.10.2020
 //
 package blockstore
@@ -28,6 +34,14 @@ import (
 // ErrNoBlock is the error message returned when the block is unknown.
 var ErrNoBlock = errors.New("no block")
 
+// ErrPruned is the error message returned when the block has been pruned from
+// the store.
+var ErrPruned = errors.New("block pruned")
+
+// ErrNoTransaction is the error message returned when the transaction is not
+// in the index.
+var ErrNoTransaction = errors.New("no transaction")
+
 // TreeCache is a cache to store a tree that needs to be accessed in different
 // places.
 type TreeCache interface {
@@ -82,6 +96,11 @@ type BlockStore interface {
 	// integrity of the last block from the genesis.
 	GetChain() (types.Chain, error)
 
+	// GetReducedChain returns the forward links of the blocks from the first
+	// index to the second one, both included. A forward link only contains the
+	// digests and the signatures, which is enough to verify a chain.
+	GetReducedChain(from, to uint64) ([]types.Link, error)
+
 	// Last must return the latest block link in the store.
 	Last() (types.BlockLink, error)
 
@@ -92,4 +111,25 @@ type BlockStore interface {
 	// WithTx returns a block store that is using the transaction to perform
 	// operations on the database.
 	WithTx(store.Transaction) BlockStore
+
+	// Prune must remove the blocks below the given index. The forward links of
+	// the pruned blocks are kept as checkpoints so that the chain can still be
+	// verified from the genesis. It must return an error if the latest block
+	// would be pruned.
+	Prune(beforeIndex uint64) error
+}
+
+// TxIndex is the interface to store and read the index of the block that
+// includes a transaction. It is left to the implementation to persist it.
+type TxIndex interface {
+	// Len must return the number of blocks that have been indexed, so that
+	// the missing ones can be indexed from the block store.
+	Len() uint64
+
+	// Index must store the index of the block for each of its transactions.
+	Index(types.Block) error
+
+	// Lookup must return the index of the block that includes the
+	// transaction, or an error wrapping ErrNoTransaction.
+	Lookup(id []byte) (uint64, error)
 }
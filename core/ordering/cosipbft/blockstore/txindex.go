@@ -0,0 +1,180 @@
+// This file contains the implementations of a transaction index. An in-memory
+// and a persistent implementation are available.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package blockstore
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/core/store/kv"
+	"golang.org/x/xerrors"
+)
+
+var txIndexBucket = []byte("blockstore-txindex")
+var txIndexMetaBucket = []byte("blockstore-txindex-meta")
+var txIndexLenKey = []byte("length")
+
+// cachedTxIndex is an in-memory transaction index.
+//
+// - implements blockstore.TxIndex
+type cachedTxIndex struct {
+	sync.Mutex
+
+	length  uint64
+	indices map[string]uint64
+}
+
+// NewTxIndex returns a new empty in-memory transaction index.
+func NewTxIndex() TxIndex {
+	return newCachedTxIndex()
+}
+
+func newCachedTxIndex() *cachedTxIndex {
+	return &cachedTxIndex{
+		indices: make(map[string]uint64),
+	}
+}
+
+// Len implements blockstore.TxIndex. It returns the number of blocks indexed.
+func (idx *cachedTxIndex) Len() uint64 {
+	idx.Lock()
+	defer idx.Unlock()
+
+	return idx.length
+}
+
+// Index implements blockstore.TxIndex. It stores the index of the block for
+// each of its transactions.
+func (idx *cachedTxIndex) Index(block types.Block) error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	idx.index(block)
+
+	return nil
+}
+
+func (idx *cachedTxIndex) index(block types.Block) {
+	for _, res := range block.GetData().GetTransactionResults() {
+		idx.indices[string(res.GetTransaction().GetID())] = block.GetIndex()
+	}
+
+	if block.GetIndex() >= idx.length {
+		idx.length = block.GetIndex() + 1
+	}
+}
+
+// Lookup implements blockstore.TxIndex. It returns the index of the block that
+// includes the transaction if it is known, otherwise an error.
+func (idx *cachedTxIndex) Lookup(id []byte) (uint64, error) {
+	idx.Lock()
+	defer idx.Unlock()
+
+	index, found := idx.indices[string(id)]
+	if !found {
+		return 0, xerrors.Errorf("transaction %#x: %w", id, ErrNoTransaction)
+	}
+
+	return index, nil
+}
+
+// DiskTxIndex is a transaction index that is persisted in a database. The index
+// is also kept in memory for fast access.
+//
+// - implements blockstore.TxIndex
+type DiskTxIndex struct {
+	*cachedTxIndex
+
+	db kv.DB
+}
+
+// NewTxDiskIndex creates a new transaction index that uses the given database.
+func NewTxDiskIndex(db kv.DB) DiskTxIndex {
+	return DiskTxIndex{
+		cachedTxIndex: newCachedTxIndex(),
+		db:            db,
+	}
+}
+
+// Load reads the database to rebuild the index in memory.
+func (idx DiskTxIndex) Load() error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	return idx.db.View(func(tx kv.ReadableTx) error {
+		meta := tx.GetBucket(txIndexMetaBucket)
+		if meta == nil {
+			// Nothing in the database, so the index is empty.
+			return nil
+		}
+
+		idx.length = binary.LittleEndian.Uint64(meta.Get(txIndexLenKey))
+
+		bucket := tx.GetBucket(txIndexBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(key, value []byte) error {
+			idx.indices[string(key)] = binary.LittleEndian.Uint64(value)
+			return nil
+		})
+	})
+}
+
+// Index implements blockstore.TxIndex. It writes the index of the block for
+// each of its transactions in the database, and then in memory.
+func (idx DiskTxIndex) Index(block types.Block) error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	length := idx.length
+	if block.GetIndex() >= length {
+		length = block.GetIndex() + 1
+	}
+
+	err := idx.db.Update(func(tx kv.WritableTx) error {
+		bucket, err := tx.GetBucketOrCreate(txIndexBucket)
+		if err != nil {
+			return xerrors.Errorf("bucket: %v", err)
+		}
+
+		value := make([]byte, 8)
+		binary.LittleEndian.PutUint64(value, block.GetIndex())
+
+		for _, res := range block.GetData().GetTransactionResults() {
+			err = bucket.Set(res.GetTransaction().GetID(), value)
+			if err != nil {
+				return xerrors.Errorf("while writing to bucket: %v", err)
+			}
+		}
+
+		meta, err := tx.GetBucketOrCreate(txIndexMetaBucket)
+		if err != nil {
+			return xerrors.Errorf("bucket: %v", err)
+		}
+
+		value = make([]byte, 8)
+		binary.LittleEndian.PutUint64(value, length)
+
+		err = meta.Set(txIndexLenKey, value)
+		if err != nil {
+			return xerrors.Errorf("while writing to bucket: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return xerrors.Errorf("store failed: %v", err)
+	}
+
+	idx.index(block)
+
+	return nil
+}
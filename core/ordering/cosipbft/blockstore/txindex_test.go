@@ -0,0 +1,128 @@
+package blockstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/core/store/kv"
+	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestCachedTxIndex_Index(t *testing.T) {
+	index := NewTxIndex()
+	require.Equal(t, uint64(0), index.Len())
+
+	err := index.Index(makeTxBlock(t, 0, 0, 1))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), index.Len())
+
+	err = index.Index(makeTxBlock(t, 2, 2))
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), index.Len())
+
+	// An older block does not decrease the length.
+	err = index.Index(makeTxBlock(t, 1, 3))
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), index.Len())
+}
+
+func TestCachedTxIndex_Lookup(t *testing.T) {
+	index := NewTxIndex()
+
+	err := index.Index(makeTxBlock(t, 4, 0, 1))
+	require.NoError(t, err)
+
+	num, err := index.Lookup(makeTxID(t, 1))
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), num)
+
+	_, err = index.Lookup([]byte{0xaa})
+	require.EqualError(t, err, "transaction 0xaa: no transaction")
+	require.True(t, errors.Is(err, ErrNoTransaction))
+}
+
+func TestDiskTxIndex_Load(t *testing.T) {
+	db, clean := makeDB(t)
+	defer clean()
+
+	index := NewTxDiskIndex(db)
+
+	err := index.Load()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), index.Len())
+
+	require.NoError(t, index.Index(makeTxBlock(t, 0, 0)))
+	require.NoError(t, index.Index(makeTxBlock(t, 1, 1, 2)))
+
+	// Reset the index.
+	index = NewTxDiskIndex(db)
+
+	err = index.Load()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), index.Len())
+
+	num, err := index.Lookup(makeTxID(t, 2))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), num)
+}
+
+func TestDiskTxIndex_Index(t *testing.T) {
+	db, clean := makeDB(t)
+	defer clean()
+
+	index := NewTxDiskIndex(db)
+
+	err := index.Index(makeTxBlock(t, 3, 0))
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), index.Len())
+
+	var data []byte
+	db.View(func(tx kv.ReadableTx) error {
+		data = tx.GetBucket(txIndexBucket).Get(makeTxID(t, 0))
+		return nil
+	})
+
+	require.Equal(t, []byte{3, 0, 0, 0, 0, 0, 0, 0}, data)
+
+	index = NewTxDiskIndex(badDB{})
+	err = index.Index(makeTxBlock(t, 0, 0))
+	require.EqualError(t, err, fake.Err("store failed: bucket"))
+	require.Equal(t, uint64(0), index.Len())
+
+	index.db = badDB{bucket: badBucket{}}
+	err = index.Index(makeTxBlock(t, 0, 0))
+	require.EqualError(t, err, fake.Err("store failed: while writing to bucket"))
+
+	_, err = index.Lookup(makeTxID(t, 0))
+	require.True(t, errors.Is(err, ErrNoTransaction))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeSignedTx(t *testing.T, nonce uint64) *signed.Transaction {
+	tx, err := signed.NewTransaction(nonce, fake.PublicKey{})
+	require.NoError(t, err)
+
+	return tx
+}
+
+func makeTxID(t *testing.T, nonce uint64) []byte {
+	return makeSignedTx(t, nonce).GetID()
+}
+
+func makeTxBlock(t *testing.T, index uint64, nonces ...uint64) types.Block {
+	results := make([]simple.TransactionResult, len(nonces))
+	for i, nonce := range nonces {
+		results[i] = simple.NewTransactionResult(makeSignedTx(t, nonce), true, "")
+	}
+
+	block, err := types.NewBlock(simple.NewResult(results), types.WithIndex(index))
+	require.NoError(t, err)
+
+	return block
+}
@@ -53,13 +53,21 @@ type SyncParam struct {
 	LinkFactory     otypes.LinkFactory
 	ChainFactory    otypes.ChainFactory
 	VerifierFactory crypto.VerifierFactory
+
+	// OnConflict is called, if defined, when a participant announces a valid
+	// chain that ends with a block different from the one stored locally at
+	// the same index.
+	OnConflict func(index uint64, local, remote otypes.Link)
 }
 
 // NewSynchronizer creates a new block synchronizer.
 func NewSynchronizer(param SyncParam) Synchronizer {
 	latest := param.Blocks.Len()
 
-	logger := dela.Logger.With().Str("addr", param.Mino.GetAddress().String()).Logger()
+	logger := dela.Logger.With().
+		Str(dela.SubsystemKey, "cosipbft").
+		Str("addr", param.Mino.GetAddress().String()).
+		Logger()
 
 	h := &handler{
 		latest:      &latest,
@@ -69,6 +77,7 @@ func NewSynchronizer(param SyncParam) Synchronizer {
 		blocks:      param.Blocks,
 		pbftsm:      param.PBFT,
 		verifierFac: param.VerifierFactory,
+		onConflict:  param.OnConflict,
 	}
 
 	fac := types.NewMessageFactory(param.LinkFactory, param.ChainFactory)
@@ -113,7 +122,7 @@ func (s defaultSync) Sync(ctx context.Context, players mino.Players, cfg Config)
 
 	// 1. Send the announcement message to everyone so that they can learn about
 	// the latest block.
-	chain, err := s.blocks.GetChain()
+	chain, err := s.announcedChain()
 	if err != nil {
 		return xerrors.Errorf("failed to read chain: %v", err)
 	}
@@ -127,6 +136,9 @@ func (s defaultSync) Sync(ctx context.Context, players mino.Players, cfg Config)
 
 	// 2. Wait for the hard synchronization to end. It can be interrupted with
 	// the context.
+	progress := newProgress(cfg.OnProgress)
+	defer progress.stop()
+
 	wg := sync.WaitGroup{}
 	wg.Add(1)
 	once := sync.Once{}
@@ -160,7 +172,7 @@ func (s defaultSync) Sync(ctx context.Context, players mino.Players, cfg Config)
 
 				soft[from] = struct{}{}
 
-				go s.syncNode(in.GetFrom(), sender, from)
+				go s.syncNode(in.GetFrom(), sender, from, progress)
 
 			case types.SyncAck:
 				soft[from] = struct{}{}
@@ -178,7 +190,29 @@ func (s defaultSync) Sync(ctx context.Context, players mino.Players, cfg Config)
 	return nil
 }
 
-func (s defaultSync) syncNode(from uint64, sender mino.Sender, to mino.Address) {
+// announcedChain returns the chain to the latest block where the previous
+// blocks are reduced to their forward links, which is enough for the
+// participants to verify the chain before they request the missing blocks.
+func (s defaultSync) announcedChain() (otypes.Chain, error) {
+	last, err := s.blocks.Last()
+	if err != nil {
+		return nil, xerrors.Errorf("reading last block: %v", err)
+	}
+
+	prevs := []otypes.Link{}
+
+	index := last.GetBlock().GetIndex()
+	if index > 0 {
+		prevs, err = s.blocks.GetReducedChain(0, index-1)
+		if err != nil {
+			return nil, xerrors.Errorf("reading links: %v", err)
+		}
+	}
+
+	return otypes.NewChain(last, prevs), nil
+}
+
+func (s defaultSync) syncNode(from uint64, sender mino.Sender, to mino.Address, p *progress) {
 	for i := from; i < s.blocks.Len(); i++ {
 		link, err := s.blocks.GetByIndex(i)
 		if err != nil {
@@ -196,9 +230,40 @@ func (s defaultSync) syncNode(from uint64, sender mino.Sender, to mino.Address)
 			s.logger.Err(err).Msgf("while synchronizing %v", to)
 			return
 		}
+
+		p.notify(to, i+1, s.blocks.Len())
 	}
 }
 
+// progress forwards the progress of the synchronization to the callback until
+// it is stopped, so that the callback is never called after Sync returns. The
+// blocks sent by a catch up that continues in background are not notified.
+type progress struct {
+	sync.Mutex
+
+	fn      func(to mino.Address, current, target uint64)
+	stopped bool
+}
+
+func newProgress(fn func(to mino.Address, current, target uint64)) *progress {
+	return &progress{fn: fn}
+}
+
+func (p *progress) notify(to mino.Address, current, target uint64) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.fn != nil && !p.stopped {
+		p.fn(to, current, target)
+	}
+}
+
+func (p *progress) stop() {
+	p.Lock()
+	p.stopped = true
+	p.Unlock()
+}
+
 // handler is a Mino handler for the synchronization messages.
 //
 // - implements mino.Handler
@@ -213,6 +278,7 @@ type handler struct {
 	genesis     blockstore.GenesisStore
 	pbftsm      pbft.StateMachine
 	verifierFac crypto.VerifierFactory
+	onConflict  func(index uint64, local, remote otypes.Link)
 }
 
 // Stream implements mino.Handler. It waits for an announcement message and then
@@ -241,7 +307,10 @@ func (h *handler) Stream(out mino.Sender, in mino.Receiver) error {
 
 	if m.GetLatestIndex() < h.blocks.Len() {
 		// The block storage has already all the block known so far so we can
-		// send the hard-sync acknowledgement.
+		// send the hard-sync acknowledgement, after checking that the announced
+		// block is the one known locally.
+		h.checkConflict(m.GetChain())
+
 		return h.ack(out, orch)
 	}
 
@@ -305,6 +374,39 @@ func (h *handler) waitAnnounce(ctx context.Context,
 	}
 }
 
+// checkConflict compares the last link of a verified chain with the one stored
+// at the same index. Both being signed by the collective authority, a
+// difference means that participants have signed two blocks for that index.
+func (h *handler) checkConflict(chain otypes.Chain) {
+	links := chain.GetLinks()
+	if len(links) == 0 {
+		return
+	}
+
+	remote := links[len(links)-1]
+	index := chain.GetBlock().GetIndex()
+
+	local, err := h.blocks.GetByIndex(index)
+	if err != nil {
+		h.logger.Warn().Err(err).Msg("failed to read local block")
+		return
+	}
+
+	if local.GetTo() == remote.GetTo() {
+		return
+	}
+
+	h.logger.Warn().
+		Uint64("index", index).
+		Stringer("local", local.GetTo()).
+		Stringer("remote", remote.GetTo()).
+		Msg("conflicting blocks detected")
+
+	if h.onConflict != nil {
+		h.onConflict(index, local.Reduce(), remote)
+	}
+}
+
 func (h *handler) ack(out mino.Sender, orch mino.Address) error {
 	// Send the acknowledgement to the orchestrator that the blocks have been
 	// caught up.
@@ -20,6 +20,7 @@ import (
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/mino/minoch"
+	"go.dedis.ch/dela/serde"
 )
 
 func TestDefaultSync_Basic(t *testing.T) {
@@ -77,6 +78,99 @@ func TestDefaultSync_Basic(t *testing.T) {
 	}
 }
 
+func TestDefaultSync_Progress(t *testing.T) {
+	num := 5
+
+	syncs, genesis, roster := makeNodes(t, 3)
+
+	storeBlocks(t, syncs[0].blocks, num, genesis.GetHash().Bytes()...)
+
+	type call struct {
+		current uint64
+		target  uint64
+	}
+
+	// The calls of the participants catching up are interleaved, hence they
+	// are grouped by address.
+	calls := map[string][]call{}
+
+	cfg := Config{
+		MinSoft: roster.Len(),
+		MinHard: roster.Len(),
+		OnProgress: func(to mino.Address, current, target uint64) {
+			calls[to.String()] = append(calls[to.String()], call{current: current, target: target})
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := syncs[0].Sync(ctx, roster, cfg)
+	require.NoError(t, err)
+
+	addrs := iter2arr(roster.AddressIterator())
+
+	require.Len(t, calls, 2)
+	for _, addr := range addrs[1:] {
+		// The last block might be sent after the participant acknowledged the
+		// chain, and thus after Sync returned.
+		require.GreaterOrEqual(t, len(calls[addr.String()]), num-1)
+		require.LessOrEqual(t, len(calls[addr.String()]), num)
+
+		for i, c := range calls[addr.String()] {
+			require.Equal(t, uint64(i+1), c.current)
+			require.Equal(t, uint64(num), c.target)
+		}
+	}
+
+	p := newProgress(cfg.OnProgress)
+	p.stop()
+
+	p.notify(addrs[1], 1, 1)
+	require.LessOrEqual(t, len(calls[addrs[1].String()]), num)
+}
+
+func TestDefaultSync_CatchUpInBackground(t *testing.T) {
+	rcvr := fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewSyncRequest(0)),
+		fake.NewRecvMsg(fake.NewAddress(1), types.NewSyncAck()),
+	)
+
+	sender := blockingSender{
+		sent:    make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	sync := defaultSync{
+		rpc:    blockingRPC{sender: sender, receiver: rcvr},
+		blocks: blockstore.NewInMemory(),
+	}
+
+	storeBlocks(t, sync.blocks, 1)
+
+	calls := 0
+
+	cfg := Config{
+		MinSoft: 2,
+		MinHard: 1,
+		OnProgress: func(mino.Address, uint64, uint64) {
+			calls++
+		},
+	}
+
+	// Sync returns as soon as the threshold is reached while the participant
+	// catching up is still waiting for its block.
+	err := sync.Sync(context.Background(), mino.NewAddresses(), cfg)
+	require.NoError(t, err)
+
+	close(sender.release)
+	<-sender.sent
+
+	// The block is not notified once Sync has returned.
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, 0, calls)
+}
+
 func TestDefaultSync_GetLatest(t *testing.T) {
 	latest := uint64(5)
 
@@ -108,9 +202,14 @@ func TestDefaultSync_Sync(t *testing.T) {
 	err := sync.Sync(ctx, mino.NewAddresses(), Config{MinSoft: 1, MinHard: 1})
 	require.NoError(t, err)
 
-	sync.blocks = badBlockStore{errChain: fake.GetError()}
+	sync.blocks = badBlockStore{errLast: fake.GetError()}
 	err = sync.Sync(ctx, mino.NewAddresses(), Config{})
-	require.EqualError(t, err, fake.Err("failed to read chain"))
+	require.EqualError(t, err, fake.Err("failed to read chain: reading last block"))
+
+	sync.blocks = badBlockStore{BlockStore: blockstore.NewInMemory(), errChain: fake.GetError()}
+	storeBlocks(t, sync.blocks, 2)
+	err = sync.Sync(ctx, mino.NewAddresses(), Config{})
+	require.EqualError(t, err, fake.Err("failed to read chain: reading links"))
 
 	sync.blocks = blockstore.NewInMemory()
 	storeBlocks(t, sync.blocks, 1)
@@ -142,12 +241,56 @@ func TestDefaultSync_Sync(t *testing.T) {
 
 	sync.logger = logger
 	sync.rpc = fake.NewStreamRPC(recv, sender)
-	sync.blocks = badBlockStore{}
+	sync.blocks = badBlockStore{BlockStore: blockstore.NewInMemory()}
+	storeBlocks(t, sync.blocks, 1)
 	err = sync.Sync(ctx, mino.NewAddresses(), Config{MinSoft: 1})
 	require.NoError(t, err)
 	wait(t)
 }
 
+func TestDefaultSync_AnnouncedChain(t *testing.T) {
+	syncs, genesis, _ := makeNodes(t, 1)
+
+	num := 5
+
+	storeBlocks(t, syncs[0].blocks, num, genesis.GetHash().Bytes()...)
+
+	reduced, err := syncs[0].announcedChain()
+	require.NoError(t, err)
+	require.Len(t, reduced.GetLinks(), num)
+
+	prevs := make([]otypes.Link, num-1)
+	for i := range prevs {
+		prevs[i], err = syncs[0].blocks.GetByIndex(uint64(i))
+		require.NoError(t, err)
+
+		// Only the latest block is announced in full.
+		_, isBlock := reduced.GetLinks()[i].(otypes.BlockLink)
+		require.False(t, isBlock)
+	}
+
+	last, err := syncs[0].blocks.Last()
+	require.NoError(t, err)
+
+	full := otypes.NewChain(last, prevs)
+
+	// The reduced chain must be verified the same way as the full one.
+	require.NoError(t, full.Verify(genesis, fake.VerifierFactory{}))
+	require.NoError(t, reduced.Verify(genesis, fake.VerifierFactory{}))
+
+	badFac := fake.NewVerifierFactory(fake.NewBadVerifier())
+	errFull := full.Verify(genesis, badFac)
+	require.Error(t, errFull)
+	require.EqualError(t, reduced.Verify(genesis, badFac), errFull.Error())
+
+	other, err := otypes.NewGenesis(genesis.GetRoster(), otypes.WithGenesisRoot(otypes.Digest{1}))
+	require.NoError(t, err)
+
+	errFull = full.Verify(other, fake.VerifierFactory{})
+	require.Error(t, errFull)
+	require.EqualError(t, reduced.Verify(other, fake.VerifierFactory{}), errFull.Error())
+}
+
 func TestDefaultSync_SyncNode(t *testing.T) {
 	sync := defaultSync{
 		blocks: blockstore.NewInMemory(),
@@ -158,7 +301,7 @@ func TestDefaultSync_SyncNode(t *testing.T) {
 	logger, check := fake.CheckLog("while synchronizing fake.Address[0]")
 
 	sync.logger = logger
-	sync.syncNode(0, fake.NewBadSender(), fake.NewAddress(0))
+	sync.syncNode(0, fake.NewBadSender(), fake.NewAddress(0), newProgress(nil))
 
 	check(t)
 }
@@ -248,6 +391,46 @@ func TestHandler_Stream(t *testing.T) {
 	require.EqualError(t, err, fake.Err("sending ack failed"))
 }
 
+func TestHandler_CheckConflict(t *testing.T) {
+	blocks := blockstore.NewInMemory()
+	storeBlocks(t, blocks, 2)
+
+	var conflicts []uint64
+
+	handler := &handler{
+		blocks: blocks,
+		onConflict: func(index uint64, local, remote otypes.Link) {
+			require.NotEqual(t, local.GetTo(), remote.GetTo())
+			conflicts = append(conflicts, index)
+		},
+	}
+
+	same, err := blocks.GetByIndex(1)
+	require.NoError(t, err)
+
+	handler.checkConflict(fakeChain{block: same.GetBlock(), links: []otypes.Link{same}})
+	require.Empty(t, conflicts)
+
+	block, err := otypes.NewBlock(simple.NewResult(nil), otypes.WithIndex(1),
+		otypes.WithTreeRoot(otypes.Digest{1}))
+	require.NoError(t, err)
+
+	other, err := otypes.NewBlockLink(otypes.Digest{}, block,
+		otypes.WithSignatures(fake.Signature{}, fake.Signature{}))
+	require.NoError(t, err)
+
+	handler.checkConflict(fakeChain{block: block, links: []otypes.Link{other}})
+	require.Equal(t, []uint64{1}, conflicts)
+
+	logger, check := fake.CheckLog("failed to read local block")
+
+	handler.logger = logger
+	handler.blocks = badBlockStore{}
+	handler.checkConflict(fakeChain{block: block, links: []otypes.Link{other}})
+	require.Len(t, conflicts, 1)
+	check(t)
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
@@ -332,9 +515,41 @@ func (sm testSM) CatchUp(link otypes.BlockLink) error {
 	return nil
 }
 
+// blockingSender is a sender that blocks the replies of a synchronization until
+// it is released.
+type blockingSender struct {
+	fake.Sender
+
+	sent    chan struct{}
+	release chan struct{}
+}
+
+func (s blockingSender) Send(msg serde.Message, addrs ...mino.Address) <-chan error {
+	_, ok := msg.(types.SyncReply)
+	if ok {
+		<-s.release
+		defer close(s.sent)
+	}
+
+	return s.Sender.Send(msg, addrs...)
+}
+
+// blockingRPC is an RPC that streams with a blocking sender.
+type blockingRPC struct {
+	mino.RPC
+
+	sender   blockingSender
+	receiver mino.Receiver
+}
+
+func (rpc blockingRPC) Stream(context.Context, mino.Players) (mino.Sender, mino.Receiver, error) {
+	return rpc.sender, rpc.receiver, nil
+}
+
 type badBlockStore struct {
 	blockstore.BlockStore
 
+	errLast  error
 	errChain error
 }
 
@@ -342,8 +557,20 @@ func (s badBlockStore) Len() uint64 {
 	return 5
 }
 
-func (s badBlockStore) GetChain() (otypes.Chain, error) {
-	return nil, s.errChain
+func (s badBlockStore) Last() (otypes.BlockLink, error) {
+	if s.errLast != nil {
+		return nil, s.errLast
+	}
+
+	return s.BlockStore.Last()
+}
+
+func (s badBlockStore) GetReducedChain(from, to uint64) ([]otypes.Link, error) {
+	if s.errChain != nil {
+		return nil, s.errChain
+	}
+
+	return s.BlockStore.GetReducedChain(from, to)
 }
 
 func (s badBlockStore) GetByIndex(index uint64) (otypes.BlockLink, error) {
@@ -354,9 +581,14 @@ type fakeChain struct {
 	otypes.Chain
 
 	block otypes.Block
+	links []otypes.Link
 	err   error
 }
 
+func (c fakeChain) GetLinks() []otypes.Link {
+	return c.links
+}
+
 func (c fakeChain) GetBlock() otypes.Block {
 	return c.block
 }
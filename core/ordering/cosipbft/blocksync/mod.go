@@ -23,6 +23,12 @@ type Config struct {
 	// MinHard is the number of participants that have hard-synchronized,
 	// meaning they have the latest block stored.
 	MinHard int
+
+	// OnProgress is called, if defined, every time a block is sent to a
+	// participant catching up, with its address, the number of blocks it has
+	// received so far and the number of blocks of the chain. It is never called
+	// after Sync returns.
+	OnProgress func(to mino.Address, current, target uint64)
 }
 
 // Synchronizer is an interface to synchronize a leader with the participants.
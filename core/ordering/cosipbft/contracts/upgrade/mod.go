@@ -0,0 +1,141 @@
+// Package upgrade implements a native smart contract to record the version of
+// the validation service that a chain uses.
+//
+// Documentation Last Review: 15.10.2026
+//
+package upgrade
+
+import (
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/core/access"
+	"go.dedis.ch/dela/core/execution"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/ordering/cosipbft/contracts/viewchange"
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/core/txn"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// ContractName is the name of the contract.
+	ContractName = "go.dedis.ch/dela.Upgrade"
+
+	// VersionArg is the key of the argument for the new version.
+	VersionArg = "upgrade:version"
+
+	messageOnlyOne        = "only one upgrade per block is allowed"
+	messageArgMissing     = "version not found in transaction"
+	messageStorageFailure = "storage failure"
+	messageUnauthorized   = "unauthorized identity"
+	messageUnknownVersion = "unknown version"
+)
+
+// Versions is the registry of the versions of the validation service that a
+// node can apply.
+type Versions interface {
+	// IsRegistered returns true if the version is registered.
+	IsRegistered(version string) bool
+}
+
+// RegisterContract registers the upgrade contract to the given execution
+// service.
+func RegisterContract(exec *native.Service, c Contract) {
+	exec.Set(ContractName, c)
+}
+
+// Manager is an extension of a normal transaction manager to help creating
+// upgrade ones.
+type Manager struct {
+	manager txn.Manager
+}
+
+// NewManager returns an upgrade manager from the transaction manager.
+func NewManager(mgr txn.Manager) Manager {
+	return Manager{
+		manager: mgr,
+	}
+}
+
+// Make creates a new transaction using the provided manager. It contains the
+// version of the validation service that the transaction should apply.
+func (mgr Manager) Make(version string) (txn.Transaction, error) {
+	tx, err := mgr.manager.Make(
+		txn.Arg{Key: native.ContractArg, Value: []byte(ContractName)},
+		txn.Arg{Key: VersionArg, Value: []byte(version)},
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("creating transaction: %v", err)
+	}
+
+	return tx, nil
+}
+
+// Contract is a contract to update the version of the validation service at a
+// given key in the storage. The identities allowed to update the roster are
+// the ones allowed to upgrade.
+//
+// - implements native.Contract
+type Contract struct {
+	versionKey []byte
+	accessKey  []byte
+	access     access.Service
+	versions   Versions
+}
+
+// NewContract creates a new upgrade contract that only accepts the versions of
+// the registry.
+func NewContract(vKey, aKey []byte, srvc access.Service, versions Versions) Contract {
+	return Contract{
+		versionKey: vKey,
+		accessKey:  aKey,
+		access:     srvc,
+		versions:   versions,
+	}
+}
+
+// Execute implements native.Contract. It looks for the version in the
+// transaction and writes it to the storage if it is registered, as the chain
+// could not validate the next blocks otherwise.
+func (c Contract) Execute(snap store.Snapshot, step execution.Step) error {
+	for _, tx := range step.Previous {
+		if string(tx.GetArg(native.ContractArg)) == ContractName {
+			return xerrors.New(messageOnlyOne)
+		}
+	}
+
+	version := step.Current.GetArg(VersionArg)
+	if len(version) == 0 {
+		return xerrors.New(messageArgMissing)
+	}
+
+	creds := viewchange.NewCreds(c.accessKey)
+
+	err := c.access.Match(snap, creds, step.Current.GetIdentity())
+	if err != nil {
+		reportErr(step.Current, xerrors.Errorf("access control: %v", err))
+
+		return xerrors.Errorf("%s: %v", messageUnauthorized, step.Current.GetIdentity())
+	}
+
+	if !c.versions.IsRegistered(string(version)) {
+		return xerrors.Errorf("%s: %s", messageUnknownVersion, version)
+	}
+
+	err = snap.Set(c.versionKey, version)
+	if err != nil {
+		reportErr(step.Current, xerrors.Errorf("writing store: %v", err))
+
+		return xerrors.New(messageStorageFailure)
+	}
+
+	return nil
+}
+
+// reportErr prints a log with the actual error while the transaction will
+// contain a simplified explanation.
+func reportErr(tx txn.Transaction, err error) {
+	dela.Logger.Warn().
+		Hex("ID", tx.GetID()).
+		Err(err).
+		Msg("transaction refused")
+}
@@ -0,0 +1,122 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/access"
+	"go.dedis.ch/dela/core/execution"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestRegisterContract(t *testing.T) {
+	srvc := native.NewExecution()
+
+	RegisterContract(srvc, Contract{})
+}
+
+func TestNewTransaction(t *testing.T) {
+	mgr := NewManager(signed.NewManager(fake.NewSigner(), nil))
+
+	tx, err := mgr.Make("v2")
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+	require.Equal(t, "v2", string(tx.GetArg(VersionArg)))
+
+	mgr.manager = badManager{}
+	_, err = mgr.Make("v2")
+	require.EqualError(t, err, fake.Err("creating transaction"))
+}
+
+func TestContract_Execute(t *testing.T) {
+	contract := NewContract([]byte("version"), []byte("access"), fakeAccess{}, fakeVersions{"v2"})
+
+	snap := &fakeStore{}
+	err := contract.Execute(snap, makeStep(t, "v2"))
+	require.NoError(t, err)
+	require.Equal(t, "v2", string(snap.value))
+
+	err = contract.Execute(snap, execution.Step{Previous: []txn.Transaction{makeTx(t, "")}})
+	require.EqualError(t, err, messageOnlyOne)
+
+	err = contract.Execute(snap, makeStep(t, ""))
+	require.EqualError(t, err, messageArgMissing)
+
+	err = contract.Execute(&fakeStore{errSet: fake.GetError()}, makeStep(t, "v2"))
+	require.EqualError(t, err, messageStorageFailure)
+
+	// A version that is not registered would halt the chain.
+	snap = &fakeStore{}
+	err = contract.Execute(snap, makeStep(t, "v3"))
+	require.EqualError(t, err, "unknown version: v3")
+	require.Nil(t, snap.value)
+
+	contract.access = fakeAccess{err: fake.GetError()}
+	err = contract.Execute(snap, makeStep(t, "v2"))
+	require.EqualError(t, err, "unauthorized identity: fake.PublicKey")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeStep(t *testing.T, arg string) execution.Step {
+	return execution.Step{Current: makeTx(t, arg)}
+}
+
+func makeTx(t *testing.T, arg string) txn.Transaction {
+	args := []signed.TransactionOption{
+		signed.WithArg(VersionArg, []byte(arg)),
+		signed.WithArg(native.ContractArg, []byte(ContractName)),
+	}
+
+	tx, err := signed.NewTransaction(0, fake.PublicKey{}, args...)
+	require.NoError(t, err)
+
+	return tx
+}
+
+type fakeStore struct {
+	store.Snapshot
+
+	value  []byte
+	errSet error
+}
+
+func (snap *fakeStore) Set(key, value []byte) error {
+	snap.value = value
+	return snap.errSet
+}
+
+type badManager struct {
+	txn.Manager
+}
+
+func (badManager) Make(opts ...txn.Arg) (txn.Transaction, error) {
+	return nil, fake.GetError()
+}
+
+type fakeVersions []string
+
+func (versions fakeVersions) IsRegistered(version string) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+type fakeAccess struct {
+	access.Service
+
+	err error
+}
+
+func (srvc fakeAccess) Match(store.Readable, access.Credential, ...access.Identity) error {
+	return srvc.err
+}
@@ -16,6 +16,7 @@ import (
 	"go.dedis.ch/dela/cli/node"
 	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
 	"go.dedis.ch/dela/core/ordering/cosipbft/contracts/viewchange"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/pool"
@@ -127,7 +128,43 @@ func (a exportAction) Execute(ctx node.Context) error {
 	return nil
 }
 
-// RosterAddAction is an action to require a roster change in the change by
+// FsckAction is an action to verify the integrity of the blocks stored by the
+// node. It only reads the blocks and can therefore be run on a live node.
+//
+// - implements node.ActionTemplate
+type fsckAction struct{}
+
+// Execute implements node.ActionTemplate. It goes through the chain of blocks
+// and reports the first one that is corrupted, if any.
+func (a fsckAction) Execute(ctx node.Context) error {
+	var blocks blockstore.BlockStore
+	err := ctx.Injector.Resolve(&blocks)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	var genstore blockstore.GenesisStore
+	err = ctx.Injector.Resolve(&genstore)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	genesis, err := genstore.Get()
+	if err != nil {
+		return xerrors.Errorf("failed to read genesis: %v", err)
+	}
+
+	err = blockstore.Check(blocks, genesis.GetHash())
+	if err != nil {
+		return xerrors.Errorf("integrity check failed: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "%d block(s) verified", blocks.Len())
+
+	return nil
+}
+
+// RosterAddAction is an action to require a roster change in the chain by
 // adding a new member.
 //
 // - implements node.ActionTemplate
@@ -136,13 +173,63 @@ type rosterAddAction struct{}
 // Execute implements node.ActionTemplate. It reads the new member and send a
 // transaction to require a roster change.
 func (rosterAddAction) Execute(ctx node.Context) error {
+	return executeRosterChange(ctx, addMember)
+}
+
+// RosterRemoveAction is an action to require a roster change in the chain by
+// removing an existing member.
+//
+// - implements node.ActionTemplate
+type rosterRemoveAction struct{}
+
+// Execute implements node.ActionTemplate. It reads the member to remove and
+// send a transaction to require a roster change.
+func (rosterRemoveAction) Execute(ctx node.Context) error {
+	return executeRosterChange(ctx, removeMember)
+}
+
+// rosterChange is the type of function that returns the change set to apply to
+// the roster for the given member.
+type rosterChange func(roster authority.Authority, addr mino.Address,
+	pubkey crypto.PublicKey) (authority.ChangeSet, error)
+
+func addMember(roster authority.Authority, addr mino.Address,
+	pubkey crypto.PublicKey) (authority.ChangeSet, error) {
+
+	cset := authority.NewChangeSet()
+	cset.Add(addr, pubkey)
+
+	return cset, nil
+}
+
+func removeMember(roster authority.Authority, addr mino.Address,
+	pubkey crypto.PublicKey) (authority.ChangeSet, error) {
+
+	_, index := roster.GetPublicKey(addr)
+	if index < 0 {
+		return nil, xerrors.Errorf("member %v is not in the roster", addr)
+	}
+
+	if roster.Len() <= 1 {
+		return nil, xerrors.New("cannot remove the last member")
+	}
+
+	cset := authority.NewChangeSet()
+	cset.Remove(uint(index))
+
+	return cset, nil
+}
+
+// executeRosterChange sends a transaction with the roster updated by the
+// change, and waits for it to be included if the wait flag is set.
+func executeRosterChange(ctx node.Context, change rosterChange) error {
 	var srvc Service
 	err := ctx.Injector.Resolve(&srvc)
 	if err != nil {
 		return xerrors.Errorf("injector: %v", err)
 	}
 
-	tx, err := prepareRosterTx(ctx, srvc)
+	tx, err := prepareRosterTx(ctx, srvc, change)
 	if err != nil {
 		return xerrors.Errorf("while preparing tx: %v", err)
 	}
@@ -197,7 +284,7 @@ func (rosterAddAction) Execute(ctx node.Context) error {
 	return nil
 }
 
-func prepareRosterTx(ctx node.Context, srvc Service) (txn.Transaction, error) {
+func prepareRosterTx(ctx node.Context, srvc Service, change rosterChange) (txn.Transaction, error) {
 	roster, err := srvc.GetRoster()
 	if err != nil {
 		return nil, xerrors.Errorf("failed to read roster: %v", err)
@@ -208,8 +295,10 @@ func prepareRosterTx(ctx node.Context, srvc Service) (txn.Transaction, error) {
 		return nil, xerrors.Errorf("failed to decode member: %v", err)
 	}
 
-	cset := authority.NewChangeSet()
-	cset.Add(addr, pubkey)
+	cset, err := change(roster, addr, pubkey)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid change: %v", err)
+	}
 
 	mgr, err := makeManager(ctx)
 	if err != nil {
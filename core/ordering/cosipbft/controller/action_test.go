@@ -12,10 +12,13 @@ import (
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/pool"
 	"go.dedis.ch/dela/core/txn/pool/mem"
 	"go.dedis.ch/dela/core/validation"
+	"go.dedis.ch/dela/core/validation/simple"
 	"go.dedis.ch/dela/cosi"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/internal/testing/fake"
@@ -77,6 +80,61 @@ func TestExportAction_Execute(t *testing.T) {
 	require.EqualError(t, err, fake.Err("failed to marshal public key"))
 }
 
+func TestFsckAction_Execute(t *testing.T) {
+	action := fsckAction{}
+
+	ctx := prepContext(nil)
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	genstore := blockstore.NewGenesisStore()
+	genesis, err := types.NewGenesis(authority.New(nil, nil))
+	require.NoError(t, err)
+	require.NoError(t, genstore.Set(genesis))
+
+	block, err := types.NewBlock(simple.NewResult(nil))
+	require.NoError(t, err)
+
+	link, err := types.NewBlockLink(genesis.GetHash(), block)
+	require.NoError(t, err)
+
+	blocks := blockstore.NewInMemory()
+	require.NoError(t, blocks.Store(link))
+
+	ctx.Injector.Inject(blocks)
+	ctx.Injector.Inject(genstore)
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "1 block(s) verified", buffer.String())
+
+	ctx.Injector.Inject(blockstore.NewGenesisStore())
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to read genesis: missing genesis block")
+
+	other, err := types.NewGenesis(authority.New(nil, nil), types.WithGenesisRoot(types.Digest{1}))
+	require.NoError(t, err)
+
+	genstore = blockstore.NewGenesisStore()
+	require.NoError(t, genstore.Set(other))
+	ctx.Injector.Inject(genstore)
+
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^integrity check failed: block 0 is corrupted", err.Error())
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'blockstore.BlockStore'")
+
+	ctx.Injector.Inject(blocks)
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'blockstore.GenesisStore'")
+}
+
 func TestRosterAddAction_Execute(t *testing.T) {
 	action := rosterAddAction{}
 
@@ -146,6 +204,49 @@ func TestRosterAddAction_Execute(t *testing.T) {
 	require.EqualError(t, err, "transaction not found after timeout")
 }
 
+func TestRosterRemoveAction_Execute(t *testing.T) {
+	action := rosterRemoveAction{}
+
+	roster := authority.New(
+		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		[]crypto.PublicKey{fake.PublicKey{}, fake.PublicKey{}},
+	)
+
+	ctx := prepContext(nil)
+	ctx.Flags.(node.FlagSet)["member"] = "YQ==:YQ=="
+	ctx.Flags.(node.FlagSet)["wait"] = float64(time.Second)
+	ctx.Injector.Inject(fakeService{roster: roster, events: []ordering.Event{
+		{Transactions: []validation.TransactionResult{fakeResult{}}},
+	}})
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+
+	ctx.Injector.Inject(fakeService{})
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "while preparing tx: invalid change: "+
+		"member fake.Address[0] is not in the roster")
+
+	ctx.Injector.Inject(fakeService{roster: roster.Take(mino.IndexFilter(0)).(authority.Authority)})
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "while preparing tx: invalid change: "+
+		"cannot remove the last member")
+}
+
+func TestRemoveMember(t *testing.T) {
+	roster := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	cset, err := removeMember(roster, fake.NewAddress(1), nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, cset.NumChanges())
+
+	next := roster.Apply(cset)
+	require.Equal(t, 2, next.Len())
+
+	_, index := next.GetPublicKey(fake.NewAddress(1))
+	require.Equal(t, -1, index)
+}
+
 func TestDecodeMember(t *testing.T) {
 	ctx := prepContext(nil)
 
@@ -192,10 +293,15 @@ type fakeService struct {
 	ordering.Service
 	calls  *fake.Call
 	events []ordering.Event
+	roster authority.Authority
 	err    error
 }
 
 func (s fakeService) GetRoster() (authority.Authority, error) {
+	if s.roster != nil {
+		return s.roster, s.err
+	}
+
 	return authority.New(nil, nil), s.err
 }
 
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"go.dedis.ch/dela/contracts/noop"
 	"go.dedis.ch/dela/contracts/value"
 	"go.dedis.ch/dela/crypto"
 
@@ -86,10 +87,14 @@ func (miniController) SetCommands(builder node.Builder) {
 	sub.SetDescription("Export the node information")
 	sub.SetAction(builder.MakeAction(exportAction{}))
 
-	sub = cmd.SetSubCommand("roster")
-	sub.SetDescription("Roster administration")
+	sub = cmd.SetSubCommand("fsck")
+	sub.SetDescription("Verify the integrity of the blocks stored on disk")
+	sub.SetAction(builder.MakeAction(fsckAction{}))
 
-	sub = sub.SetSubCommand("add")
+	roster := cmd.SetSubCommand("roster")
+	roster.SetDescription("Roster administration")
+
+	sub = roster.SetSubCommand("add")
 	sub.SetDescription("Add a member to the chain")
 	sub.SetFlags(
 		cli.StringFlag{
@@ -103,6 +108,21 @@ func (miniController) SetCommands(builder node.Builder) {
 		},
 	)
 	sub.SetAction(builder.MakeAction(rosterAddAction{}))
+
+	sub = roster.SetSubCommand("remove")
+	sub.SetDescription("Remove a member from the chain")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "member",
+			Required: true,
+			Usage:    "base64 description of the member to remove",
+		},
+		cli.DurationFlag{
+			Name:  "wait",
+			Usage: "wait for the transaction to be processed",
+		},
+	)
+	sub.SetAction(builder.MakeAction(rosterRemoveAction{}))
 }
 
 // OnStart implements node.Initializer. It starts the ordering components and
@@ -129,6 +149,7 @@ func (m miniController) OnStart(flags cli.Flags, inj node.Injector) error {
 	cosipbft.RegisterRosterContract(exec, rosterFac, access)
 
 	value.RegisterContract(exec, value.NewContract(valueAccessKey[:], access))
+	noop.RegisterContract(exec, noop.NewContract())
 
 	txFac := signed.NewTransactionFactory()
 	vs := simple.NewService(exec, txFac)
@@ -179,12 +200,26 @@ func (m miniController) OnStart(flags cli.Flags, inj node.Injector) error {
 		return xerrors.Errorf("failed to load blocks: %v", err)
 	}
 
-	srvc, err := cosipbft.NewService(param, cosipbft.WithGenesisStore(genstore), cosipbft.WithBlockStore(blocks))
+	txIndex := blockstore.NewTxDiskIndex(db)
+
+	err = txIndex.Load()
+	if err != nil {
+		return xerrors.Errorf("failed to load tx index: %v", err)
+	}
+
+	srvc, err := cosipbft.NewService(param, cosipbft.WithGenesisStore(genstore),
+		cosipbft.WithBlockStore(blocks), cosipbft.WithTxIndex(txIndex))
 	if err != nil {
 		return xerrors.Errorf("service: %v", err)
 	}
 
+	// The upgrade contract only accepts the versions registered to the
+	// service.
+	cosipbft.RegisterUpgradeContract(exec, access, srvc)
+
 	inj.Inject(srvc)
+	inj.Inject(blocks)
+	inj.Inject(genstore)
 	inj.Inject(cosi)
 	inj.Inject(pool)
 	inj.Inject(vs)
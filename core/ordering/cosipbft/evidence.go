@@ -0,0 +1,45 @@
+// This file contains the implementation of the equivocation evidences.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package cosipbft
+
+import (
+	"sync"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+)
+
+// Evidence is the proof that two different blocks have been collectively
+// signed for the same index. Both links hold the prepare and commit signatures
+// so that the participants responsible for the equivocation can be identified.
+type Evidence struct {
+	Index  uint64
+	Local  types.Link
+	Remote types.Link
+}
+
+// evidenceLog is a thread-safe list of the evidences observed by a node.
+type evidenceLog struct {
+	sync.Mutex
+	evidences []Evidence
+}
+
+func (l *evidenceLog) add(index uint64, local, remote types.Link) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.evidences = append(l.evidences, Evidence{
+		Index:  index,
+		Local:  local,
+		Remote: remote,
+	})
+}
+
+func (l *evidenceLog) list() []Evidence {
+	l.Lock()
+	defer l.Unlock()
+
+	return append([]Evidence{}, l.evidences...)
+}
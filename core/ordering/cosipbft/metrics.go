@@ -0,0 +1,34 @@
+// This file contains the definition of the metrics observed by the service.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package cosipbft
+
+import "time"
+
+// Metrics is the interface to observe the activity of the ordering service. The
+// implementation is called synchronously from the round loop and it should
+// therefore return quickly.
+type Metrics interface {
+	// ObserveBlockCommit is called by the leader when a block has been
+	// committed by the participants, with the number of transactions of the
+	// block and the time it took to create and propagate it.
+	ObserveBlockCommit(index uint64, txCount int, d time.Duration)
+
+	// ObserveViewChange is called when a view change succeeded, with the index
+	// of the new leader and the time it took since the round timeout.
+	ObserveViewChange(leader uint16, d time.Duration)
+}
+
+// noopMetrics is the default implementation of the metrics that ignores all the
+// observations.
+//
+// - implements cosipbft.Metrics
+type noopMetrics struct{}
+
+// ObserveBlockCommit implements cosipbft.Metrics. It does nothing.
+func (noopMetrics) ObserveBlockCommit(uint64, int, time.Duration) {}
+
+// ObserveViewChange implements cosipbft.Metrics. It does nothing.
+func (noopMetrics) ObserveViewChange(uint16, time.Duration) {}
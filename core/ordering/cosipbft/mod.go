@@ -31,12 +31,16 @@
 package cosipbft
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/core"
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/ordering"
@@ -71,9 +75,21 @@ const (
 	// RoundMaxWait is the maximum amount for the backoff.
 	RoundMaxWait = 5 * time.Minute
 
+	// TimeoutAlertThreshold is the default number of consecutive round
+	// timeouts before an alert is raised.
+	TimeoutAlertThreshold = 3
+
+	// MaxBlocksRange is the maximum number of blocks returned by a single
+	// range query.
+	MaxBlocksRange = 100
+
 	rpcName = "cosipbft"
 )
 
+// ErrAlreadySetup is the error returned when the service is set up again with a
+// different roster than the one of the existing genesis block.
+var ErrAlreadySetup = errors.New("genesis block is already set")
+
 // RegisterRosterContract registers the native smart contract to update the
 // roster to the given service.
 func RegisterRosterContract(exec *native.Service, rFac authority.Factory, srvc access.Service) {
@@ -94,6 +110,7 @@ type Service struct {
 	actor       cosi.Actor
 	val         validation.Service
 	verifierFac crypto.VerifierFactory
+	upgrades    *upgradableValidation
 
 	timeoutRound             time.Duration
 	timeoutRoundAfterFailure time.Duration
@@ -103,12 +120,59 @@ type Service struct {
 	closing     chan struct{}
 	closed      chan struct{}
 	failedRound bool
+
+	// timeouts is the number of consecutive rounds that reached the timeout.
+	timeouts       int
+	alertThreshold int
+	onAlert        TimeoutAlert
+
+	metrics      Metrics
+	txOrdering   TxOrdering
+	syncProgress SyncProgress
+	syncMinHard  int
+	syncMinSoft  int
+	gather       gatherConfig
+	txIndex      blockstore.TxIndex
+}
+
+// gatherConfig is the configuration of the gathering of the transactions for a
+// new block.
+type gatherConfig struct {
+	min  int
+	max  int
+	wait time.Duration
 }
 
+// TimeoutAlert is the type of callback invoked when the number of consecutive
+// round timeouts reaches the threshold. It is called with the number of
+// timeouts observed so far.
+type TimeoutAlert func(timeouts int)
+
+// SyncProgress is the type of callback invoked during the synchronization of a
+// round with the address of a participant, the number of blocks it has
+// received so far and the number of blocks of the chain.
+type SyncProgress func(to mino.Address, current, target uint64)
+
+// TxOrdering is the type of function that sorts the transactions of a block
+// before they are validated. The order must only depend on the transactions so
+// that every node produces the same block.
+type TxOrdering func(txs []txn.Transaction)
+
 type serviceTemplate struct {
-	hashFac crypto.HashFactory
-	blocks  blockstore.BlockStore
-	genesis blockstore.GenesisStore
+	hashFac        crypto.HashFactory
+	blocks         blockstore.BlockStore
+	genesis        blockstore.GenesisStore
+	roundTimeout   time.Duration
+	alertThreshold int
+	onAlert        TimeoutAlert
+	metrics        Metrics
+	txOrdering     TxOrdering
+	leaderPolicy   pbft.LeaderPolicy
+	syncProgress   SyncProgress
+	syncMinHard    int
+	syncMinSoft    int
+	gather         gatherConfig
+	txIndex        blockstore.TxIndex
 }
 
 // ServiceOption is the type of option to set some fields of the service.
@@ -135,6 +199,92 @@ func WithHashFactory(fac crypto.HashFactory) ServiceOption {
 	}
 }
 
+// WithRoundTimeout is an option to set the maximum amount of time a follower
+// waits for a block before it starts a view change, which is also the maximum
+// amount of time of a round and of a view change. It defaults to RoundTimeout
+// and must be positive.
+func WithRoundTimeout(timeout time.Duration) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.roundTimeout = timeout
+	}
+}
+
+// WithTimeoutAlert is an option to set the number of consecutive round timeouts
+// after which the alert is raised. The callback is invoked once every time the
+// threshold is reached, and the counter is reset by a successful round.
+func WithTimeoutAlert(threshold int, fn TimeoutAlert) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.alertThreshold = threshold
+		tmpl.onAlert = fn
+	}
+}
+
+// WithMetrics is an option to set the implementation observing the block
+// commits and the view changes. The observations are ignored by default.
+func WithMetrics(metrics Metrics) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.metrics = metrics
+	}
+}
+
+// WithTxOrdering is an option to set the function sorting the transactions of a
+// new block. By default, the transactions are sorted by identity, then by nonce
+// and finally by identifier.
+func WithTxOrdering(fn TxOrdering) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.txOrdering = fn
+	}
+}
+
+// WithLeaderPolicy is an option to set the policy that selects the leader of a
+// round. By default, the leader stays the same until a view change happens.
+func WithLeaderPolicy(policy pbft.LeaderPolicy) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.leaderPolicy = policy
+	}
+}
+
+// WithSyncProgress is an option to set the callback invoked while the leader of
+// a round sends the missing blocks to the participants catching up.
+func WithSyncProgress(fn SyncProgress) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.syncProgress = fn
+	}
+}
+
+// WithSyncThreshold is an option to set the number of participants that must
+// be hard-synchronized and soft-synchronized before a round can start. A hard
+// threshold of zero means the Byzantine quorum of the roster, which is the
+// default. The soft threshold must not be above the hard one, and the hard one
+// must not be above the size of the roster when the round starts.
+func WithSyncThreshold(minHard, minSoft int) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.syncMinHard = minHard
+		tmpl.syncMinSoft = minSoft
+	}
+}
+
+// WithGatherConfig is an option to set how the leader gathers the transactions
+// of a block. It waits up to the given duration for at least min transactions
+// to be available in the pool, and then for at least one, and it includes at
+// most max transactions in the block. A max of zero means no limit. The wait is
+// always bounded by the round timeout. By default, a block is created as soon
+// as one transaction is available.
+func WithGatherConfig(min, max int, wait time.Duration) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.gather = gatherConfig{min: min, max: max, wait: wait}
+	}
+}
+
+// WithTxIndex is an option to set the index of the transactions, which is used
+// to look up the block of a transaction. By default, the index is kept in
+// memory and rebuilt from the blocks on start.
+func WithTxIndex(index blockstore.TxIndex) ServiceOption {
+	return func(tmpl *serviceTemplate) {
+		tmpl.txIndex = index
+	}
+}
+
 // ServiceParam is the different components to provide to the service. All the
 // fields are mandatory and it will panic if any is nil.
 type ServiceParam struct {
@@ -150,15 +300,40 @@ type ServiceParam struct {
 // NewService starts a new ordering service.
 func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 	tmpl := serviceTemplate{
-		hashFac: crypto.NewSha256Factory(),
-		genesis: blockstore.NewGenesisStore(),
-		blocks:  blockstore.NewInMemory(),
+		hashFac:        crypto.NewSha256Factory(),
+		roundTimeout:   RoundTimeout,
+		genesis:        blockstore.NewGenesisStore(),
+		blocks:         blockstore.NewInMemory(),
+		alertThreshold: TimeoutAlertThreshold,
+		metrics:        noopMetrics{},
+		txOrdering:     sortTransactions,
+		leaderPolicy:   pbft.NewStickyPolicy(),
+		txIndex:        blockstore.NewTxIndex(),
 	}
 
 	for _, opt := range opts {
 		opt(&tmpl)
 	}
 
+	if tmpl.syncMinHard < 0 || tmpl.syncMinSoft < 0 {
+		return nil, xerrors.New("invalid sync threshold: negative value")
+	}
+
+	if tmpl.syncMinHard > 0 && tmpl.syncMinSoft > tmpl.syncMinHard {
+		return nil, xerrors.Errorf("invalid sync threshold: soft %d > hard %d",
+			tmpl.syncMinSoft, tmpl.syncMinHard)
+	}
+
+	if tmpl.gather.max > 0 && tmpl.gather.min > tmpl.gather.max {
+		return nil, xerrors.Errorf("invalid gather config: min %d > max %d",
+			tmpl.gather.min, tmpl.gather.max)
+	}
+
+	if tmpl.roundTimeout <= 0 {
+		return nil, xerrors.Errorf("invalid round timeout: %v is not positive",
+			tmpl.roundTimeout)
+	}
+
 	proc := newProcessor()
 	proc.hashFactory = tmpl.hashFac
 	proc.blocks = tmpl.blocks
@@ -167,11 +342,18 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 	proc.rosterFac = authority.NewFactory(param.Mino.GetAddressFactory(), param.Cosi.GetPublicKeyFactory())
 	proc.tree = blockstore.NewTreeCache(param.Tree)
 	proc.access = param.Access
-	proc.logger = dela.Logger.With().Str("addr", param.Mino.GetAddress().String()).Logger()
+	proc.logger = dela.Logger.With().
+		Str(dela.SubsystemKey, "cosipbft").
+		Str("addr", param.Mino.GetAddress().String()).
+		Logger()
+
+	// The validation service is shared by the components so that an upgrade
+	// applies to all of them at the same block.
+	upgrades := newUpgradableValidation(param.Validation)
 
 	pcparam := pbft.StateMachineParam{
 		Logger:          proc.logger,
-		Validation:      param.Validation,
+		Validation:      upgrades,
 		Signer:          param.Cosi.GetSigner(),
 		VerifierFactory: param.Cosi.GetVerifierFactory(),
 		Blocks:          tmpl.blocks,
@@ -179,6 +361,8 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 		Tree:            proc.tree,
 		AuthorityReader: proc.readRoster,
 		DB:              param.DB,
+		LeaderPolicy:    tmpl.leaderPolicy,
+		OnConflict:      proc.reportConflict,
 	}
 
 	proc.pbftsm = pbft.NewStateMachine(pcparam)
@@ -196,6 +380,7 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 		LinkFactory:     linkFac,
 		ChainFactory:    chainFac,
 		VerifierFactory: param.Cosi.GetVerifierFactory(),
+		OnConflict:      proc.reportConflict,
 	}
 
 	blocksync := blocksync.NewSynchronizer(syncparam)
@@ -222,19 +407,36 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 		me:                       param.Mino.GetAddress(),
 		rpc:                      mino.MustCreateRPC(param.Mino, rpcName, proc, fac),
 		actor:                    actor,
-		val:                      param.Validation,
+		val:                      upgrades,
 		verifierFac:              param.Cosi.GetVerifierFactory(),
-		timeoutRound:             RoundTimeout,
-		timeoutRoundAfterFailure: RoundTimeout,
-		timeoutViewchange:        RoundTimeout,
+		upgrades:                 upgrades,
+		timeoutRound:             tmpl.roundTimeout,
+		timeoutRoundAfterFailure: tmpl.roundTimeout,
+		timeoutViewchange:        tmpl.roundTimeout,
 		events:                   make(chan ordering.Event, 1),
 		closing:                  make(chan struct{}),
 		closed:                   make(chan struct{}),
+		alertThreshold:           tmpl.alertThreshold,
+		onAlert:                  tmpl.onAlert,
+		metrics:                  tmpl.metrics,
+		txOrdering:               tmpl.txOrdering,
+		syncProgress:             tmpl.syncProgress,
+		syncMinHard:              tmpl.syncMinHard,
+		syncMinSoft:              tmpl.syncMinSoft,
+		gather:                   tmpl.gather,
+		txIndex:                  tmpl.txIndex,
 	}
 
 	// Pool will filter the transaction that are already accepted by this
 	// service.
-	param.Pool.AddFilter(poolFilter{tree: proc.tree, srvc: param.Validation})
+	param.Pool.AddFilter(poolFilter{tree: proc.tree, srvc: upgrades})
+
+	// The blocks stored while the index was not updated, or all of them when
+	// the index is not persisted, are indexed before new ones are watched.
+	err = s.rebuildTxIndex()
+	if err != nil {
+		return nil, xerrors.Errorf("rebuilding tx index: %v", err)
+	}
 
 	go s.main()
 
@@ -249,11 +451,24 @@ func NewService(param ServiceParam, opts ...ServiceOption) (*Service, error) {
 	return s, nil
 }
 
-// Setup creates a genesis block and sends it to the collective authority.
+// Setup creates a genesis block and sends it to the collective authority. It
+// succeeds as long as a Byzantine threshold of the members acknowledges the
+// genesis block, the others being expected to catch up later. When the genesis
+// block already exists, it is sent again if the roster is the same so that a
+// failed setup can be retried, otherwise it returns ErrAlreadySetup.
 func (s *Service) Setup(ctx context.Context, ca crypto.CollectiveAuthority) error {
-	err := s.storeGenesis(authority.FromAuthority(ca), nil)
-	if err != nil {
-		return xerrors.Errorf("creating genesis: %v", err)
+	roster := authority.FromAuthority(ca)
+
+	if s.genesis.Exists() {
+		err := s.checkSetup(roster)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := s.storeGenesis(roster, nil)
+		if err != nil {
+			return xerrors.Errorf("creating genesis: %v", err)
+		}
 	}
 
 	genesis, err := s.genesis.Get()
@@ -266,13 +481,33 @@ func (s *Service) Setup(ctx context.Context, ca crypto.CollectiveAuthority) erro
 		return xerrors.Errorf("sending genesis: %v", err)
 	}
 
+	// The members acknowledge the genesis block by not returning an error.
+	missing := []string{}
+
 	for resp := range resps {
 		_, err := resp.GetMessageOrError()
 		if err != nil {
-			return xerrors.Errorf("one request failed: %v", err)
+			s.logger.Warn().Err(err).
+				Stringer("from", resp.GetFrom()).
+				Msg("genesis not acknowledged")
+
+			missing = append(missing, resp.GetFrom().String())
 		}
 	}
 
+	min := threshold.ByzantineThreshold(ca.Len())
+
+	if ca.Len()-len(missing) < min {
+		return xerrors.Errorf("only %d acknowledgements of the required %d "+
+			"out of %d members, missing %v", ca.Len()-len(missing), min, ca.Len(), missing)
+	}
+
+	if len(missing) > 0 {
+		s.logger.Warn().
+			Strs("missing", missing).
+			Msg("some members will catch up the genesis later")
+	}
+
 	s.logger.Info().
 		Int("roster", ca.Len()).
 		Stringer("digest", genesis.GetHash()).
@@ -281,20 +516,49 @@ func (s *Service) Setup(ctx context.Context, ca crypto.CollectiveAuthority) erro
 	return nil
 }
 
+// checkSetup compares the roster of the existing genesis block with the given
+// one and returns an error if they differ.
+func (s *Service) checkSetup(roster authority.Authority) error {
+	genesis, err := s.genesis.Get()
+	if err != nil {
+		return xerrors.Errorf("failed to read genesis: %v", err)
+	}
+
+	var expected, actual bytes.Buffer
+
+	err = genesis.GetRoster().Fingerprint(&expected)
+	if err != nil {
+		return xerrors.Errorf("failed to fingerprint genesis roster: %v", err)
+	}
+
+	err = roster.Fingerprint(&actual)
+	if err != nil {
+		return xerrors.Errorf("failed to fingerprint roster: %v", err)
+	}
+
+	if !bytes.Equal(expected.Bytes(), actual.Bytes()) {
+		return xerrors.Errorf("mismatch roster: %w", ErrAlreadySetup)
+	}
+
+	s.logger.Info().
+		Stringer("digest", genesis.GetHash()).
+		Msg("chain already set up with the same roster, sending genesis again")
+
+	return nil
+}
+
 // GetProof implements ordering.Service. It returns the proof of absence or
 // inclusion for the latest block. The proof integrity is not verified as this
 // is assumed the node is acting correctly so the data is anyway consistent. The
 // proof must be verified by the caller when leaving the trusted environment,
 // for instance when the proof is sent over the network.
+//
+// The proofs are cached for the latest block so that repeated reads of the
+// same key do not compute the path again.
 func (s *Service) GetProof(key []byte) (ordering.Proof, error) {
 	tree, unlock := s.tree.GetWithLock()
 	defer unlock()
 
-	path, err := tree.GetPath(key)
-	if err != nil {
-		return nil, xerrors.Errorf("reading path: %v", err)
-	}
-
 	// The chain is fetched while having the lock of the tree cache so that
 	// there is no race between the two stores when finalizing a block.
 	chain, err := s.blocks.GetChain()
@@ -302,7 +566,23 @@ func (s *Service) GetProof(key []byte) (ordering.Proof, error) {
 		return nil, xerrors.Errorf("reading chain: %v", err)
 	}
 
-	return newProof(path, chain), nil
+	latest := chain.GetBlock().GetHash()
+
+	proof, found := s.proofs.get(latest, key)
+	if found {
+		return proof, nil
+	}
+
+	path, err := tree.GetPath(key)
+	if err != nil {
+		return nil, xerrors.Errorf("reading path: %v", err)
+	}
+
+	proof = newProof(path, chain)
+
+	s.proofs.add(latest, key, proof)
+
+	return proof, nil
 }
 
 // GetStore implements ordering.Service. It returns the current tree as a
@@ -316,6 +596,174 @@ func (s *Service) GetRoster() (authority.Authority, error) {
 	return s.getCurrentRoster()
 }
 
+// GetEquivocations returns the evidences of the blocks that have been signed
+// twice for the same index, as observed by this node.
+func (s *Service) GetEquivocations() []Evidence {
+	return s.evidences.list()
+}
+
+// Status returns the current status of the service.
+func (s *Service) Status() ServiceStatus {
+	length := s.blocks.Len()
+
+	status := ServiceStatus{
+		HasGenesis: s.genesis.Exists(),
+		LastIndex:  int64(length) - 1,
+		Syncing:    s.sync.GetLatest() > length,
+	}
+
+	if status.HasGenesis {
+		leader, err := s.pbftsm.GetLeader()
+		status.IsLeader = err == nil && s.me.Equal(leader)
+	}
+
+	return status
+}
+
+// GetBlock returns the committed block at the given index. It returns an error
+// if the genesis block is not set or if the block does not exist.
+func (s *Service) GetBlock(index uint64) (types.Block, error) {
+	if !s.genesis.Exists() {
+		return types.Block{}, xerrors.New("genesis block is not set")
+	}
+
+	link, err := s.blocks.GetByIndex(index)
+	if err != nil {
+		return types.Block{}, xerrors.Errorf("reading block %d: %w", index, err)
+	}
+
+	return link.GetBlock(), nil
+}
+
+// GetBlocks returns the committed blocks from the index "from" to the index
+// "to", both included, in order. It returns an error if the range is inverted,
+// if it is larger than MaxBlocksRange, or if "to" is after the last block.
+func (s *Service) GetBlocks(from, to uint64) ([]types.Block, error) {
+	if !s.genesis.Exists() {
+		return nil, xerrors.New("genesis block is not set")
+	}
+
+	if from > to {
+		return nil, xerrors.Errorf("invalid range: %d > %d", from, to)
+	}
+
+	if to-from >= MaxBlocksRange {
+		return nil, xerrors.Errorf("range of %d blocks exceeds the limit of %d",
+			to-from+1, MaxBlocksRange)
+	}
+
+	length := s.blocks.Len()
+	if to >= length {
+		return nil, xerrors.Errorf("block %d is after the head %d: %w",
+			to, int64(length)-1, blockstore.ErrNoBlock)
+	}
+
+	blocks := make([]types.Block, 0, to-from+1)
+
+	for index := from; index <= to; index++ {
+		link, err := s.blocks.GetByIndex(index)
+		if err != nil {
+			return nil, xerrors.Errorf("reading block %d: %w", index, err)
+		}
+
+		blocks = append(blocks, link.GetBlock())
+	}
+
+	return blocks, nil
+}
+
+// GetBlockByHash returns the committed block with the given digest. It returns
+// an error if the genesis block is not set or if the block does not exist.
+func (s *Service) GetBlockByHash(id types.Digest) (types.Block, error) {
+	if !s.genesis.Exists() {
+		return types.Block{}, xerrors.New("genesis block is not set")
+	}
+
+	link, err := s.blocks.Get(id)
+	if err != nil {
+		return types.Block{}, xerrors.Errorf("reading block '%v': %w", id, err)
+	}
+
+	return link.GetBlock(), nil
+}
+
+// GetVerifiableBlock returns the chain of links from the genesis block to the
+// block at the given index. A client knowing the genesis block can verify the
+// block with the collective signatures of the links.
+func (s *Service) GetVerifiableBlock(index uint64) (types.Chain, error) {
+	if !s.genesis.Exists() {
+		return nil, xerrors.New("genesis block is not set")
+	}
+
+	chain, err := s.blocks.GetChain()
+	if err != nil {
+		return nil, xerrors.Errorf("reading chain: %v", err)
+	}
+
+	links := chain.GetLinks()
+	if index >= uint64(len(links)) {
+		return nil, xerrors.Errorf("block %d not found: %w", index, blockstore.ErrNoBlock)
+	}
+
+	link, err := s.blocks.GetByIndex(index)
+	if err != nil {
+		return nil, xerrors.Errorf("reading block %d: %w", index, err)
+	}
+
+	return types.NewChain(link, links[:index]), nil
+}
+
+// Prune removes the blocks below the given index from the block store. Only
+// their forward links are kept so that the chain can still be verified. It
+// refuses to prune past the last committed block.
+func (s *Service) Prune(beforeIndex uint64) error {
+	last, err := s.blocks.Last()
+	if err != nil {
+		return xerrors.Errorf("failed to read last block: %v", err)
+	}
+
+	index := last.GetBlock().GetIndex()
+	if beforeIndex > index {
+		return xerrors.Errorf("cannot prune past the last committed block %d", index)
+	}
+
+	err = s.blocks.Prune(beforeIndex)
+	if err != nil {
+		return xerrors.Errorf("pruning failed: %v", err)
+	}
+
+	return nil
+}
+
+// TriggerViewChange expires the current view and broadcasts the view message
+// to the roster without waiting for the round timeout, so that an operator can
+// replace a leader that is alive but misbehaving. It returns an error when the
+// node is the leader of the round, or when the view change does not succeed.
+func (s *Service) TriggerViewChange() error {
+	leader, err := s.pbftsm.GetLeader()
+	if err != nil {
+		return xerrors.Errorf("reading leader: %v", err)
+	}
+
+	if s.me.Equal(leader) {
+		return xerrors.New("node is the leader of the round")
+	}
+
+	roster, err := s.getCurrentRoster()
+	if err != nil {
+		return xerrors.Errorf("reading roster: %v", err)
+	}
+
+	s.logger.Warn().Stringer("leader", leader).Msg("view change triggered")
+
+	err = s.viewChange(context.Background(), roster, time.Now())
+	if err != nil {
+		return xerrors.Errorf("view change failed: %v", err)
+	}
+
+	return nil
+}
+
 // Watch implements ordering.Service. It returns a channel that will be
 // populated with new incoming blocks and some information about them. The
 // channel must be listened at all time and the context must be closed when
@@ -323,15 +771,136 @@ func (s *Service) GetRoster() (authority.Authority, error) {
 func (s *Service) Watch(ctx context.Context) <-chan ordering.Event {
 	obs := observer{ch: make(chan ordering.Event, 1)}
 
+	s.observe(ctx, obs, obs.ch)
+
+	return obs.ch
+}
+
+// WatchFilter returns a channel that will be populated with the new incoming
+// blocks for which the filter returns true. The same constraints as Watch
+// apply, and the filter is called synchronously for every block.
+func (s *Service) WatchFilter(ctx context.Context, filter func(ordering.Event) bool) <-chan ordering.Event {
+	obs := &filterObserver{
+		observer: observer{ch: make(chan ordering.Event, 1)},
+		filter:   filter,
+	}
+
+	s.observe(ctx, obs, obs.ch)
+
+	return obs.ch
+}
+
+// observe adds the observer to the watcher until the context is done, and then
+// closes the channel of the observer.
+func (s *Service) observe(ctx context.Context, obs core.Observer, ch chan ordering.Event) {
 	s.watcher.Add(obs)
 
 	go func() {
 		<-ctx.Done()
 		s.watcher.Remove(obs)
-		close(obs.ch)
+		close(ch)
 	}()
+}
 
-	return obs.ch
+// TransactionCallback is the type of callback invoked when a transaction is
+// included in a block, with the result and the index of the block.
+type TransactionCallback func(res validation.TransactionResult, index uint64)
+
+// OnTransaction registers a callback that is invoked once when the transaction
+// with the given identifier is included in a block. The callback is dropped
+// without being invoked when the context is done, which allows the caller to
+// set a timeout or to cancel the registration.
+func (s *Service) OnTransaction(ctx context.Context, id []byte, cb TransactionCallback) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	events := s.Watch(ctx)
+
+	go func() {
+		done := false
+
+		for event := range events {
+			if done {
+				// The channel is drained until it is closed so that the
+				// watcher is never blocked.
+				continue
+			}
+
+			for _, res := range event.Transactions {
+				if bytes.Equal(res.GetTransaction().GetID(), id) {
+					cb(res, event.Index)
+					done = true
+					cancel()
+					break
+				}
+			}
+		}
+	}()
+}
+
+// WaitForTx blocks until the transaction with the given identifier is included
+// in a block and returns its result, which tells if it has been accepted or
+// rejected. The index of the transactions is read after the subscription, as a
+// block is indexed before it is notified, so that a transaction committed in
+// the meantime is not missed. It returns an error when the context is done
+// before the transaction is found.
+func (s *Service) WaitForTx(ctx context.Context, id []byte) (validation.TransactionResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	events := s.Watch(ctx)
+
+	defer func() {
+		cancel()
+
+		// The channel is drained until it is closed so that the watcher is
+		// never blocked.
+		go func() {
+			for range events {
+			}
+		}()
+	}()
+
+	_, res, err := s.LookupTx(id)
+	if err == nil {
+		return res, nil
+	}
+
+	if !errors.Is(err, blockstore.ErrNoTransaction) {
+		return nil, xerrors.Errorf("failed to look up transaction: %v", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, xerrors.Errorf("transaction %#x not found: %w", id, ctx.Err())
+		case event := <-events:
+			res := findTx(event.Transactions, id)
+			if res != nil {
+				return res, nil
+			}
+		}
+	}
+}
+
+// LookupTx returns the index of the block that includes the transaction with
+// the given identifier, and its result. It returns an error wrapping
+// blockstore.ErrNoTransaction when the transaction is unknown.
+func (s *Service) LookupTx(id []byte) (uint64, validation.TransactionResult, error) {
+	index, err := s.txIndex.Lookup(id)
+	if err != nil {
+		return 0, nil, xerrors.Errorf("lookup failed: %w", err)
+	}
+
+	link, err := s.blocks.GetByIndex(index)
+	if err != nil {
+		return 0, nil, xerrors.Errorf("reading block %d: %v", index, err)
+	}
+
+	res := findTx(link.GetBlock().GetData().GetTransactionResults(), id)
+	if res == nil {
+		return 0, nil, xerrors.Errorf("transaction %#x missing in block %d", id, index)
+	}
+
+	return index, res, nil
 }
 
 // Close implements ordering.Service. It gracefully closes the service. It will
@@ -360,34 +929,69 @@ func (s *Service) watchBlocks() {
 			s.pool.Remove(res.GetTransaction())
 		}
 
-		// 2. Update the current membership.
+		// 2. Drop the proofs of the previous block.
+		s.proofs.purge()
+
+		// 3. Update the current membership.
 		err := s.refreshRoster()
 		if err != nil {
 			s.logger.Err(err).Msg("roster refresh failed")
 		}
 
+		// 4. Index the transactions of the block.
+		err = s.txIndex.Index(link.GetBlock())
+		if err != nil {
+			s.logger.Err(err).Msg("transaction indexing failed")
+		}
+
+		results := link.GetBlock().GetData().GetTransactionResults()
+
 		event := ordering.Event{
 			Index:        link.GetBlock().GetIndex(),
-			Transactions: link.GetBlock().GetData().GetTransactionResults(),
+			Transactions: results,
+			Rejected:     filterRejected(results),
 		}
 
-		// 3. Notify the main loop that a new block has been created, but ignore
+		// 5. Notify the main loop that a new block has been created, but ignore
 		// if the channel is busy.
 		select {
 		case s.events <- event:
 		default:
 		}
 
-		// 4. Notify the new block to potential listeners.
+		// 6. Notify the new block to potential listeners.
 		s.watcher.Notify(event)
 
 		s.logger.Info().
 			Uint64("index", link.GetBlock().GetIndex()).
 			Stringer("root", link.GetBlock().GetTreeRoot()).
+			Int("rejected", len(event.Rejected)).
 			Msg("block event")
 	}
 }
 
+// rebuildTxIndex indexes the blocks of the store that are missing in the index
+// of the transactions. Pruned blocks are skipped.
+func (s *Service) rebuildTxIndex() error {
+	for i := s.txIndex.Len(); i < s.blocks.Len(); i++ {
+		link, err := s.blocks.GetByIndex(i)
+		if errors.Is(err, blockstore.ErrPruned) {
+			continue
+		}
+
+		if err != nil {
+			return xerrors.Errorf("reading block %d: %v", i, err)
+		}
+
+		err = s.txIndex.Index(link.GetBlock())
+		if err != nil {
+			return xerrors.Errorf("indexing block %d: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
 func (s *Service) refreshRoster() error {
 	roster, err := s.getCurrentRoster()
 	if err != nil {
@@ -490,53 +1094,19 @@ func (s *Service) doRound(ctx context.Context) error {
 
 			s.logger.Warn().Msg("round reached the timeout")
 
-			// Mark that the view change happened during this round.
-			s.failedRound = true
+			start := time.Now()
 
-			ctx, cancel := context.WithTimeout(ctx, s.timeoutViewchange)
+			s.reportTimeout()
 
-			view, err := s.pbftsm.Expire(s.me)
-			if err != nil {
-				cancel()
-				return xerrors.Errorf("pbft expire failed: %v", err)
-			}
-
-			viewMsg := types.NewViewMessage(view.GetID(), view.GetLeader(), view.GetSignature())
-
-			resps, err := s.rpc.Call(ctx, viewMsg, roster)
-			if err != nil {
-				cancel()
-				return xerrors.Errorf("rpc failed to send views: %v", err)
-			}
-
-			for resp := range resps {
-				_, err = resp.GetMessageOrError()
-				if err != nil {
-					s.logger.Warn().Err(err).Msg("view propagation failure")
-				}
-			}
-
-			statesCh := s.pbftsm.Watch(ctx)
-
-			state := s.pbftsm.GetState()
-			var more bool
-
-			for state == pbft.ViewChangeState {
-				state, more = <-statesCh
-				if !more {
-					cancel()
-					return xerrors.New("viewchange failed")
-				}
-			}
-
-			s.logger.Debug().Msgf("view change successful for %d", viewMsg.GetLeader())
+			// Mark that the view change happened during this round.
+			s.failedRound = true
 
-			cancel()
-			return nil
+			return s.viewChange(ctx, roster, start)
 		case <-s.events:
 			// As a child, a block has been committed thus the previous view
 			// change succeeded.
 			s.failedRound = false
+			s.timeouts = 0
 
 			// A block has been created meaning that the round is over.
 			return nil
@@ -552,7 +1122,12 @@ func (s *Service) doRound(ctx context.Context) error {
 
 	// Send a synchronization to the roster so that they can learn about the
 	// latest block of the chain.
-	err = s.sync.Sync(ctx, roster, blocksync.Config{MinHard: threshold.ByzantineThreshold(roster.Len())})
+	syncCfg, err := s.syncConfig(roster.Len())
+	if err != nil {
+		return xerrors.Errorf("invalid sync threshold: %v", err)
+	}
+
+	err = s.sync.Sync(ctx, roster, syncCfg)
 	if err != nil {
 		return xerrors.Errorf("sync failed: %v", err)
 	}
@@ -567,11 +1142,77 @@ func (s *Service) doRound(ctx context.Context) error {
 	// The leader can be a new leader coming from a view change, so it resets
 	// the value as a round has finished.
 	s.failedRound = false
+	s.timeouts = 0
+
+	return nil
+}
+
+// viewChange expires the current view and sends the view message to the
+// roster, and then waits for the state machine to leave the view change.
+func (s *Service) viewChange(ctx context.Context, roster authority.Authority, start time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeoutViewchange)
+	defer cancel()
+
+	view, err := s.pbftsm.Expire(s.me)
+	if err != nil {
+		return xerrors.Errorf("pbft expire failed: %v", err)
+	}
+
+	viewMsg := types.NewViewMessage(view.GetID(), view.GetLeader(), view.GetSignature())
+
+	resps, err := s.rpc.Call(ctx, viewMsg, roster)
+	if err != nil {
+		return xerrors.Errorf("rpc failed to send views: %v", err)
+	}
+
+	for resp := range resps {
+		_, err = resp.GetMessageOrError()
+		if err != nil {
+			s.logger.Warn().Err(err).Msg("view propagation failure")
+		}
+	}
+
+	statesCh := s.pbftsm.Watch(ctx)
+
+	state := s.pbftsm.GetState()
+	var more bool
+
+	for state == pbft.ViewChangeState {
+		state, more = <-statesCh
+		if !more {
+			return xerrors.New("viewchange failed")
+		}
+	}
+
+	s.logger.Debug().Msgf("view change successful for %d", viewMsg.GetLeader())
+
+	s.metrics.ObserveViewChange(viewMsg.GetLeader(), time.Since(start))
 
 	return nil
 }
 
+// reportTimeout increases the number of consecutive round timeouts and raises
+// the alert when it reaches the threshold, which usually means the leader is
+// dead or the node is partitioned from the rest of the participants.
+func (s *Service) reportTimeout() {
+	s.timeouts++
+
+	if s.alertThreshold <= 0 || s.timeouts != s.alertThreshold {
+		return
+	}
+
+	s.logger.Error().
+		Int("timeouts", s.timeouts).
+		Msg("too many consecutive round timeouts")
+
+	if s.onAlert != nil {
+		s.onAlert(s.timeouts)
+	}
+}
+
 func (s *Service) doPBFT(ctx context.Context) error {
+	start := time.Now()
+
 	var id types.Digest
 	var block types.Block
 
@@ -580,7 +1221,7 @@ func (s *Service) doPBFT(ctx context.Context) error {
 		// have accepted, but somehow the finalization failed.
 		id, block = s.pbftsm.GetCommit()
 	} else {
-		txs := s.pool.Gather(ctx, pool.Config{Min: 1})
+		txs := s.gatherTxs(ctx)
 		if len(txs) == 0 {
 			s.logger.Debug().Msg("no transaction in pool")
 
@@ -596,6 +1237,15 @@ func (s *Service) doPBFT(ctx context.Context) error {
 			return ctx.Err()
 		}
 
+		// The pool does not guarantee any order, so the transactions are sorted
+		// to make the content of the block deterministic.
+		s.txOrdering(txs)
+
+		if s.gather.max > 0 && len(txs) > s.gather.max {
+			// The remaining transactions stay in the pool for the next blocks.
+			txs = txs[:s.gather.max]
+		}
+
 		data, root, err := s.prepareData(txs)
 		if err != nil {
 			return xerrors.Errorf("failed to prepare data: %v", err)
@@ -663,9 +1313,61 @@ func (s *Service) doPBFT(ctx context.Context) error {
 		return xerrors.Errorf("wake up failed: %v", err)
 	}
 
+	s.metrics.ObserveBlockCommit(block.GetIndex(),
+		len(block.GetData().GetTransactionResults()), time.Since(start))
+
 	return nil
 }
 
+// gatherTxs waits for the transactions of the next block according to the
+// gathering configuration. It returns nil if no transaction is available
+// before the context is done.
+func (s *Service) gatherTxs(ctx context.Context) []txn.Transaction {
+	var txs []txn.Transaction
+
+	if s.gather.min > 1 {
+		waitCtx := ctx
+
+		if s.gather.wait > 0 {
+			var cancel context.CancelFunc
+			waitCtx, cancel = context.WithTimeout(ctx, s.gather.wait)
+			defer cancel()
+		}
+
+		txs = s.pool.Gather(waitCtx, pool.Config{Min: s.gather.min})
+	}
+
+	if len(txs) == 0 {
+		txs = s.pool.Gather(ctx, pool.Config{Min: 1})
+	}
+
+	return txs
+}
+
+// syncConfig returns the configuration of the synchronization for a roster of
+// the given size.
+func (s *Service) syncConfig(n int) (blocksync.Config, error) {
+	cfg := blocksync.Config{
+		MinHard:    s.syncMinHard,
+		MinSoft:    s.syncMinSoft,
+		OnProgress: s.syncProgress,
+	}
+
+	if cfg.MinHard == 0 {
+		cfg.MinHard = threshold.ByzantineThreshold(n)
+	}
+
+	if cfg.MinSoft > cfg.MinHard {
+		return cfg, xerrors.Errorf("soft %d > hard %d", cfg.MinSoft, cfg.MinHard)
+	}
+
+	if cfg.MinHard > n {
+		return cfg, xerrors.Errorf("hard %d > roster size %d", cfg.MinHard, n)
+	}
+
+	return cfg, nil
+}
+
 func (s *Service) prepareViews() map[mino.Address]types.ViewMessage {
 	views := s.pbftsm.GetViews()
 	msgs := make(map[mino.Address]types.ViewMessage)
@@ -727,6 +1429,54 @@ func (s *Service) wakeUp(ctx context.Context, ro authority.Authority) error {
 	return nil
 }
 
+// sortTransactions sorts the transactions by identity, then by nonce and
+// finally by identifier when both are equal.
+func sortTransactions(txs []txn.Transaction) {
+	keys := make([][]byte, len(txs))
+	for i, tx := range txs {
+		if tx.GetIdentity() != nil {
+			// An identity that cannot be marshaled is sorted as an empty one.
+			keys[i], _ = tx.GetIdentity().MarshalText()
+		}
+	}
+
+	sort.Sort(txSorter{txs: txs, keys: keys})
+}
+
+// txSorter sorts a list of transactions with the keys of their identities.
+//
+// - implements sort.Interface
+type txSorter struct {
+	txs  []txn.Transaction
+	keys [][]byte
+}
+
+// Len implements sort.Interface. It returns the number of transactions.
+func (s txSorter) Len() int {
+	return len(s.txs)
+}
+
+// Less implements sort.Interface. It returns true if the transaction at index i
+// comes before the one at index j.
+func (s txSorter) Less(i, j int) bool {
+	cmp := bytes.Compare(s.keys[i], s.keys[j])
+	if cmp != 0 {
+		return cmp < 0
+	}
+
+	if s.txs[i].GetNonce() != s.txs[j].GetNonce() {
+		return s.txs[i].GetNonce() < s.txs[j].GetNonce()
+	}
+
+	return bytes.Compare(s.txs[i].GetID(), s.txs[j].GetID()) < 0
+}
+
+// Swap implements sort.Interface. It swaps the transactions at index i and j.
+func (s txSorter) Swap(i, j int) {
+	s.txs[i], s.txs[j] = s.txs[j], s.txs[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
 type observer struct {
 	ch chan ordering.Event
 }
@@ -735,6 +1485,51 @@ func (obs observer) NotifyCallback(event interface{}) {
 	obs.ch <- event.(ordering.Event)
 }
 
+// filterObserver is an observer that only forwards the events accepted by the
+// filter. It is used as a pointer so that it can be compared by the watcher.
+//
+// - implements core.Observer
+type filterObserver struct {
+	observer
+
+	filter func(ordering.Event) bool
+}
+
+func (obs *filterObserver) NotifyCallback(event interface{}) {
+	evt := event.(ordering.Event)
+
+	if obs.filter(evt) {
+		obs.ch <- evt
+	}
+}
+
+// findTx returns the result of the transaction with the given identifier, or
+// nil if it is not in the list.
+func findTx(results []validation.TransactionResult, id []byte) validation.TransactionResult {
+	for _, res := range results {
+		if bytes.Equal(res.GetTransaction().GetID(), id) {
+			return res
+		}
+	}
+
+	return nil
+}
+
+// filterRejected returns the results of the transactions that have been refused
+// by the validation.
+func filterRejected(results []validation.TransactionResult) []validation.TransactionResult {
+	var rejected []validation.TransactionResult
+
+	for _, res := range results {
+		accepted, _ := res.GetStatus()
+		if !accepted {
+			rejected = append(rejected, res)
+		}
+	}
+
+	return rejected
+}
+
 func calculateBackoff(backoff float64) time.Duration {
 	return time.Duration(math.Pow(2, backoff)) * RoundWait
 }
@@ -2,14 +2,18 @@ package cosipbft
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/contracts/noop"
+	"go.dedis.ch/dela/core"
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/access/darc"
 	"go.dedis.ch/dela/core/execution"
@@ -93,6 +97,115 @@ func TestService_Scenario_Basic(t *testing.T) {
 	require.NotNil(t, proof.GetValue())
 
 	checkProof(t, proof.(Proof), nodes[0].service)
+
+	// Any block can be verified from the genesis block with the signatures of
+	// the links.
+	genesis, err := nodes[3].service.genesis.Get()
+	require.NoError(t, err)
+
+	for i := uint64(0); i < 6; i++ {
+		chain, err := nodes[0].service.GetVerifiableBlock(i)
+		require.NoError(t, err)
+		require.Equal(t, i, chain.GetBlock().GetIndex())
+		require.Len(t, chain.GetLinks(), int(i)+1)
+		require.NoError(t, chain.Verify(genesis, nodes[0].service.verifierFac))
+	}
+
+	// A light client with a different genesis rejects the chain.
+	other, err := types.NewGenesis(ro, types.WithGenesisRoot(types.Digest{1}))
+	require.NoError(t, err)
+
+	chain, err := nodes[0].service.GetVerifiableBlock(2)
+	require.NoError(t, err)
+	require.Error(t, chain.Verify(other, nodes[0].service.verifierFac))
+
+	// A key that is not in the store gives a proof of absence.
+	proof, err = nodes[0].service.GetProof([]byte("unknown"))
+	require.NoError(t, err)
+	require.Nil(t, proof.GetValue())
+
+	checkProof(t, proof.(Proof), nodes[0].service)
+}
+
+func TestService_Scenario_RosterChange(t *testing.T) {
+	nodes, ro, clean := makeAuthority(t, 5)
+	defer clean()
+
+	signer := nodes[0].signer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initial := ro.Take(mino.RangeFilter(0, 4)).(crypto.CollectiveAuthority)
+
+	err := nodes[0].service.Setup(ctx, initial)
+	require.NoError(t, err)
+
+	events := nodes[1].service.Watch(ctx)
+
+	// The fifth node is added to the roster.
+	err = nodes[0].pool.Add(makeRosterTx(t, 0, ro, signer))
+	require.NoError(t, err)
+
+	evt := waitEvent(t, events)
+	require.Equal(t, uint64(0), evt.Index)
+
+	roster, err := nodes[1].service.getCurrentRoster()
+	require.NoError(t, err)
+	require.Equal(t, 5, roster.Len())
+
+	_, index := roster.GetPublicKey(nodes[4].service.me)
+	require.Equal(t, 4, index)
+
+	// And then removed.
+	err = nodes[0].pool.Add(makeRosterTx(t, 1, authority.FromAuthority(initial), signer))
+	require.NoError(t, err)
+
+	evt = waitEvent(t, events)
+	require.Equal(t, uint64(1), evt.Index)
+
+	roster, err = nodes[1].service.getCurrentRoster()
+	require.NoError(t, err)
+	require.Equal(t, 4, roster.Len())
+
+	_, index = roster.GetPublicKey(nodes[4].service.me)
+	require.Equal(t, -1, index)
+}
+
+func TestService_Scenario_Noop(t *testing.T) {
+	nodes, ro, clean := makeAuthority(t, 3)
+	defer clean()
+
+	signer := nodes[0].signer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := nodes[0].service.Setup(ctx, ro)
+	require.NoError(t, err)
+
+	events := nodes[1].service.Watch(ctx)
+
+	opts := make([]signed.TransactionOption, 0, 1)
+	for _, arg := range noop.NewArgs() {
+		opts = append(opts, signed.WithArg(arg.Key, arg.Value))
+	}
+
+	tx, err := signed.NewTransaction(0, signer.GetPublicKey(), opts...)
+	require.NoError(t, err)
+	require.NoError(t, tx.Sign(signer))
+
+	err = nodes[0].pool.Add(tx)
+	require.NoError(t, err)
+
+	evt := waitEvent(t, events)
+	require.Equal(t, uint64(0), evt.Index)
+	require.Len(t, evt.Transactions, 1)
+	require.Equal(t, tx.GetID(), evt.Transactions[0].GetTransaction().GetID())
+
+	accepted, reason := evt.Transactions[0].GetStatus()
+	require.True(t, accepted)
+	require.Empty(t, reason)
 }
 
 func TestService_Scenario_ViewChange(t *testing.T) {
@@ -127,14 +240,132 @@ func TestService_Scenario_ViewChange(t *testing.T) {
 	require.Equal(t, uint64(0), evt.Index)
 }
 
+func TestService_Scenario_TriggerViewChange(t *testing.T) {
+	nodes, ro, clean := makeAuthority(t, 4)
+	defer clean()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := nodes[0].service.Setup(ctx, ro)
+	require.NoError(t, err)
+
+	err = nodes[0].service.TriggerViewChange()
+	require.EqualError(t, err, "node is the leader of the round")
+
+	// The followers need a threshold of views to move to the next leader, so
+	// the view change is triggered on each of them.
+	errs := make(chan error, 3)
+
+	for _, node := range nodes[1:] {
+		go func(srvc *Service) {
+			errs <- srvc.TriggerViewChange()
+		}(node.service)
+	}
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	for _, node := range nodes[1:] {
+		require.Equal(t, pbft.InitialState, node.service.pbftsm.GetState())
+
+		leader, err := node.service.pbftsm.GetLeader()
+		require.NoError(t, err)
+		require.Equal(t, nodes[1].service.me, leader)
+	}
+}
+
+func TestService_FailTriggerViewChange(t *testing.T) {
+	srvc := &Service{
+		processor:         newProcessor(),
+		me:                fake.NewAddress(1),
+		timeoutViewchange: time.Second,
+	}
+
+	srvc.pbftsm = fakeSM{errLeader: fake.GetError()}
+
+	err := srvc.TriggerViewChange()
+	require.EqualError(t, err, fake.Err("reading leader"))
+
+	srvc.pbftsm = fakeSM{}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{err: fake.GetError()})
+	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
+
+	err = srvc.TriggerViewChange()
+	require.EqualError(t, err, fake.Err("reading roster: read from tree"))
+
+	srvc.pbftsm = fakeSM{err: fake.GetError()}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+
+	err = srvc.TriggerViewChange()
+	require.EqualError(t, err, fake.Err("view change failed: pbft expire failed"))
+}
+
 // Test that a block committed will be eventually finalized even if the
 // propagation failed.
 //
 // Expected log warnings and errors:
-//  - timeout from the followers
-//  - block not from the leader
-//  - round failed on node 0
-//  - mismatch state viewchange != (initial|prepare)
+//   - timeout from the followers
+//   - block not from the leader
+//   - round failed on node 0
+//   - mismatch state viewchange != (initial|prepare)
+func TestService_Scenario_RoundRobin(t *testing.T) {
+	nodes, ro, clean := makeAuthority(t, 4, WithLeaderPolicy(pbft.NewRoundRobinPolicy()))
+	defer clean()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := nodes[0].service.Setup(ctx, ro)
+	require.NoError(t, err)
+
+	events := nodes[3].service.Watch(ctx)
+
+	// Each committed block moves the leader to the next member of the roster.
+	for i := 0; i < 3; i++ {
+		err = nodes[i].pool.Add(makeTx(t, uint64(i), nodes[0].signer))
+		require.NoError(t, err)
+
+		evt := waitEvent(t, events)
+		require.Equal(t, uint64(i), evt.Index)
+
+		leader, err := nodes[3].service.pbftsm.GetLeader()
+		require.NoError(t, err)
+		require.Equal(t, nodes[i+1].onet.GetAddress(), leader)
+	}
+}
+
+func TestService_Scenario_GatherConfig(t *testing.T) {
+	nodes, ro, clean := makeAuthority(t, 4, WithGatherConfig(4, 4, 500*time.Millisecond))
+	defer clean()
+
+	signer := nodes[0].signer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := nodes[0].service.Setup(ctx, ro)
+	require.NoError(t, err)
+
+	events := nodes[2].service.Watch(ctx)
+
+	// The transactions are batched in a single block up to the maximum, and
+	// the remaining one is included after the wait.
+	for i := 0; i < 5; i++ {
+		err = nodes[0].pool.Add(makeTx(t, uint64(i), signer))
+		require.NoError(t, err)
+	}
+
+	evt := waitEvent(t, events)
+	require.Equal(t, uint64(0), evt.Index)
+	require.Len(t, evt.Transactions, 4)
+
+	evt = waitEvent(t, events)
+	require.Equal(t, uint64(1), evt.Index)
+	require.Len(t, evt.Transactions, 1)
+}
+
 func TestService_Scenario_FinalizeFailure(t *testing.T) {
 	nodes, ro, clean := makeAuthority(t, 4)
 	defer clean()
@@ -197,6 +428,122 @@ func TestService_New(t *testing.T) {
 	param.Cosi = badCosi{}
 	_, err = NewService(param)
 	require.EqualError(t, err, fake.Err("creating cosi failed"))
+
+	_, err = NewService(param, WithSyncThreshold(-1, 0))
+	require.EqualError(t, err, "invalid sync threshold: negative value")
+
+	_, err = NewService(param, WithSyncThreshold(1, 2))
+	require.EqualError(t, err, "invalid sync threshold: soft 2 > hard 1")
+
+	_, err = NewService(param, WithGatherConfig(2, 1, 0))
+	require.EqualError(t, err, "invalid gather config: min 2 > max 1")
+}
+
+func TestService_Restart(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "cosipbft")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	db, err := kv.New(filepath.Join(dir, "test.db"))
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+	genesisFac := types.NewGenesisFactory(authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{}))
+
+	genesis, err := types.NewGenesis(ro)
+	require.NoError(t, err)
+
+	store := blockstore.NewGenesisDiskStore(db, genesisFac)
+	require.NoError(t, store.Load())
+	require.NoError(t, store.Set(genesis))
+
+	// The node restarts with the same database and loads the genesis block.
+	store = blockstore.NewGenesisDiskStore(db, genesisFac)
+	require.NoError(t, store.Load())
+
+	param := ServiceParam{
+		Mino:       fake.Mino{},
+		Cosi:       flatcosi.NewFlat(fake.Mino{}, fake.NewAggregateSigner()),
+		Tree:       fakeTree{},
+		Validation: simple.NewService(nil, nil),
+		Pool:       badPool{},
+	}
+
+	srvc, err := NewService(param, WithGenesisStore(store))
+	require.NoError(t, err)
+
+	// The service resumes the chain without running the setup again.
+	select {
+	case <-srvc.started:
+	default:
+		t.Fatal("service should have started")
+	}
+
+	stored, err := srvc.genesis.Get()
+	require.NoError(t, err)
+	require.Equal(t, genesis.GetHash(), stored.GetHash())
+
+	<-srvc.closed
+}
+
+func TestService_WithRoundTimeout(t *testing.T) {
+	param := ServiceParam{
+		Mino:       fake.Mino{},
+		Cosi:       flatcosi.NewFlat(fake.Mino{}, fake.NewAggregateSigner()),
+		Tree:       fakeTree{},
+		Validation: simple.NewService(nil, nil),
+		Pool:       badPool{},
+	}
+
+	genesis := blockstore.NewGenesisStore()
+	genesis.Set(types.Genesis{})
+
+	srvc, err := NewService(param, WithGenesisStore(genesis), WithRoundTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+
+	<-srvc.closed
+
+	require.Equal(t, 50*time.Millisecond, srvc.timeoutRound)
+	require.Equal(t, 50*time.Millisecond, srvc.timeoutRoundAfterFailure)
+	require.Equal(t, 50*time.Millisecond, srvc.timeoutViewchange)
+
+	_, err = NewService(param, WithRoundTimeout(0))
+	require.EqualError(t, err, "invalid round timeout: 0s is not positive")
+
+	_, err = NewService(param, WithRoundTimeout(-time.Second))
+	require.EqualError(t, err, "invalid round timeout: -1s is not positive")
+
+	// A follower that does not receive the block expires the view after the
+	// round timeout.
+	rpc := fake.NewRPC()
+	rpc.Done()
+
+	srvc = &Service{
+		processor:                newProcessor(),
+		me:                       fake.NewAddress(1),
+		rpc:                      rpc,
+		timeoutRound:             50 * time.Millisecond,
+		timeoutRoundAfterFailure: 50 * time.Millisecond,
+		timeoutViewchange:        50 * time.Millisecond,
+	}
+
+	srvc.blocks = blockstore.NewInMemory()
+	srvc.pool = mem.NewPool()
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
+	srvc.pbftsm = fakeSM{err: fake.GetError()}
+
+	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+
+	start := time.Now()
+
+	err = srvc.doRound(context.Background())
+	require.EqualError(t, err, fake.Err("pbft expire failed"))
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond))
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
 }
 
 func TestService_Setup(t *testing.T) {
@@ -233,37 +580,42 @@ func TestService_AlreadySet_Setup(t *testing.T) {
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.access = fakeAccess{}
 	srvc.genesis = blockstore.NewGenesisStore()
-	srvc.genesis.Set(types.Genesis{})
 
-	authority := fake.NewAuthority(3, fake.NewSigner)
+	rpc := fake.NewRPC()
+	rpc.Done()
+	srvc.rpc = rpc
+
+	ca := fake.NewAuthority(3, fake.NewSigner)
+
+	genesis, err := types.NewGenesis(authority.FromAuthority(ca))
+	require.NoError(t, err)
+	require.NoError(t, srvc.genesis.Set(genesis))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	err := srvc.Setup(ctx, authority)
-	require.EqualError(t, err,
-		"creating genesis: set genesis failed: genesis block is already set")
-}
-
-func TestService_FailReadGenesis_Setup(t *testing.T) {
-	srvc := &Service{
-		processor: newProcessor(),
-	}
+	// The genesis block is sent again for the same roster.
+	err = srvc.Setup(ctx, ca)
+	require.NoError(t, err)
+	require.Equal(t, 1, rpc.Calls.Len())
 
-	srvc.tree = blockstore.NewTreeCache(fakeTree{})
-	srvc.access = fakeAccess{}
-	srvc.genesis = fakeGenesisStore{errGet: fake.GetError()}
+	err = srvc.Setup(ctx, fake.NewAuthority(2, fake.NewSigner))
+	require.EqualError(t, err, "mismatch roster: genesis block is already set")
+	require.True(t, errors.Is(err, ErrAlreadySetup))
 
-	authority := fake.NewAuthority(3, fake.NewSigner)
+	err = srvc.Setup(ctx, ca.Take(mino.RangeFilter(1, 3)).(crypto.CollectiveAuthority))
+	require.True(t, errors.Is(err, ErrAlreadySetup))
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	bad := fake.NewAuthority(1, func() crypto.Signer {
+		return fake.NewSignerWithPublicKey(fake.NewBadPublicKey())
+	})
 
-	err := srvc.Setup(ctx, authority)
-	require.EqualError(t, err, fake.Err("failed to read genesis"))
+	err = srvc.Setup(ctx, bad)
+	require.EqualError(t, err, fake.Err("failed to fingerprint roster: "+
+		"couldn't marshal public key"))
 }
 
-func TestService_FailPropagate_Setup(t *testing.T) {
+func TestService_Retry_Setup(t *testing.T) {
 	srvc := &Service{
 		processor: newProcessor(),
 	}
@@ -271,19 +623,89 @@ func TestService_FailPropagate_Setup(t *testing.T) {
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.access = fakeAccess{}
 	srvc.genesis = blockstore.NewGenesisStore()
-	srvc.rpc = fake.NewBadRPC()
 
-	authority := fake.NewAuthority(3, fake.NewSigner)
+	rpc := fake.NewRPC()
+	rpc.SendResponseWithError(fake.NewAddress(1), fake.GetError())
+	rpc.Done()
+	srvc.rpc = rpc
+
+	ca := fake.NewAuthority(3, fake.NewSigner)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	err := srvc.Setup(ctx, authority)
-	require.EqualError(t, err, fake.Err("sending genesis"))
-}
+	err := srvc.Setup(ctx, ca)
+	require.EqualError(t, err,
+		"only 2 acknowledgements of the required 3 out of 3 members, "+
+			"missing [fake.Address[1]]")
 
-func TestService_RequestFailure_Setup(t *testing.T) {
-	srvc := &Service{
+	// The genesis is stored locally but the setup can be retried once the
+	// member is available, which acknowledges the same genesis block.
+	rpc = fake.NewRPC()
+	rpc.Done()
+	srvc.rpc = rpc
+
+	err = srvc.Setup(ctx, ca)
+	require.NoError(t, err)
+	require.Equal(t, 1, rpc.Calls.Len())
+
+	msg := rpc.Calls.Get(0, 1).(types.GenesisMessage)
+
+	genesis, err := srvc.genesis.Get()
+	require.NoError(t, err)
+	require.Equal(t, genesis.GetHash(), msg.GetGenesis().GetHash())
+
+	// A retry that is still not acknowledged by enough members fails.
+	rpc = fake.NewRPC()
+	rpc.SendResponseWithError(fake.NewAddress(1), fake.GetError())
+	rpc.Done()
+	srvc.rpc = rpc
+
+	err = srvc.Setup(ctx, ca)
+	require.EqualError(t, err,
+		"only 2 acknowledgements of the required 3 out of 3 members, "+
+			"missing [fake.Address[1]]")
+}
+
+func TestService_FailReadGenesis_Setup(t *testing.T) {
+	srvc := &Service{
+		processor: newProcessor(),
+	}
+
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.access = fakeAccess{}
+	srvc.genesis = fakeGenesisStore{errGet: fake.GetError()}
+
+	authority := fake.NewAuthority(3, fake.NewSigner)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := srvc.Setup(ctx, authority)
+	require.EqualError(t, err, fake.Err("failed to read genesis"))
+}
+
+func TestService_FailPropagate_Setup(t *testing.T) {
+	srvc := &Service{
+		processor: newProcessor(),
+	}
+
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.access = fakeAccess{}
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.rpc = fake.NewBadRPC()
+
+	authority := fake.NewAuthority(3, fake.NewSigner)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := srvc.Setup(ctx, authority)
+	require.EqualError(t, err, fake.Err("sending genesis"))
+}
+
+func TestService_RequestFailure_Setup(t *testing.T) {
+	srvc := &Service{
 		processor: newProcessor(),
 	}
 
@@ -293,6 +715,7 @@ func TestService_RequestFailure_Setup(t *testing.T) {
 
 	rpc := fake.NewRPC()
 	rpc.SendResponseWithError(fake.NewAddress(1), fake.GetError())
+	rpc.Done()
 	srvc.rpc = rpc
 
 	authority := fake.NewAuthority(3, fake.NewSigner)
@@ -301,7 +724,47 @@ func TestService_RequestFailure_Setup(t *testing.T) {
 	defer cancel()
 
 	err := srvc.Setup(ctx, authority)
-	require.EqualError(t, err, fake.Err("one request failed"))
+	require.EqualError(t, err,
+		"only 2 acknowledgements of the required 3 out of 3 members, "+
+			"missing [fake.Address[1]]")
+
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.started = make(chan struct{})
+
+	rpc = fake.NewRPC()
+	rpc.SendResponseWithError(fake.NewAddress(1), fake.GetError())
+	rpc.SendResponseWithError(fake.NewAddress(2), fake.GetError())
+	rpc.Done()
+	srvc.rpc = rpc
+
+	err = srvc.Setup(ctx, fake.NewAuthority(4, fake.NewSigner))
+	require.EqualError(t, err,
+		"only 2 acknowledgements of the required 3 out of 4 members, "+
+			"missing [fake.Address[1] fake.Address[2]]")
+}
+
+func TestService_MinorityFailure_Setup(t *testing.T) {
+	srvc := &Service{
+		processor: newProcessor(),
+	}
+
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.access = fakeAccess{}
+	srvc.genesis = blockstore.NewGenesisStore()
+
+	rpc := fake.NewRPC()
+	rpc.SendResponseWithError(fake.NewAddress(3), fake.GetError())
+	rpc.Done()
+	srvc.rpc = rpc
+
+	logger, check := fake.CheckLog("some members will catch up the genesis later")
+	srvc.logger = logger
+
+	authority := fake.NewAuthority(4, fake.NewSigner)
+
+	err := srvc.Setup(context.Background(), authority)
+	require.NoError(t, err)
+	check(t)
 }
 
 func TestService_Main(t *testing.T) {
@@ -346,6 +809,7 @@ func TestService_Main(t *testing.T) {
 func TestService_DoRound(t *testing.T) {
 	rpc := fake.NewRPC()
 	ch := make(chan pbft.State)
+	metrics := &fakeMetrics{}
 
 	srvc := &Service{
 		processor:                newProcessor(),
@@ -354,6 +818,7 @@ func TestService_DoRound(t *testing.T) {
 		timeoutRound:             time.Millisecond,
 		timeoutRoundAfterFailure: time.Millisecond,
 		closing:                  make(chan struct{}),
+		metrics:                  metrics,
 	}
 	srvc.blocks = blockstore.NewInMemory()
 	srvc.sync = fakeSync{}
@@ -385,6 +850,8 @@ func TestService_DoRound(t *testing.T) {
 	// Round with timeout and a transaction in the pool.
 	err = srvc.doRound(ctx)
 	require.NoError(t, err)
+	require.Empty(t, metrics.commits)
+	require.Len(t, metrics.views, 1)
 }
 
 func TestService_ViewchangeFailed_DoRound(t *testing.T) {
@@ -421,6 +888,59 @@ func TestService_ViewchangeFailed_DoRound(t *testing.T) {
 	require.EqualError(t, err, "viewchange failed")
 }
 
+func TestService_TimeoutAlert_DoRound(t *testing.T) {
+	rpc := fake.NewRPC()
+	rpc.Done()
+
+	alerts := 0
+
+	srvc := &Service{
+		processor:                newProcessor(),
+		me:                       fake.NewAddress(1),
+		rpc:                      rpc,
+		timeoutRound:             time.Millisecond,
+		timeoutRoundAfterFailure: time.Millisecond,
+		closing:                  make(chan struct{}),
+		alertThreshold:           3,
+		onAlert:                  func(int) { alerts++ },
+		metrics:                  noopMetrics{},
+	}
+	srvc.blocks = blockstore.NewInMemory()
+	srvc.pool = mem.NewPool()
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
+	srvc.pbftsm = fakeSM{}
+
+	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+
+	for i := 0; i < 5; i++ {
+		err := srvc.doRound(context.Background())
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 5, srvc.timeouts)
+	require.Equal(t, 1, alerts)
+
+	// A successful round resets the counter so that the alert can be raised
+	// again.
+	srvc.events = make(chan ordering.Event, 1)
+	srvc.events <- ordering.Event{}
+	srvc.timeoutRoundAfterFailure = time.Minute
+
+	err := srvc.doRound(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, srvc.timeouts)
+
+	srvc.timeoutRoundAfterFailure = time.Millisecond
+
+	for i := 0; i < 3; i++ {
+		err := srvc.doRound(context.Background())
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 2, alerts)
+}
+
 func TestService_FailPBFTExpire_DoRound(t *testing.T) {
 	rpc := fake.NewRPC()
 	rpc.Done()
@@ -533,6 +1053,79 @@ func TestService_FailSync_DoRound(t *testing.T) {
 	require.EqualError(t, err, fake.Err("sync failed"))
 }
 
+func TestService_InvalidSyncThreshold_DoRound(t *testing.T) {
+	srvc := &Service{
+		processor:                newProcessor(),
+		me:                       fake.NewAddress(0),
+		timeoutRound:             time.Millisecond,
+		timeoutRoundAfterFailure: time.Millisecond,
+		syncMinHard:              1,
+	}
+
+	srvc.blocks = blockstore.NewInMemory()
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
+	srvc.pbftsm = fakeSM{}
+	srvc.sync = fakeSync{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := srvc.doRound(ctx)
+	require.EqualError(t, err, "invalid sync threshold: hard 1 > roster size 0")
+}
+
+func TestService_GatherTxs(t *testing.T) {
+	srvc := &Service{processor: newProcessor()}
+	srvc.pool = mem.NewPool()
+	srvc.gather = gatherConfig{min: 3, wait: 10 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Nothing is available before the round ends.
+	require.Len(t, srvc.gatherTxs(ctx), 0)
+
+	require.NoError(t, srvc.pool.Add(makeTx(t, 0, fake.NewSigner())))
+	require.NoError(t, srvc.pool.Add(makeTx(t, 1, fake.NewSigner())))
+
+	// Not enough transactions after the wait, so the available ones are
+	// returned.
+	require.Len(t, srvc.gatherTxs(context.Background()), 2)
+
+	require.NoError(t, srvc.pool.Add(makeTx(t, 2, fake.NewSigner())))
+
+	srvc.gather.wait = 0
+	require.Len(t, srvc.gatherTxs(context.Background()), 3)
+}
+
+func TestService_SyncConfig(t *testing.T) {
+	srvc := &Service{}
+
+	// By default, the round waits for the Byzantine quorum to be synchronized.
+	cfg, err := srvc.syncConfig(4)
+	require.NoError(t, err)
+	require.Equal(t, 3, cfg.MinHard)
+	require.Equal(t, 0, cfg.MinSoft)
+
+	srvc.syncMinHard = 2
+	srvc.syncMinSoft = 1
+
+	cfg, err = srvc.syncConfig(4)
+	require.NoError(t, err)
+	require.Equal(t, 2, cfg.MinHard)
+	require.Equal(t, 1, cfg.MinSoft)
+
+	_, err = srvc.syncConfig(1)
+	require.EqualError(t, err, "hard 2 > roster size 1")
+
+	srvc.syncMinHard = 0
+	srvc.syncMinSoft = 4
+
+	_, err = srvc.syncConfig(4)
+	require.EqualError(t, err, "soft 4 > hard 3")
+}
+
 func TestService_FailPBFT_DoRound(t *testing.T) {
 	srvc := &Service{
 		processor:                newProcessor(),
@@ -540,6 +1133,7 @@ func TestService_FailPBFT_DoRound(t *testing.T) {
 		timeoutRound:             RoundTimeout,
 		timeoutRoundAfterFailure: RoundTimeout,
 		val:                      fakeValidation{err: fake.GetError()},
+		txOrdering:               sortTransactions,
 	}
 
 	srvc.blocks = blockstore.NewInMemory()
@@ -561,8 +1155,13 @@ func TestService_FailPBFT_DoRound(t *testing.T) {
 
 func TestService_DoPBFT(t *testing.T) {
 	rpc := fake.NewRPC()
+	metrics := &fakeMetrics{}
 
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{
+		processor:  newProcessor(),
+		metrics:    metrics,
+		txOrdering: sortTransactions,
+	}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.val = fakeValidation{}
 	srvc.blocks = blockstore.NewInMemory()
@@ -584,12 +1183,15 @@ func TestService_DoPBFT(t *testing.T) {
 	// Context timed out and no transaction are in the pool.
 	err := srvc.doPBFT(ctx)
 	require.NoError(t, err)
+	require.Empty(t, metrics.commits)
 
 	// This time the gathering succeeds.
 	ctx = context.Background()
 	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
 	err = srvc.doPBFT(ctx)
 	require.NoError(t, err)
+	require.Equal(t, []uint64{0}, metrics.commits)
+	require.Empty(t, metrics.views)
 }
 
 func TestService_ContextCanceld_DoPBFT(t *testing.T) {
@@ -609,7 +1211,7 @@ func TestService_ContextCanceld_DoPBFT(t *testing.T) {
 }
 
 func TestService_FailValidation_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), txOrdering: sortTransactions}
 	srvc.val = fakeValidation{err: fake.GetError()}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.pbftsm = fakeSM{}
@@ -627,7 +1229,7 @@ func TestService_FailValidation_DoPBFT(t *testing.T) {
 }
 
 func TestService_FailCreateBlock_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
+	srvc := &Service{processor: newProcessor(), txOrdering: sortTransactions}
 	srvc.val = fakeValidation{}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.pbftsm = fakeSM{}
@@ -635,205 +1237,848 @@ func TestService_FailCreateBlock_DoPBFT(t *testing.T) {
 	srvc.hashFactory = fake.NewHashFactory(fake.NewBadHash())
 	srvc.blocks = blockstore.NewInMemory()
 
-	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := srvc.doPBFT(ctx)
+	require.EqualError(t, err,
+		fake.Err("creating block failed: fingerprint failed: couldn't write index"))
+}
+
+func TestService_FailPrepare_DoPBFT(t *testing.T) {
+	srvc := &Service{processor: newProcessor(), txOrdering: sortTransactions}
+	srvc.val = fakeValidation{}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.pbftsm = fakeSM{err: fake.GetError()}
+	srvc.pool = mem.NewPool()
+	srvc.hashFactory = crypto.NewSha256Factory()
+	srvc.blocks = blockstore.NewInMemory()
+
+	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := srvc.doPBFT(ctx)
+	require.EqualError(t, err, fake.Err("pbft prepare failed"))
+}
+
+func TestService_FailReadRoster_DoPBFT(t *testing.T) {
+	srvc := &Service{processor: newProcessor(), txOrdering: sortTransactions}
+	srvc.val = fakeValidation{}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{err: fake.GetError()})
+	srvc.pbftsm = fakeSM{}
+	srvc.pool = mem.NewPool()
+	srvc.hashFactory = crypto.NewSha256Factory()
+	srvc.blocks = blockstore.NewInMemory()
+
+	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := srvc.doPBFT(ctx)
+	require.EqualError(t, err, fake.Err("read roster failed: read from tree"))
+}
+
+func TestService_FailPrepareSig_DoPBFT(t *testing.T) {
+	srvc := &Service{processor: newProcessor(), txOrdering: sortTransactions}
+	srvc.val = fakeValidation{}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.pbftsm = fakeSM{}
+	srvc.pool = mem.NewPool()
+	srvc.hashFactory = crypto.NewSha256Factory()
+	srvc.blocks = blockstore.NewInMemory()
+	srvc.actor = fakeCosiActor{err: fake.GetError()}
+	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
+
+	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := srvc.doPBFT(ctx)
+	require.EqualError(t, err, fake.Err("prepare signature failed"))
+}
+
+func TestService_FailCommitSign_DoPBFT(t *testing.T) {
+	srvc := &Service{processor: newProcessor(), txOrdering: sortTransactions}
+	srvc.val = fakeValidation{}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.pbftsm = fakeSM{}
+	srvc.pool = mem.NewPool()
+	srvc.hashFactory = crypto.NewSha256Factory()
+	srvc.blocks = blockstore.NewInMemory()
+	srvc.actor = fakeCosiActor{
+		err:     fake.GetError(),
+		counter: fake.NewCounter(1),
+	}
+	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
+
+	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := srvc.doPBFT(ctx)
+	require.EqualError(t, err, fake.Err("commit signature failed"))
+}
+
+func TestService_FailPropagation_DoPBFT(t *testing.T) {
+	srvc := &Service{processor: newProcessor(), txOrdering: sortTransactions}
+	srvc.val = fakeValidation{}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.pbftsm = fakeSM{}
+	srvc.pool = mem.NewPool()
+	srvc.hashFactory = crypto.NewSha256Factory()
+	srvc.blocks = blockstore.NewInMemory()
+	srvc.actor = fakeCosiActor{}
+	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
+	srvc.rpc = fake.NewBadRPC()
+
+	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := srvc.doPBFT(ctx)
+	require.EqualError(t, err, fake.Err("propagation failed"))
+}
+
+func TestService_FailWakeUp_DoPBFT(t *testing.T) {
+	rpc := fake.NewRPC()
+	rpc.Done()
+
+	srvc := &Service{processor: newProcessor(), txOrdering: sortTransactions}
+	srvc.val = fakeValidation{}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.pbftsm = fakeSM{}
+	srvc.pool = mem.NewPool()
+	srvc.hashFactory = crypto.NewSha256Factory()
+	srvc.blocks = blockstore.NewInMemory()
+	srvc.actor = fakeCosiActor{}
+	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
+	srvc.rpc = rpc
+	srvc.genesis = blockstore.NewGenesisStore()
+
+	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := srvc.doPBFT(ctx)
+	require.EqualError(t, err, "wake up failed: read genesis failed: missing genesis block")
+}
+
+func TestService_WakeUp(t *testing.T) {
+	rpc := fake.NewRPC()
+
+	srvc := &Service{processor: newProcessor()}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.genesis.Set(types.Genesis{})
+	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
+	srvc.rpc = rpc
+
+	ctx := context.Background()
+
+	rpc.SendResponseWithError(fake.NewAddress(5), fake.GetError())
+	rpc.Done()
+	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	err := srvc.wakeUp(ctx, ro)
+	require.NoError(t, err)
+
+	srvc.tree.Set(fakeTree{err: fake.GetError()})
+	err = srvc.wakeUp(ctx, ro)
+	require.EqualError(t, err, fake.Err("read roster failed: read from tree"))
+
+	srvc.tree.Set(fakeTree{})
+	srvc.rpc = fake.NewBadRPC()
+	err = srvc.wakeUp(ctx, ro)
+	require.EqualError(t, err, fake.Err("rpc failed"))
+}
+
+func TestService_GetProof(t *testing.T) {
+	srvc := &Service{processor: newProcessor()}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.blocks = blockstore.NewInMemory()
+	srvc.blocks.Store(makeBlock(t, types.Digest{}))
+
+	proof, err := srvc.GetProof([]byte("A"))
+	require.NoError(t, err)
+	require.NotNil(t, proof)
+
+	srvc.tree.Set(fakeTree{err: fake.GetError()})
+	_, err = srvc.GetProof([]byte("B"))
+	require.EqualError(t, err, fake.Err("reading path"))
+
+	srvc.tree.Set(fakeTree{})
+	srvc.blocks = blockstore.NewInMemory()
+	_, err = srvc.GetProof([]byte("A"))
+	require.EqualError(t, err, "reading chain: store is empty")
+}
+
+func TestService_GetProof_Cache(t *testing.T) {
+	tree := &countingTree{}
+
+	srvc := &Service{processor: newProcessor()}
+	srvc.tree = blockstore.NewTreeCache(tree)
+	srvc.blocks = blockstore.NewInMemory()
+	srvc.blocks.Store(makeBlock(t, types.Digest{}))
+
+	for i := 0; i < 3; i++ {
+		_, err := srvc.GetProof([]byte("A"))
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 1, tree.calls)
+	require.Equal(t, 1, srvc.proofs.len())
+
+	// A new block supersedes the proofs even before the cache is purged.
+	last, err := srvc.blocks.Last()
+	require.NoError(t, err)
+
+	next, err := types.NewBlock(simple.NewResult(nil), types.WithIndex(1))
+	require.NoError(t, err)
+
+	link, err := types.NewBlockLink(last.GetBlock().GetHash(), next)
+	require.NoError(t, err)
+	require.NoError(t, srvc.blocks.Store(link))
+
+	proof, err := srvc.GetProof([]byte("A"))
+	require.NoError(t, err)
+	require.Equal(t, 2, tree.calls)
+	require.Equal(t, next.GetHash(), proof.(Proof).chain.GetBlock().GetHash())
+
+	srvc.proofs.purge()
+	require.Equal(t, 0, srvc.proofs.len())
+}
+
+func BenchmarkService_GetProof(b *testing.B) {
+	tree := &countingTree{}
+
+	srvc := &Service{processor: newProcessor()}
+	srvc.tree = blockstore.NewTreeCache(tree)
+	srvc.blocks = blockstore.NewInMemory()
+
+	block, err := types.NewBlock(simple.NewResult(nil))
+	require.NoError(b, err)
+
+	link, err := types.NewBlockLink(types.Digest{}, block)
+	require.NoError(b, err)
+	require.NoError(b, srvc.blocks.Store(link))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := srvc.GetProof([]byte("A"))
+		require.NoError(b, err)
+	}
+
+	b.ReportMetric(float64(tree.calls), "paths")
+}
+
+func TestService_WatchFilter(t *testing.T) {
+	srvc := &Service{processor: newProcessor()}
+	srvc.watcher = core.NewWatcher()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	even := func(evt ordering.Event) bool {
+		return evt.Index%2 == 0
+	}
+
+	events := srvc.WatchFilter(ctx, even)
+	all := srvc.Watch(ctx)
+
+	for i := uint64(0); i < 5; i++ {
+		srvc.watcher.Notify(ordering.Event{Index: i})
+
+		require.Equal(t, i, waitEvent(t, all).Index)
+
+		if i%2 == 0 {
+			require.Equal(t, i, waitEvent(t, events).Index)
+		}
+
+		require.Len(t, events, 0)
+	}
+
+	cancel()
+
+	_, more := <-events
+	require.False(t, more)
+}
+
+func TestService_WatchBlocks(t *testing.T) {
+	srvc := &Service{
+		processor: newProcessor(),
+		events:    make(chan ordering.Event, 1),
+		closing:   make(chan struct{}),
+		txIndex:   blockstore.NewTxIndex(),
+	}
+	srvc.pool = mem.NewPool()
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
+
+	defer close(srvc.closing)
+
+	signer := fake.NewSigner()
+	accepted := makeTx(t, 0, signer)
+	refused := makeTx(t, 1, signer)
+
+	require.NoError(t, srvc.pool.Add(accepted))
+	require.NoError(t, srvc.pool.Add(refused))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := srvc.Watch(ctx)
+
+	block, err := types.NewBlock(simple.NewResult([]simple.TransactionResult{
+		simple.NewTransactionResult(accepted, true, ""),
+		simple.NewTransactionResult(refused, false, "nonce is invalid"),
+	}))
+	require.NoError(t, err)
+
+	link, err := types.NewBlockLink(types.Digest{}, block)
+	require.NoError(t, err)
+
+	srvc.blocks = fakeWatchStore{links: []types.BlockLink{link}}
+	srvc.watchBlocks()
+
+	evt := waitEvent(t, events)
+	require.Equal(t, uint64(0), evt.Index)
+	require.Len(t, evt.Transactions, 2)
+
+	require.Equal(t, accepted.GetID(), evt.Transactions[0].GetTransaction().GetID())
+	status, reason := evt.Transactions[0].GetStatus()
+	require.True(t, status)
+	require.Empty(t, reason)
+
+	require.Equal(t, refused.GetID(), evt.Transactions[1].GetTransaction().GetID())
+	status, reason = evt.Transactions[1].GetStatus()
+	require.False(t, status)
+	require.Equal(t, "nonce is invalid", reason)
+
+	// The refused transaction is reported with its reason.
+	require.Len(t, evt.Rejected, 1)
+	require.Equal(t, refused.GetID(), evt.Rejected[0].GetTransaction().GetID())
+	_, reason = evt.Rejected[0].GetStatus()
+	require.Equal(t, "nonce is invalid", reason)
+
+	// The transactions of the block are removed from the pool.
+	require.Equal(t, 0, srvc.pool.Len())
+
+	// The transactions of the block are indexed.
+	index, err := srvc.txIndex.Lookup(refused.GetID())
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), index)
+}
+
+func TestService_GetStore(t *testing.T) {
+	srvc := &Service{processor: newProcessor()}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+
+	require.IsType(t, fakeTree{}, srvc.GetStore())
+}
+
+func TestService_GetRoster(t *testing.T) {
+	srvc := &Service{processor: newProcessor()}
+	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+	srvc.rosterFac = fakeRosterFac{}
+
+	roster, err := srvc.GetRoster()
+	require.NoError(t, err)
+	require.Equal(t, 3, roster.Len())
+}
+
+func TestService_OnTransaction(t *testing.T) {
+	watcher := newCountingWatcher()
+
+	srvc := &Service{processor: newProcessor()}
+	srvc.watcher = watcher
+
+	tx := makeTx(t, 0, fake.NewSigner())
+	other := makeTx(t, 1, fake.NewSigner())
+
+	type call struct {
+		res   validation.TransactionResult
+		index uint64
+	}
+
+	calls := make(chan call, 2)
+
+	srvc.OnTransaction(context.Background(), tx.GetID(),
+		func(res validation.TransactionResult, index uint64) {
+			calls <- call{res: res, index: index}
+		})
+
+	srvc.watcher.Notify(ordering.Event{
+		Index:        1,
+		Transactions: []validation.TransactionResult{simple.NewTransactionResult(other, true, "")},
+	})
+
+	srvc.watcher.Notify(ordering.Event{
+		Index: 2,
+		Transactions: []validation.TransactionResult{
+			simple.NewTransactionResult(other, true, ""),
+			simple.NewTransactionResult(tx, false, "oops"),
+		},
+	})
+
+	select {
+	case c := <-calls:
+		require.Equal(t, uint64(2), c.index)
+		require.Equal(t, tx.GetID(), c.res.GetTransaction().GetID())
+
+		accepted, reason := c.res.GetStatus()
+		require.False(t, accepted)
+		require.Equal(t, "oops", reason)
+	case <-time.After(time.Second):
+		t.Fatal("callback not invoked")
+	}
+
+	// The callback is invoked only once.
+	srvc.watcher.Notify(ordering.Event{
+		Index:        3,
+		Transactions: []validation.TransactionResult{simple.NewTransactionResult(tx, true, "")},
+	})
+
+	require.Eventually(t, func() bool { return watcher.Len() == 0 },
+		time.Second, time.Millisecond)
+	require.Len(t, calls, 0)
+}
+
+func TestService_WaitForTx(t *testing.T) {
+	watcher := newCountingWatcher()
+
+	srvc := &Service{processor: newProcessor(), txIndex: blockstore.NewTxIndex()}
+	srvc.watcher = watcher
+	srvc.blocks = blockstore.NewInMemory()
+
+	tx := makeTx(t, 0, fake.NewSigner())
+	rejected := makeTx(t, 1, fake.NewSigner())
+	other := makeTx(t, 2, fake.NewSigner())
+
+	// The transaction is already in a block that is not the last one.
+	storeTxBlock(t, srvc.blocks, simple.NewTransactionResult(tx, true, ""))
+	storeTxBlock(t, srvc.blocks, simple.NewTransactionResult(other, true, ""))
+
+	err := srvc.rebuildTxIndex()
+	require.NoError(t, err)
+
+	res, err := srvc.WaitForTx(context.Background(), tx.GetID())
+	require.NoError(t, err)
+	require.Equal(t, tx.GetID(), res.GetTransaction().GetID())
+
+	accepted, _ := res.GetStatus()
+	require.True(t, accepted)
+
+	// The transaction is rejected in a later block.
+	go func() {
+		require.Eventually(t, func() bool { return watcher.Len() == 1 },
+			time.Second, time.Millisecond)
+
+		srvc.watcher.Notify(ordering.Event{
+			Index:        1,
+			Transactions: []validation.TransactionResult{simple.NewTransactionResult(other, true, "")},
+		})
+
+		srvc.watcher.Notify(ordering.Event{
+			Index:        2,
+			Transactions: []validation.TransactionResult{simple.NewTransactionResult(rejected, false, "oops")},
+		})
+	}()
+
+	res, err = srvc.WaitForTx(context.Background(), rejected.GetID())
+	require.NoError(t, err)
+	require.Equal(t, rejected.GetID(), res.GetTransaction().GetID())
+
+	accepted, reason := res.GetStatus()
+	require.False(t, accepted)
+	require.Equal(t, "oops", reason)
+
+	require.Eventually(t, func() bool { return watcher.Len() == 0 },
+		time.Second, time.Millisecond)
+
+	// The transaction is never committed.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = srvc.WaitForTx(ctx, []byte{0xaa})
+	require.EqualError(t, err, "transaction 0xaa not found: context deadline exceeded")
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	require.Eventually(t, func() bool { return watcher.Len() == 0 },
+		time.Second, time.Millisecond)
+
+	srvc.blocks = blockstore.NewInMemory()
+	_, err = srvc.WaitForTx(context.Background(), tx.GetID())
+	require.EqualError(t, err,
+		"failed to look up transaction: reading block 0: block not found: no block")
+}
+
+func TestService_LookupTx(t *testing.T) {
+	signer := fake.NewSigner()
+	first := makeTx(t, 0, signer)
+	second := makeTx(t, 1, signer)
+	refused := makeTx(t, 2, signer)
+
+	blocks := blockstore.NewInMemory()
+	storeTxBlock(t, blocks, simple.NewTransactionResult(first, true, ""))
+	storeTxBlock(t, blocks,
+		simple.NewTransactionResult(second, true, ""),
+		simple.NewTransactionResult(refused, false, "nonce is invalid"))
+
+	srvc := &Service{processor: newProcessor(), txIndex: blockstore.NewTxIndex()}
+	srvc.blocks = blocks
+
+	err := srvc.rebuildTxIndex()
+	require.NoError(t, err)
+
+	index, res, err := srvc.LookupTx(second.GetID())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), index)
+	require.Equal(t, second.GetID(), res.GetTransaction().GetID())
+
+	index, res, err = srvc.LookupTx(refused.GetID())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), index)
+	accepted, reason := res.GetStatus()
+	require.False(t, accepted)
+	require.Equal(t, "nonce is invalid", reason)
+
+	_, _, err = srvc.LookupTx([]byte{0xaa})
+	require.EqualError(t, err, "lookup failed: transaction 0xaa: no transaction")
+	require.True(t, errors.Is(err, blockstore.ErrNoTransaction))
+
+	// A restart comes with an empty index that is rebuilt from the blocks.
+	srvc = &Service{processor: newProcessor(), txIndex: blockstore.NewTxIndex()}
+	srvc.blocks = blocks
+
+	_, _, err = srvc.LookupTx(first.GetID())
+	require.True(t, errors.Is(err, blockstore.ErrNoTransaction))
+
+	err = srvc.rebuildTxIndex()
+	require.NoError(t, err)
+
+	index, _, err = srvc.LookupTx(first.GetID())
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), index)
+
+	// Pruned blocks are skipped during the rebuild.
+	require.NoError(t, blocks.Prune(1))
+
+	srvc.txIndex = blockstore.NewTxIndex()
+
+	err = srvc.rebuildTxIndex()
+	require.NoError(t, err)
+
+	_, _, err = srvc.LookupTx(first.GetID())
+	require.True(t, errors.Is(err, blockstore.ErrNoTransaction))
+
+	index, _, err = srvc.LookupTx(second.GetID())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), index)
+}
+
+func TestService_FailLookupTx(t *testing.T) {
+	tx := makeTx(t, 0, fake.NewSigner())
+
+	index := blockstore.NewTxIndex()
+	require.NoError(t, index.Index(makeTxBlock(t, 0, simple.NewTransactionResult(tx, true, ""))))
+
+	srvc := &Service{processor: newProcessor(), txIndex: index}
+	srvc.blocks = blockstore.NewInMemory()
+
+	_, _, err := srvc.LookupTx(tx.GetID())
+	require.EqualError(t, err, "reading block 0: block not found: no block")
+
+	// The index points to a block without the transaction.
+	srvc.blocks = blockstore.NewInMemory()
+	storeTxBlock(t, srvc.blocks)
+
+	_, _, err = srvc.LookupTx(tx.GetID())
+	require.EqualError(t, err, fmt.Sprintf("transaction %#x missing in block 0", tx.GetID()))
+
+	srvc.txIndex = blockstore.NewTxIndex()
+	srvc.blocks = badGetStore{BlockStore: srvc.blocks}
+
+	err = srvc.rebuildTxIndex()
+	require.EqualError(t, err, fake.Err("reading block 0"))
+}
+
+func TestService_Canceled_OnTransaction(t *testing.T) {
+	watcher := newCountingWatcher()
+
+	srvc := &Service{processor: newProcessor()}
+	srvc.watcher = watcher
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	srvc.OnTransaction(ctx, []byte{0xaa}, func(validation.TransactionResult, uint64) {
+		t.Fatal("callback must not be invoked")
+	})
+
+	require.Equal(t, 1, watcher.Len())
+
+	cancel()
+
+	require.Eventually(t, func() bool { return watcher.Len() == 0 },
+		time.Second, time.Millisecond)
+}
+
+func TestService_GetEquivocations(t *testing.T) {
+	srvc := &Service{processor: newProcessor()}
+
+	require.Empty(t, srvc.GetEquivocations())
+
+	first, err := types.NewBlock(simple.NewResult(nil), types.WithIndex(1))
+	require.NoError(t, err)
+
+	second, err := types.NewBlock(simple.NewResult(nil), types.WithIndex(1),
+		types.WithTreeRoot(types.Digest{1}))
+	require.NoError(t, err)
+
+	local, err := types.NewBlockLink(types.Digest{}, first,
+		types.WithSignatures(fake.Signature{}, fake.Signature{}))
+	require.NoError(t, err)
+
+	remote, err := types.NewBlockLink(types.Digest{}, second,
+		types.WithSignatures(fake.Signature{}, fake.Signature{}))
+	require.NoError(t, err)
+
+	srvc.reportConflict(1, local.Reduce(), remote.Reduce())
+
+	evidences := srvc.GetEquivocations()
+	require.Len(t, evidences, 1)
+	require.Equal(t, uint64(1), evidences[0].Index)
+	require.Equal(t, local.GetTo(), evidences[0].Local.GetTo())
+	require.Equal(t, remote.GetTo(), evidences[0].Remote.GetTo())
+	require.NotNil(t, evidences[0].Local.GetPrepareSignature())
+	require.NotNil(t, evidences[0].Remote.GetCommitSignature())
+}
+
+func TestService_GetBlock(t *testing.T) {
+	srvc := &Service{processor: newProcessor()}
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.blocks = blockstore.NewInMemory()
+
+	_, err := srvc.GetBlock(0)
+	require.EqualError(t, err, "genesis block is not set")
+
+	_, err = srvc.GetBlockByHash(types.Digest{})
+	require.EqualError(t, err, "genesis block is not set")
+
+	require.NoError(t, srvc.genesis.Set(types.Genesis{}))
+
+	links := make([]types.BlockLink, 3)
+
+	prev := types.Digest{}
+	for i := range links {
+		block, err := types.NewBlock(simple.NewResult(nil), types.WithIndex(uint64(i)))
+		require.NoError(t, err)
+
+		links[i], err = types.NewBlockLink(prev, block)
+		require.NoError(t, err)
+		require.NoError(t, srvc.blocks.Store(links[i]))
+
+		prev = links[i].GetTo()
+	}
+
+	for i, link := range links {
+		block, err := srvc.GetBlock(uint64(i))
+		require.NoError(t, err)
+		require.Equal(t, uint64(i), block.GetIndex())
+		require.Equal(t, link.GetTo(), block.GetHash())
+
+		block, err = srvc.GetBlockByHash(link.GetTo())
+		require.NoError(t, err)
+		require.Equal(t, uint64(i), block.GetIndex())
+	}
+
+	_, err = srvc.GetBlock(3)
+	require.EqualError(t, err, "reading block 3: block not found: no block")
+	require.True(t, errors.Is(err, blockstore.ErrNoBlock))
+
+	_, err = srvc.GetBlockByHash(types.Digest{1})
+	require.EqualError(t, err, "reading block '01000000': block not found: no block")
+	require.True(t, errors.Is(err, blockstore.ErrNoBlock))
+}
+
+func TestService_Status(t *testing.T) {
+	nodes, ro, clean := makeAuthority(t, 4)
+	defer clean()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	err := srvc.doPBFT(ctx)
-	require.EqualError(t, err,
-		fake.Err("creating block failed: fingerprint failed: couldn't write index"))
-}
+	require.Equal(t, ServiceStatus{LastIndex: -1}, nodes[0].service.Status())
 
-func TestService_FailPrepare_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
-	srvc.val = fakeValidation{}
-	srvc.tree = blockstore.NewTreeCache(fakeTree{})
-	srvc.pbftsm = fakeSM{err: fake.GetError()}
-	srvc.pool = mem.NewPool()
-	srvc.hashFactory = crypto.NewSha256Factory()
-	srvc.blocks = blockstore.NewInMemory()
+	err := nodes[0].service.Setup(ctx, ro)
+	require.NoError(t, err)
 
-	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+	status := nodes[0].service.Status()
+	require.True(t, status.HasGenesis)
+	require.True(t, status.IsLeader)
+	require.Equal(t, int64(-1), status.LastIndex)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	events := nodes[0].service.Watch(ctx)
 
-	err := srvc.doPBFT(ctx)
-	require.EqualError(t, err, fake.Err("pbft prepare failed"))
-}
+	err = nodes[0].pool.Add(makeTx(t, 0, nodes[0].signer))
+	require.NoError(t, err)
 
-func TestService_FailReadRoster_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
-	srvc.val = fakeValidation{}
-	srvc.tree = blockstore.NewTreeCache(fakeTree{err: fake.GetError()})
-	srvc.pbftsm = fakeSM{}
-	srvc.pool = mem.NewPool()
-	srvc.hashFactory = crypto.NewSha256Factory()
-	srvc.blocks = blockstore.NewInMemory()
+	waitEvent(t, events)
 
-	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+	require.Equal(t, ServiceStatus{HasGenesis: true, IsLeader: true},
+		nodes[0].service.Status())
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	status = nodes[1].service.Status()
+	require.True(t, status.HasGenesis)
+	require.False(t, status.IsLeader)
 
-	err := srvc.doPBFT(ctx)
-	require.EqualError(t, err, fake.Err("read roster failed: read from tree"))
+	// The node knows about a block it has not stored yet.
+	srvc := &Service{processor: newProcessor()}
+	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.blocks = blockstore.NewInMemory()
+	srvc.sync = fakeSync{latest: 2}
+
+	require.Equal(t, ServiceStatus{LastIndex: -1, Syncing: true}, srvc.Status())
 }
 
-func TestService_FailPrepareSig_DoPBFT(t *testing.T) {
+func TestService_GetBlocks(t *testing.T) {
 	srvc := &Service{processor: newProcessor()}
-	srvc.val = fakeValidation{}
-	srvc.tree = blockstore.NewTreeCache(fakeTree{})
-	srvc.pbftsm = fakeSM{}
-	srvc.pool = mem.NewPool()
-	srvc.hashFactory = crypto.NewSha256Factory()
+	srvc.genesis = blockstore.NewGenesisStore()
 	srvc.blocks = blockstore.NewInMemory()
-	srvc.actor = fakeCosiActor{err: fake.GetError()}
-	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
 
-	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+	_, err := srvc.GetBlocks(0, 0)
+	require.EqualError(t, err, "genesis block is not set")
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	require.NoError(t, srvc.genesis.Set(types.Genesis{}))
 
-	err := srvc.doPBFT(ctx)
-	require.EqualError(t, err, fake.Err("prepare signature failed"))
-}
+	_, err = srvc.GetBlocks(0, 0)
+	require.EqualError(t, err, "block 0 is after the head -1: no block")
+	require.True(t, errors.Is(err, blockstore.ErrNoBlock))
 
-func TestService_FailCommitSign_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
-	srvc.val = fakeValidation{}
-	srvc.tree = blockstore.NewTreeCache(fakeTree{})
-	srvc.pbftsm = fakeSM{}
-	srvc.pool = mem.NewPool()
-	srvc.hashFactory = crypto.NewSha256Factory()
-	srvc.blocks = blockstore.NewInMemory()
-	srvc.actor = fakeCosiActor{
-		err:     fake.GetError(),
-		counter: fake.NewCounter(1),
+	prev := types.Digest{}
+	for i := 0; i < 4; i++ {
+		block, err := types.NewBlock(simple.NewResult(nil), types.WithIndex(uint64(i)))
+		require.NoError(t, err)
+
+		link, err := types.NewBlockLink(prev, block)
+		require.NoError(t, err)
+		require.NoError(t, srvc.blocks.Store(link))
+
+		prev = link.GetTo()
 	}
-	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
 
-	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+	blocks, err := srvc.GetBlocks(1, 3)
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	for i, block := range blocks {
+		require.Equal(t, uint64(i+1), block.GetIndex())
+	}
 
-	err := srvc.doPBFT(ctx)
-	require.EqualError(t, err, fake.Err("commit signature failed"))
-}
+	blocks, err = srvc.GetBlocks(2, 2)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
 
-func TestService_FailPropagation_DoPBFT(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
-	srvc.val = fakeValidation{}
-	srvc.tree = blockstore.NewTreeCache(fakeTree{})
-	srvc.pbftsm = fakeSM{}
-	srvc.pool = mem.NewPool()
-	srvc.hashFactory = crypto.NewSha256Factory()
-	srvc.blocks = blockstore.NewInMemory()
-	srvc.actor = fakeCosiActor{}
-	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
-	srvc.rpc = fake.NewBadRPC()
+	_, err = srvc.GetBlocks(3, 1)
+	require.EqualError(t, err, "invalid range: 3 > 1")
 
-	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
+	_, err = srvc.GetBlocks(2, 4)
+	require.EqualError(t, err, "block 4 is after the head 3: no block")
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	_, err = srvc.GetBlocks(0, MaxBlocksRange)
+	require.EqualError(t, err, "range of 101 blocks exceeds the limit of 100")
 
-	err := srvc.doPBFT(ctx)
-	require.EqualError(t, err, fake.Err("propagation failed"))
+	require.NoError(t, srvc.blocks.Prune(2))
+	_, err = srvc.GetBlocks(0, 3)
+	require.True(t, errors.Is(err, blockstore.ErrPruned))
+	require.Regexp(t, "^reading block 0: ", err.Error())
 }
 
-func TestService_FailWakeUp_DoPBFT(t *testing.T) {
-	rpc := fake.NewRPC()
-	rpc.Done()
-
+func TestService_GetVerifiableBlock(t *testing.T) {
 	srvc := &Service{processor: newProcessor()}
-	srvc.val = fakeValidation{}
-	srvc.tree = blockstore.NewTreeCache(fakeTree{})
-	srvc.pbftsm = fakeSM{}
-	srvc.pool = mem.NewPool()
-	srvc.hashFactory = crypto.NewSha256Factory()
-	srvc.blocks = blockstore.NewInMemory()
-	srvc.actor = fakeCosiActor{}
-	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
-	srvc.rpc = rpc
 	srvc.genesis = blockstore.NewGenesisStore()
+	srvc.blocks = blockstore.NewInMemory()
 
-	srvc.pool.Add(makeTx(t, 0, fake.NewSigner()))
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	_, err := srvc.GetVerifiableBlock(0)
+	require.EqualError(t, err, "genesis block is not set")
 
-	err := srvc.doPBFT(ctx)
-	require.EqualError(t, err, "wake up failed: read genesis failed: missing genesis block")
-}
+	require.NoError(t, srvc.genesis.Set(types.Genesis{}))
 
-func TestService_WakeUp(t *testing.T) {
-	rpc := fake.NewRPC()
+	_, err = srvc.GetVerifiableBlock(0)
+	require.EqualError(t, err, "reading chain: store is empty")
 
-	srvc := &Service{processor: newProcessor()}
-	srvc.tree = blockstore.NewTreeCache(fakeTree{})
-	srvc.genesis = blockstore.NewGenesisStore()
-	srvc.genesis.Set(types.Genesis{})
-	srvc.rosterFac = authority.NewFactory(fake.AddressFactory{}, fake.PublicKeyFactory{})
-	srvc.rpc = rpc
+	prev := types.Digest{}
+	for i := 0; i < 3; i++ {
+		block, err := types.NewBlock(simple.NewResult(nil), types.WithIndex(uint64(i)))
+		require.NoError(t, err)
 
-	ctx := context.Background()
+		link, err := types.NewBlockLink(prev, block)
+		require.NoError(t, err)
+		require.NoError(t, srvc.blocks.Store(link))
 
-	rpc.SendResponseWithError(fake.NewAddress(5), fake.GetError())
-	rpc.Done()
-	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+		prev = link.GetTo()
+	}
 
-	err := srvc.wakeUp(ctx, ro)
+	chain, err := srvc.GetVerifiableBlock(1)
 	require.NoError(t, err)
+	require.Equal(t, uint64(1), chain.GetBlock().GetIndex())
+	require.Len(t, chain.GetLinks(), 2)
+	require.Equal(t, types.Digest{}, chain.GetLinks()[0].GetFrom())
 
-	srvc.tree.Set(fakeTree{err: fake.GetError()})
-	err = srvc.wakeUp(ctx, ro)
-	require.EqualError(t, err, fake.Err("read roster failed: read from tree"))
+	_, err = srvc.GetVerifiableBlock(3)
+	require.EqualError(t, err, "block 3 not found: no block")
 
-	srvc.tree.Set(fakeTree{})
-	srvc.rpc = fake.NewBadRPC()
-	err = srvc.wakeUp(ctx, ro)
-	require.EqualError(t, err, fake.Err("rpc failed"))
+	require.NoError(t, srvc.blocks.Prune(2))
+
+	_, err = srvc.GetVerifiableBlock(1)
+	require.EqualError(t, err, "reading block 1: block 1 is pruned: block pruned")
 }
 
-func TestService_GetProof(t *testing.T) {
+func TestService_Prune(t *testing.T) {
 	srvc := &Service{processor: newProcessor()}
 	srvc.tree = blockstore.NewTreeCache(fakeTree{})
 	srvc.blocks = blockstore.NewInMemory()
-	srvc.blocks.Store(makeBlock(t, types.Digest{}))
 
-	proof, err := srvc.GetProof([]byte("A"))
-	require.NoError(t, err)
-	require.NotNil(t, proof)
+	err := srvc.Prune(0)
+	require.EqualError(t, err, "failed to read last block: store empty: no block")
 
-	srvc.tree.Set(fakeTree{err: fake.GetError()})
-	_, err = srvc.GetProof([]byte("A"))
-	require.EqualError(t, err, fake.Err("reading path"))
+	prev := types.Digest{}
+	for i := 0; i < 3; i++ {
+		block, err := types.NewBlock(simple.NewResult(nil), types.WithIndex(uint64(i)))
+		require.NoError(t, err)
 
-	srvc.tree.Set(fakeTree{})
-	srvc.blocks = blockstore.NewInMemory()
-	_, err = srvc.GetProof([]byte("A"))
-	require.EqualError(t, err, "reading chain: store is empty")
-}
+		link, err := types.NewBlockLink(prev, block)
+		require.NoError(t, err)
+		require.NoError(t, srvc.blocks.Store(link))
 
-func TestService_GetStore(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
-	srvc.tree = blockstore.NewTreeCache(fakeTree{})
+		prev = link.GetTo()
+	}
 
-	require.IsType(t, fakeTree{}, srvc.GetStore())
-}
+	err = srvc.Prune(3)
+	require.EqualError(t, err, "cannot prune past the last committed block 2")
 
-func TestService_GetRoster(t *testing.T) {
-	srvc := &Service{processor: newProcessor()}
-	srvc.tree = blockstore.NewTreeCache(fakeTree{})
-	srvc.rosterFac = fakeRosterFac{}
+	err = srvc.Prune(2)
+	require.NoError(t, err)
 
-	roster, err := srvc.GetRoster()
+	_, err = srvc.blocks.GetByIndex(1)
+	require.True(t, errors.Is(err, blockstore.ErrPruned))
+
+	// The proofs still contain the whole chain.
+	proof, err := srvc.GetProof([]byte("A"))
 	require.NoError(t, err)
-	require.Equal(t, 3, roster.Len())
+	require.Len(t, proof.(Proof).chain.GetLinks(), 3)
+
+	srvc.blocks = badPruneStore{BlockStore: srvc.blocks}
+	err = srvc.Prune(1)
+	require.EqualError(t, err, fake.Err("pruning failed"))
 }
 
 func TestService_PoolFilter(t *testing.T) {
@@ -850,9 +2095,104 @@ func TestService_PoolFilter(t *testing.T) {
 	require.EqualError(t, err, fake.Err("unacceptable transaction"))
 }
 
+func TestService_SortTransactions(t *testing.T) {
+	alice := bls.NewSigner()
+	bob := bls.NewSigner()
+
+	txs := []txn.Transaction{
+		makeTx(t, 1, bob),
+		makeTx(t, 0, alice),
+		makeTx(t, 1, alice),
+		makeTx(t, 0, bob),
+	}
+
+	reversed := make([]txn.Transaction, len(txs))
+	for i, tx := range txs {
+		reversed[len(txs)-1-i] = tx
+	}
+
+	sortTransactions(txs)
+	sortTransactions(reversed)
+	require.Equal(t, txs, reversed)
+
+	// Both nodes must produce the same block whatever the order of the pool.
+	first, err := types.NewBlock(makeResult(txs))
+	require.NoError(t, err)
+
+	second, err := types.NewBlock(makeResult(reversed))
+	require.NoError(t, err)
+
+	require.Equal(t, first.GetHash(), second.GetHash())
+
+	// Transactions of the same identity are sorted by nonce.
+	for i := 0; i < len(txs); i += 2 {
+		require.True(t, txs[i].GetIdentity().Equal(txs[i+1].GetIdentity()))
+		require.Less(t, txs[i].GetNonce(), txs[i+1].GetNonce())
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
+func makeResult(txs []txn.Transaction) validation.Result {
+	results := make([]simple.TransactionResult, len(txs))
+	for i, tx := range txs {
+		results[i] = simple.NewTransactionResult(tx, true, "")
+	}
+
+	return simple.NewResult(results)
+}
+
+type fakeWatchStore struct {
+	blockstore.BlockStore
+
+	links []types.BlockLink
+}
+
+func (s fakeWatchStore) Watch(context.Context) <-chan types.BlockLink {
+	ch := make(chan types.BlockLink, len(s.links))
+	for _, link := range s.links {
+		ch <- link
+	}
+
+	close(ch)
+
+	return ch
+}
+
+type badPruneStore struct {
+	blockstore.BlockStore
+}
+
+func (badPruneStore) Prune(uint64) error {
+	return fake.GetError()
+}
+
+type badGetStore struct {
+	blockstore.BlockStore
+}
+
+func (badGetStore) Len() uint64 {
+	return 1
+}
+
+func (badGetStore) GetByIndex(uint64) (types.BlockLink, error) {
+	return nil, fake.GetError()
+}
+
+type fakeMetrics struct {
+	commits []uint64
+	views   []uint16
+}
+
+func (m *fakeMetrics) ObserveBlockCommit(index uint64, txCount int, d time.Duration) {
+	m.commits = append(m.commits, index)
+}
+
+func (m *fakeMetrics) ObserveViewChange(leader uint16, d time.Duration) {
+	m.views = append(m.views, leader)
+}
+
 func checkProof(t *testing.T, p Proof, s *Service) {
 	genesis, err := s.genesis.Get()
 	require.NoError(t, err)
@@ -893,6 +2233,28 @@ func makeTx(t *testing.T, nonce uint64, signer crypto.Signer) txn.Transaction {
 	return tx
 }
 
+func makeTxBlock(t *testing.T, index uint64, results ...simple.TransactionResult) types.Block {
+	block, err := types.NewBlock(simple.NewResult(results), types.WithIndex(index))
+	require.NoError(t, err)
+
+	return block
+}
+
+func storeTxBlock(t *testing.T, blocks blockstore.BlockStore, results ...simple.TransactionResult) {
+	from := types.Digest{}
+	if blocks.Len() > 0 {
+		last, err := blocks.Last()
+		require.NoError(t, err)
+
+		from = last.GetTo()
+	}
+
+	link, err := types.NewBlockLink(from, makeTxBlock(t, blocks.Len(), results...))
+	require.NoError(t, err)
+
+	require.NoError(t, blocks.Store(link))
+}
+
 func makeRosterTx(t *testing.T, nonce uint64, roster authority.Authority, signer crypto.Signer) txn.Transaction {
 	data, err := roster.Serialize(json.NewContext())
 	require.NoError(t, err)
@@ -920,7 +2282,7 @@ func waitEvent(t *testing.T, events <-chan ordering.Event) ordering.Event {
 	}
 }
 
-func makeAuthority(t *testing.T, n int) ([]testNode, authority.Authority, func()) {
+func makeAuthority(t *testing.T, n int, opts ...ServiceOption) ([]testNode, authority.Authority, func()) {
 	manager := minoch.NewManager()
 
 	addrs := make([]mino.Address, n)
@@ -953,6 +2315,7 @@ func makeAuthority(t *testing.T, n int) ([]testNode, authority.Authority, func()
 
 		exec := native.NewExecution()
 		exec.Set(testContractName, testExec{})
+		noop.RegisterContract(exec, noop.NewContract())
 
 		accessSrvc := darc.NewService(json.NewContext())
 
@@ -971,9 +2334,11 @@ func makeAuthority(t *testing.T, n int) ([]testNode, authority.Authority, func()
 			DB:         db,
 		}
 
-		srv, err := NewService(param)
+		srv, err := NewService(param, opts...)
 		require.NoError(t, err)
 
+		RegisterUpgradeContract(exec, accessSrvc, srv)
+
 		nodes[i] = testNode{
 			onet:    m,
 			service: srv,
@@ -1045,6 +2410,14 @@ type fakeValidation struct {
 	err error
 }
 
+func (val fakeValidation) GetFactory() validation.ResultFactory {
+	return simple.NewResultFactory(signed.NewTransactionFactory())
+}
+
+func (val fakeValidation) GetNonce(store.Readable, access.Identity) (uint64, error) {
+	return 0, val.err
+}
+
 func (val fakeValidation) Accept(store.Readable, txn.Transaction, validation.Leeway) error {
 	return val.err
 }
@@ -1088,3 +2461,37 @@ type fakeAccess struct {
 func (srvc fakeAccess) Grant(store.Snapshot, access.Credential, ...access.Identity) error {
 	return srvc.err
 }
+
+type countingWatcher struct {
+	*core.Watcher
+
+	sync.Mutex
+	count int
+}
+
+func newCountingWatcher() *countingWatcher {
+	return &countingWatcher{Watcher: core.NewWatcher()}
+}
+
+func (w *countingWatcher) Add(obs core.Observer) {
+	w.Lock()
+	w.count++
+	w.Unlock()
+
+	w.Watcher.Add(obs)
+}
+
+func (w *countingWatcher) Remove(obs core.Observer) {
+	w.Lock()
+	w.count--
+	w.Unlock()
+
+	w.Watcher.Remove(obs)
+}
+
+func (w *countingWatcher) Len() int {
+	w.Lock()
+	defer w.Unlock()
+
+	return w.count
+}
@@ -0,0 +1,56 @@
+// This file contains the implementation of the policies selecting the leader of
+// a round.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package pbft
+
+// LeaderPolicy is the interface of the policy that selects the leader of a
+// round. The selection must only depend on its arguments so that all the
+// participants agree on the same leader.
+type LeaderPolicy interface {
+	// GetLeader returns the index of the leader in a roster of n members. The
+	// view is the index of the leader agreed by the last view change, and the
+	// index is the one of the next block.
+	GetLeader(view uint16, index uint64, n int) uint16
+}
+
+// stickyPolicy is a leader policy that keeps the same leader until a view
+// change happens.
+//
+// - implements pbft.LeaderPolicy
+type stickyPolicy struct{}
+
+// NewStickyPolicy returns a leader policy that keeps the current leader until a
+// view change elects the next one.
+func NewStickyPolicy() LeaderPolicy {
+	return stickyPolicy{}
+}
+
+// GetLeader implements pbft.LeaderPolicy. It returns the leader of the view.
+func (stickyPolicy) GetLeader(view uint16, index uint64, n int) uint16 {
+	return view
+}
+
+// roundRobinPolicy is a leader policy that moves to the next member of the
+// roster for each committed block.
+//
+// - implements pbft.LeaderPolicy
+type roundRobinPolicy struct{}
+
+// NewRoundRobinPolicy returns a leader policy that rotates the leader over the
+// roster every time a block is committed, starting from the leader of the view.
+func NewRoundRobinPolicy() LeaderPolicy {
+	return roundRobinPolicy{}
+}
+
+// GetLeader implements pbft.LeaderPolicy. It returns the leader of the view
+// shifted by the index of the block.
+func (roundRobinPolicy) GetLeader(view uint16, index uint64, n int) uint16 {
+	if n <= 0 {
+		return view
+	}
+
+	return uint16((uint64(view) + index) % uint64(n))
+}
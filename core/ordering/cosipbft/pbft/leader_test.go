@@ -0,0 +1,25 @@
+package pbft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStickyPolicy_GetLeader(t *testing.T) {
+	policy := NewStickyPolicy()
+
+	require.Equal(t, uint16(0), policy.GetLeader(0, 0, 3))
+	require.Equal(t, uint16(0), policy.GetLeader(0, 5, 3))
+	require.Equal(t, uint16(2), policy.GetLeader(2, 5, 3))
+}
+
+func TestRoundRobinPolicy_GetLeader(t *testing.T) {
+	policy := NewRoundRobinPolicy()
+
+	require.Equal(t, uint16(0), policy.GetLeader(0, 0, 3))
+	require.Equal(t, uint16(1), policy.GetLeader(0, 1, 3))
+	require.Equal(t, uint16(0), policy.GetLeader(0, 3, 3))
+	require.Equal(t, uint16(1), policy.GetLeader(2, 2, 3))
+	require.Equal(t, uint16(2), policy.GetLeader(2, 0, 0))
+}
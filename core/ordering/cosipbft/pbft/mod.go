@@ -155,6 +155,8 @@ type pbftsm struct {
 	tree       blockstore.TreeCache
 	authReader AuthorityReader
 	db         kv.DB
+	policy     LeaderPolicy
+	onConflict func(index uint64, local, remote types.Link)
 
 	// verifierFac creates a verifier for the aggregated signature.
 	verifierFac crypto.VerifierFactory
@@ -177,6 +179,12 @@ type StateMachineParam struct {
 	Tree            blockstore.TreeCache
 	AuthorityReader AuthorityReader
 	DB              kv.DB
+	// LeaderPolicy selects the leader of a round. The leader stays the same
+	// until a view change when it is not set.
+	LeaderPolicy LeaderPolicy
+	// OnConflict is called, if defined, when a block collectively signed for
+	// an index differs from the one already stored.
+	OnConflict func(index uint64, local, remote types.Link)
 }
 
 // NewStateMachine returns a new state machine.
@@ -194,6 +202,8 @@ func NewStateMachine(param StateMachineParam) StateMachine {
 		db:          param.DB,
 		state:       NoneState,
 		authReader:  param.AuthorityReader,
+		policy:      param.LeaderPolicy,
+		onConflict:  param.OnConflict,
 	}
 }
 
@@ -218,7 +228,7 @@ func (m *pbftsm) GetLeader() (mino.Address, error) {
 	}
 
 	iter := roster.AddressIterator()
-	iter.Seek(int(m.round.leader))
+	iter.Seek(int(m.getLeader(roster.Len())))
 
 	return iter.GetNext(), nil
 }
@@ -270,7 +280,7 @@ func (m *pbftsm) Prepare(from mino.Address, block types.Block) (types.Digest, er
 
 	_, index := roster.GetPublicKey(from)
 
-	if uint16(index) != m.round.leader {
+	if uint16(index) != m.getLeader(roster.Len()) {
 		return id, xerrors.Errorf("'%v' is not the leader", from)
 	}
 
@@ -534,6 +544,11 @@ func (m *pbftsm) CatchUp(link types.BlockLink) error {
 		return xerrors.Errorf("failed to read roster: %v", err)
 	}
 
+	err = m.checkConflict(link, roster)
+	if err != nil {
+		return err
+	}
+
 	err = m.verifyPrepare(m.tree.Get(), link.GetBlock(), &r, roster)
 	if err != nil {
 		return xerrors.Errorf("prepare failed: %v", err)
@@ -702,6 +717,12 @@ func (m *pbftsm) verifyFinalize(r *round, sig crypto.Signature, ro authority.Aut
 			return xerrors.Errorf("creating link: %v", err)
 		}
 
+		// A block might have been caught up for the index in the meantime.
+		err = m.checkConflict(link, ro)
+		if err != nil {
+			return err
+		}
+
 		err = m.blocks.WithTx(txn).Store(link)
 		if err != nil {
 			return xerrors.Errorf("store block: %v", err)
@@ -723,6 +744,58 @@ func (m *pbftsm) verifyFinalize(r *round, sig crypto.Signature, ro authority.Aut
 	return nil
 }
 
+// checkConflict compares the link with the one stored at the same index, if
+// any. When they differ and the signatures of the link are valid, the
+// participants have signed two blocks for that index and the conflict is
+// reported before the link is refused.
+func (m *pbftsm) checkConflict(link types.BlockLink, ro authority.Authority) error {
+	index := link.GetBlock().GetIndex()
+	if index >= m.blocks.Len() {
+		return nil
+	}
+
+	local, err := m.blocks.GetByIndex(index)
+	if err != nil {
+		return xerrors.Errorf("failed to read block %d: %v", index, err)
+	}
+
+	if local.GetTo() == link.GetTo() {
+		return nil
+	}
+
+	verifier, err := m.verifierFac.FromAuthority(ro)
+	if err != nil {
+		return xerrors.Errorf("couldn't make verifier: %v", err)
+	}
+
+	err = verifier.Verify(link.GetHash().Bytes(), link.GetPrepareSignature())
+	if err != nil {
+		return xerrors.Errorf("conflicting block with invalid prepare signature: %v", err)
+	}
+
+	buffer, err := link.GetPrepareSignature().MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("couldn't marshal signature: %v", err)
+	}
+
+	err = verifier.Verify(buffer, link.GetCommitSignature())
+	if err != nil {
+		return xerrors.Errorf("conflicting block with invalid commit signature: %v", err)
+	}
+
+	m.logger.Warn().
+		Uint64("index", index).
+		Stringer("local", local.GetTo()).
+		Stringer("remote", link.GetTo()).
+		Msg("conflicting blocks detected")
+
+	if m.onConflict != nil {
+		m.onConflict(index, local.Reduce(), link.Reduce())
+	}
+
+	return xerrors.Errorf("conflicting block at index %d", index)
+}
+
 func (m *pbftsm) init() (authority.Authority, error) {
 	roster, err := m.authReader(m.tree.Get())
 	if err != nil {
@@ -740,6 +813,16 @@ func (m *pbftsm) init() (authority.Authority, error) {
 	return roster, nil
 }
 
+// getLeader returns the index of the leader of the current round for a roster
+// of n members.
+func (m *pbftsm) getLeader(n int) uint16 {
+	if m.policy == nil {
+		return m.round.leader
+	}
+
+	return m.policy.GetLeader(m.round.leader, m.blocks.Len(), n)
+}
+
 func (m *pbftsm) setState(s State) {
 	m.state = s
 	m.watcher.Notify(s)
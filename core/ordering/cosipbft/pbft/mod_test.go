@@ -69,6 +69,44 @@ func TestStateMachine_GetLeader(t *testing.T) {
 	require.EqualError(t, err, fake.Err("failed to read roster"))
 }
 
+func TestStateMachine_RoundRobin_GetLeader(t *testing.T) {
+	roster := fake.NewAuthority(3, fake.NewSigner)
+
+	sm := &pbftsm{
+		tree:   blockstore.NewTreeCache(badTree{}),
+		blocks: blockstore.NewInMemory(),
+		policy: NewRoundRobinPolicy(),
+		authReader: func(hashtree.Tree) (authority.Authority, error) {
+			return authority.FromAuthority(roster), nil
+		},
+	}
+
+	prev := types.Digest{}
+
+	// The leader advances by one for each committed block, and wraps around
+	// the roster.
+	for i := 0; i < 4; i++ {
+		leader, err := sm.GetLeader()
+		require.NoError(t, err)
+		require.Equal(t, roster.GetAddress(i%3), leader)
+
+		block, err := types.NewBlock(simple.NewResult(nil), types.WithIndex(uint64(i)))
+		require.NoError(t, err)
+
+		link, err := types.NewBlockLink(prev, block)
+		require.NoError(t, err)
+
+		require.NoError(t, sm.blocks.Store(link))
+		prev = link.GetTo()
+	}
+
+	// A view change shifts the rotation.
+	sm.round.leader = 1
+	leader, err := sm.GetLeader()
+	require.NoError(t, err)
+	require.Equal(t, roster.GetAddress(2), leader)
+}
+
 func TestStateMachine_GetViews(t *testing.T) {
 	sm := &pbftsm{}
 	require.Len(t, sm.GetViews(), 0)
@@ -814,6 +852,119 @@ func TestStateMachine_CatchUp(t *testing.T) {
 	require.EqualError(t, err, fake.Err("finalize failed: couldn't marshal signature"))
 }
 
+func TestStateMachine_Conflict_CatchUp(t *testing.T) {
+	tree, db, clean := makeTree(t)
+	defer clean()
+
+	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	var evidences [][]types.Link
+
+	param := StateMachineParam{
+		Validation:      simple.NewService(fakeExec{}, nil),
+		VerifierFactory: fake.VerifierFactory{},
+		Blocks:          blockstore.NewInMemory(),
+		Genesis:         blockstore.NewGenesisStore(),
+		Tree:            blockstore.NewTreeCache(tree),
+		AuthorityReader: func(hashtree.Tree) (authority.Authority, error) {
+			return ro, nil
+		},
+		DB: db,
+		OnConflict: func(index uint64, local, remote types.Link) {
+			require.Equal(t, uint64(0), index)
+			evidences = append(evidences, []types.Link{local, remote})
+		},
+	}
+
+	param.Genesis.Set(types.Genesis{})
+
+	sm := NewStateMachine(param).(*pbftsm)
+
+	root := types.Digest{}
+	copy(root[:], tree.GetRoot())
+
+	local := makeSignedLink(t, root)
+	require.NoError(t, sm.blocks.Store(local))
+
+	// The same block is refused without being reported.
+	err := sm.CatchUp(local)
+	require.EqualError(t, err, "prepare failed: mismatch index 0 != 1")
+	require.Empty(t, evidences)
+
+	remote := makeSignedLink(t, types.Digest{1})
+
+	err = sm.CatchUp(remote)
+	require.EqualError(t, err, "conflicting block at index 0")
+	require.Len(t, evidences, 1)
+	require.Equal(t, local.GetTo(), evidences[0][0].GetTo())
+	require.Equal(t, remote.GetTo(), evidences[0][1].GetTo())
+	require.Equal(t, remote.GetPrepareSignature(), evidences[0][1].GetPrepareSignature())
+	require.Equal(t, remote.GetCommitSignature(), evidences[0][1].GetCommitSignature())
+
+	// A block without valid signatures is not an evidence.
+	sm.verifierFac = fake.NewVerifierFactory(fake.NewBadVerifier())
+	err = sm.CatchUp(remote)
+	require.EqualError(t, err,
+		fake.Err("conflicting block with invalid prepare signature"))
+	require.Len(t, evidences, 1)
+
+	sm.verifierFac = fake.NewBadVerifierFactory()
+	err = sm.CatchUp(remote)
+	require.EqualError(t, err, fake.Err("couldn't make verifier"))
+
+	sm.blocks = badBlockStore{length: 1}
+	err = sm.CatchUp(remote)
+	require.EqualError(t, err, fake.Err("failed to read block 0"))
+}
+
+func TestStateMachine_Conflict_Finalize(t *testing.T) {
+	tree, db, clean := makeTree(t)
+	defer clean()
+
+	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	var evidences []types.Link
+
+	param := StateMachineParam{
+		VerifierFactory: fake.NewVerifierFactory(fake.Verifier{}),
+		Blocks:          blockstore.NewInMemory(),
+		Genesis:         blockstore.NewGenesisStore(),
+		Tree:            blockstore.NewTreeCache(tree),
+		AuthorityReader: func(hashtree.Tree) (authority.Authority, error) {
+			return ro, nil
+		},
+		DB: db,
+		OnConflict: func(index uint64, local, remote types.Link) {
+			evidences = append(evidences, local, remote)
+		},
+	}
+
+	param.Genesis.Set(types.Genesis{})
+
+	sm := NewStateMachine(param).(*pbftsm)
+
+	local := makeSignedLink(t, types.Digest{})
+	require.NoError(t, sm.blocks.Store(local))
+
+	block, err := types.NewBlock(simple.NewResult(nil), types.WithIndex(0),
+		types.WithTreeRoot(types.Digest{1}))
+	require.NoError(t, err)
+
+	// The block has been caught up while the round for the same index was
+	// committed.
+	sm.state = CommitState
+	sm.round.tree = tree.(hashtree.StagingTree)
+	sm.round.block = block
+	sm.round.prepareSig = fake.Signature{}
+
+	err = sm.Finalize(types.Digest{}, fake.Signature{})
+	require.EqualError(t, err, "database failed: conflicting block at index 0")
+	require.Len(t, evidences, 2)
+	require.Equal(t, local.GetTo(), evidences[0].GetTo())
+	require.Equal(t, block.GetHash(), evidences[1].GetTo())
+	require.Equal(t, uint64(1), sm.blocks.Len())
+}
+
 func TestStateMachine_Watch(t *testing.T) {
 	sm := &pbftsm{
 		watcher: core.NewWatcher(),
@@ -860,6 +1011,21 @@ func makeLink(t *testing.T) types.BlockLink {
 	return link
 }
 
+func makeSignedLink(t *testing.T, root types.Digest) types.BlockLink {
+	block, err := types.NewBlock(simple.NewResult(nil), types.WithTreeRoot(root))
+	require.NoError(t, err)
+
+	opts := []types.LinkOption{
+		types.WithSignatures(fake.Signature{}, fake.Signature{}),
+		types.WithChangeSet(authority.NewChangeSet()),
+	}
+
+	link, err := types.NewBlockLink(types.Digest{}, block, opts...)
+	require.NoError(t, err)
+
+	return link
+}
+
 type fakeExec struct {
 	err error
 }
@@ -893,6 +1059,10 @@ func (s badBlockStore) Last() (types.BlockLink, error) {
 	return nil, fake.GetError()
 }
 
+func (s badBlockStore) GetByIndex(uint64) (types.BlockLink, error) {
+	return nil, fake.GetError()
+}
+
 func (s badBlockStore) Store(types.BlockLink) error {
 	return fake.GetError()
 }
@@ -31,6 +31,9 @@ import (
 var (
 	keyRoster = [32]byte{}
 	keyAccess = [32]byte{1}
+	// keyValidation is apart from the first keys that the controllers of the
+	// native contracts use for their access.
+	keyValidation = [32]byte{0xff}
 )
 
 // Processor processes the messages to run a collective signing PBFT consensus.
@@ -55,14 +58,19 @@ type processor struct {
 	genesis blockstore.GenesisStore
 	blocks  blockstore.BlockStore
 
+	evidences *evidenceLog
+	proofs    *proofCache
+
 	started chan struct{}
 }
 
 func newProcessor() *processor {
 	return &processor{
-		watcher: core.NewWatcher(),
-		context: json.NewContext(),
-		started: make(chan struct{}),
+		watcher:   core.NewWatcher(),
+		context:   json.NewContext(),
+		started:   make(chan struct{}),
+		evidences: &evidenceLog{},
+		proofs:    newProofCache(proofCacheSize),
 	}
 }
 
@@ -255,3 +263,9 @@ func (h *processor) makeAccess(store store.Snapshot, roster authority.Authority)
 
 	return nil
 }
+
+// reportConflict records the evidence of two different blocks signed for the
+// same index.
+func (h *processor) reportConflict(index uint64, local, remote types.Link) {
+	h.evidences.add(index, local, remote)
+}
@@ -304,6 +304,17 @@ func (t fakeTree) Commit() error {
 	return t.errCommit
 }
 
+type countingTree struct {
+	fakeTree
+
+	calls int
+}
+
+func (t *countingTree) GetPath(key []byte) (hashtree.Path, error) {
+	t.calls++
+	return nil, nil
+}
+
 type fakeGenesisStore struct {
 	blockstore.GenesisStore
 
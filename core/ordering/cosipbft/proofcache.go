@@ -0,0 +1,99 @@
+// This file contains the implementation of a cache for the proofs.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package cosipbft
+
+import (
+	"container/list"
+	"sync"
+
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+)
+
+// proofCacheSize is the maximum number of proofs kept in the cache.
+const proofCacheSize = 256
+
+type proofKey struct {
+	block types.Digest
+	key   string
+}
+
+type proofEntry struct {
+	key   proofKey
+	proof Proof
+}
+
+// proofCache is a least-recently-used cache of the proofs. The entries are
+// indexed by the hash of the block the proof has been created for, so that a
+// proof of a superseded block can never be served.
+type proofCache struct {
+	sync.Mutex
+	size    int
+	order   *list.List
+	entries map[proofKey]*list.Element
+}
+
+func newProofCache(size int) *proofCache {
+	return &proofCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[proofKey]*list.Element),
+	}
+}
+
+// get returns the proof of the key for the block, if it exists.
+func (c *proofCache) get(block types.Digest, key []byte) (Proof, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, found := c.entries[proofKey{block: block, key: string(key)}]
+	if !found {
+		return Proof{}, false
+	}
+
+	c.order.MoveToFront(elem)
+
+	return elem.Value.(proofEntry).proof, true
+}
+
+// add stores the proof of the key for the block and evicts the least recently
+// used proof when the cache is full.
+func (c *proofCache) add(block types.Digest, key []byte, proof Proof) {
+	c.Lock()
+	defer c.Unlock()
+
+	pk := proofKey{block: block, key: string(key)}
+
+	elem, found := c.entries[pk]
+	if found {
+		elem.Value = proofEntry{key: pk, proof: proof}
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[pk] = c.order.PushFront(proofEntry{key: pk, proof: proof})
+
+	if c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.entries, last.Value.(proofEntry).key)
+	}
+}
+
+// purge removes all the proofs from the cache.
+func (c *proofCache) purge() {
+	c.Lock()
+	c.order.Init()
+	c.entries = make(map[proofKey]*list.Element)
+	c.Unlock()
+}
+
+// len returns the number of proofs in the cache.
+func (c *proofCache) len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.order.Len()
+}
@@ -0,0 +1,44 @@
+package cosipbft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/types"
+)
+
+func TestProofCache_GetAdd(t *testing.T) {
+	cache := newProofCache(2)
+
+	_, found := cache.get(types.Digest{}, []byte("A"))
+	require.False(t, found)
+
+	cache.add(types.Digest{}, []byte("A"), Proof{})
+	cache.add(types.Digest{}, []byte("B"), Proof{})
+	cache.add(types.Digest{}, []byte("B"), Proof{})
+	require.Equal(t, 2, cache.len())
+
+	_, found = cache.get(types.Digest{}, []byte("A"))
+	require.True(t, found)
+
+	// B is the least recently used and must be evicted.
+	cache.add(types.Digest{}, []byte("C"), Proof{})
+	require.Equal(t, 2, cache.len())
+
+	_, found = cache.get(types.Digest{}, []byte("B"))
+	require.False(t, found)
+
+	_, found = cache.get(types.Digest{1}, []byte("A"))
+	require.False(t, found)
+}
+
+func TestProofCache_Purge(t *testing.T) {
+	cache := newProofCache(2)
+	cache.add(types.Digest{}, []byte("A"), Proof{})
+
+	cache.purge()
+	require.Equal(t, 0, cache.len())
+
+	_, found := cache.get(types.Digest{}, []byte("A"))
+	require.False(t, found)
+}
@@ -0,0 +1,24 @@
+// This file contains the definition of the status of the service.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package cosipbft
+
+// ServiceStatus is a snapshot of the state of the service that can be used to
+// check if a node is ready to participate in the rounds.
+type ServiceStatus struct {
+	// HasGenesis is true when the genesis block is set.
+	HasGenesis bool
+
+	// LastIndex is the index of the last committed block, or -1 when the
+	// chain has no block yet.
+	LastIndex int64
+
+	// IsLeader is true when the node is the leader of the current round.
+	IsLeader bool
+
+	// Syncing is true when the node knows about blocks that it has not stored
+	// yet.
+	Syncing bool
+}
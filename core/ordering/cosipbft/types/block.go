@@ -82,7 +82,9 @@ func WithGenesisHashFactory(fac crypto.HashFactory) GenesisOption {
 	}
 }
 
-// NewGenesis creates a new genesis block with the provided roster.
+// NewGenesis creates a new genesis block with the provided roster. The genesis
+// does not contain any randomness so that any node creating it from the same
+// roster and options gets the same block and thus the same digest.
 func NewGenesis(ro authority.Authority, opts ...GenesisOption) (Genesis, error) {
 	tmpl := genesisTemplate{
 		Genesis: Genesis{
@@ -11,7 +11,9 @@ import (
 	"go.dedis.ch/dela/core/txn/signed"
 	"go.dedis.ch/dela/core/validation"
 	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
 )
 
 func init() {
@@ -46,6 +48,38 @@ func TestGenesis_GetHash(t *testing.T) {
 	require.Equal(t, id, genesis.GetHash())
 }
 
+func TestGenesis_Deterministic(t *testing.T) {
+	ca := fake.NewAuthority(3, fake.NewSigner)
+
+	addrs := make([]mino.Address, 0, ca.Len())
+	pubkeys := make([]crypto.PublicKey, 0, ca.Len())
+
+	iter := ca.PublicKeyIterator()
+	for iter.HasNext() {
+		pubkeys = append(pubkeys, iter.GetNext())
+	}
+
+	addrIter := ca.AddressIterator()
+	for addrIter.HasNext() {
+		addrs = append(addrs, addrIter.GetNext())
+	}
+
+	// Two rosters built independently from the same members must lead to the
+	// same genesis block.
+	first, err := NewGenesis(authority.New(addrs, pubkeys), WithGenesisRoot(Digest{1}))
+	require.NoError(t, err)
+
+	second, err := NewGenesis(authority.FromAuthority(ca), WithGenesisRoot(Digest{1}))
+	require.NoError(t, err)
+
+	require.Equal(t, first.GetHash(), second.GetHash())
+
+	other, err := NewGenesis(authority.FromAuthority(ca), WithGenesisRoot(Digest{2}))
+	require.NoError(t, err)
+
+	require.NotEqual(t, first.GetHash(), other.GetHash())
+}
+
 func TestGenesis_GetRoster(t *testing.T) {
 	ro := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
 
@@ -0,0 +1,164 @@
+// This file contains the implementation of a validation service that follows
+// the version recorded on the chain.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package cosipbft
+
+import (
+	"sync"
+
+	"go.dedis.ch/dela/core/access"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/ordering/cosipbft/contracts/upgrade"
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/core/validation"
+	"golang.org/x/xerrors"
+)
+
+// RegisterUpgradeContract registers the native smart contract to upgrade the
+// validation service of the ordering service. Only the versions registered to
+// the ordering service can be applied.
+func RegisterUpgradeContract(exec *native.Service, srvc access.Service, ordering *Service) {
+	contract := upgrade.NewContract(keyValidation[:], keyAccess[:], srvc, ordering.upgrades)
+
+	upgrade.RegisterContract(exec, contract)
+}
+
+// UpgradeValidation registers the validation service of a new version of the
+// contracts. The service is used from the block following the one that
+// includes an upgrade transaction to this version. As the version is recorded
+// in the store, every node switches at the same height, including after a
+// restart, but the version must be registered on every node beforehand.
+func (s *Service) UpgradeValidation(version string, srvc validation.Service) error {
+	err := s.upgrades.register(version, srvc)
+	if err != nil {
+		return xerrors.Errorf("invalid upgrade: %v", err)
+	}
+
+	s.logger.Info().Str("version", version).Msg("validation version registered")
+
+	return nil
+}
+
+// upgradableValidation is a validation service that forwards the calls to the
+// service of the version written in the store. As a block is validated on top
+// of the store of the previous one, a block that upgrades the version is still
+// validated by the previous service and the new one applies to the next
+// blocks.
+//
+// - implements validation.Service
+// - implements upgrade.Versions
+type upgradableValidation struct {
+	sync.Mutex
+
+	initial  validation.Service
+	versions map[string]validation.Service
+}
+
+func newUpgradableValidation(srvc validation.Service) *upgradableValidation {
+	return &upgradableValidation{
+		initial:  srvc,
+		versions: make(map[string]validation.Service),
+	}
+}
+
+// GetFactory implements validation.Service. It returns the result factory of
+// the initial service, as the blocks must be decoded the same way whatever the
+// service that created them.
+func (v *upgradableValidation) GetFactory() validation.ResultFactory {
+	return v.initial.GetFactory()
+}
+
+// GetNonce implements validation.Service. It returns the nonce of the identity
+// according to the service of the version in the store.
+func (v *upgradableValidation) GetNonce(store store.Readable, ident access.Identity) (uint64, error) {
+	srvc, err := v.serviceOf(store)
+	if err != nil {
+		return 0, xerrors.Errorf("validation service: %v", err)
+	}
+
+	return srvc.GetNonce(store, ident)
+}
+
+// Accept implements validation.Service. It returns nil if the service of the
+// version in the store accepts the transaction.
+func (v *upgradableValidation) Accept(store store.Readable, tx txn.Transaction,
+	leeway validation.Leeway) error {
+
+	srvc, err := v.serviceOf(store)
+	if err != nil {
+		return xerrors.Errorf("validation service: %v", err)
+	}
+
+	return srvc.Accept(store, tx, leeway)
+}
+
+// Validate implements validation.Service. It validates the transactions with
+// the service of the version in the snapshot.
+func (v *upgradableValidation) Validate(snap store.Snapshot, txs []txn.Transaction) (validation.Result, error) {
+	srvc, err := v.serviceOf(snap)
+	if err != nil {
+		return nil, xerrors.Errorf("validation service: %v", err)
+	}
+
+	return srvc.Validate(snap, txs)
+}
+
+func (v *upgradableValidation) register(version string, srvc validation.Service) error {
+	if version == "" {
+		return xerrors.New("empty version")
+	}
+
+	if srvc == nil {
+		return xerrors.New("missing validation service")
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	_, found := v.versions[version]
+	if found {
+		return xerrors.Errorf("version '%s' already registered", version)
+	}
+
+	v.versions[version] = srvc
+
+	return nil
+}
+
+// IsRegistered implements upgrade.Versions. It returns true if the version has
+// a validation service.
+func (v *upgradableValidation) IsRegistered(version string) bool {
+	v.Lock()
+	defer v.Unlock()
+
+	_, found := v.versions[version]
+
+	return found
+}
+
+// serviceOf returns the service of the version in the store, or the initial
+// one when no upgrade happened yet.
+func (v *upgradableValidation) serviceOf(store store.Readable) (validation.Service, error) {
+	version, err := store.Get(keyValidation[:])
+	if err != nil {
+		return nil, xerrors.Errorf("reading version: %v", err)
+	}
+
+	if len(version) == 0 {
+		return v.initial, nil
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	srvc, found := v.versions[string(version)]
+	if !found {
+		return nil, xerrors.Errorf("version '%s' is not registered", version)
+	}
+
+	return srvc, nil
+}
@@ -0,0 +1,213 @@
+package cosipbft
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/ordering/cosipbft/contracts/upgrade"
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/core/validation"
+	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestService_Scenario_UpgradeValidation(t *testing.T) {
+	nodes, ro, clean := makeAuthority(t, 3)
+	defer clean()
+
+	signer := nodes[0].signer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := nodes[0].service.Setup(ctx, ro)
+	require.NoError(t, err)
+
+	events := nodes[0].service.Watch(ctx)
+
+	// The new version of the contract refuses every transaction.
+	exec := native.NewExecution()
+	exec.Set(testContractName, testExec{err: fake.GetError()})
+
+	v2 := simple.NewService(exec, signed.NewTransactionFactory())
+
+	for _, node := range nodes {
+		require.NoError(t, node.service.UpgradeValidation("v2", v2))
+	}
+
+	err = nodes[0].pool.Add(makeTx(t, 0, signer))
+	require.NoError(t, err)
+
+	evt := waitEvent(t, events)
+	require.Equal(t, uint64(0), evt.Index)
+	requireAccepted(t, evt.Transactions, true)
+
+	// The block that includes the upgrade is validated by the initial service.
+	err = nodes[0].pool.Add(makeUpgradeTx(t, 1, "v2", signer))
+	require.NoError(t, err)
+
+	evt = waitEvent(t, events)
+	require.Equal(t, uint64(1), evt.Index)
+	requireAccepted(t, evt.Transactions, true)
+
+	err = nodes[0].pool.Add(makeTx(t, 2, signer))
+	require.NoError(t, err)
+
+	evt = waitEvent(t, events)
+	require.Equal(t, uint64(2), evt.Index)
+	requireAccepted(t, evt.Transactions, false)
+
+	for _, node := range nodes {
+		version, err := node.service.GetStore().Get(keyValidation[:])
+		require.NoError(t, err)
+		require.Equal(t, "v2", string(version))
+	}
+}
+
+func TestService_Scenario_UpgradeUnknownVersion(t *testing.T) {
+	nodes, ro, clean := makeAuthority(t, 3)
+	defer clean()
+
+	signer := nodes[0].signer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := nodes[0].service.Setup(ctx, ro)
+	require.NoError(t, err)
+
+	events := nodes[0].service.Watch(ctx)
+
+	// The version is not registered, thus the upgrade is refused instead of
+	// halting the chain.
+	err = nodes[0].pool.Add(makeUpgradeTx(t, 0, "v2", signer))
+	require.NoError(t, err)
+
+	evt := waitEvent(t, events)
+	require.Equal(t, uint64(0), evt.Index)
+	requireAccepted(t, evt.Transactions, false)
+
+	err = nodes[0].pool.Add(makeTx(t, 1, signer))
+	require.NoError(t, err)
+
+	evt = waitEvent(t, events)
+	require.Equal(t, uint64(1), evt.Index)
+	requireAccepted(t, evt.Transactions, true)
+
+	for _, node := range nodes {
+		version, err := node.service.GetStore().Get(keyValidation[:])
+		require.NoError(t, err)
+		require.Empty(t, version)
+	}
+}
+
+func TestService_UpgradeValidation(t *testing.T) {
+	srvc := &Service{
+		processor: newProcessor(),
+		upgrades:  newUpgradableValidation(fakeValidation{}),
+	}
+
+	err := srvc.UpgradeValidation("v2", fakeValidation{})
+	require.NoError(t, err)
+
+	err = srvc.UpgradeValidation("v2", fakeValidation{})
+	require.EqualError(t, err, "invalid upgrade: version 'v2' already registered")
+
+	err = srvc.UpgradeValidation("", fakeValidation{})
+	require.EqualError(t, err, "invalid upgrade: empty version")
+
+	err = srvc.UpgradeValidation("v3", nil)
+	require.EqualError(t, err, "invalid upgrade: missing validation service")
+}
+
+func TestUpgradableValidation_Forward(t *testing.T) {
+	srvc := newUpgradableValidation(fakeValidation{})
+	require.NoError(t, srvc.register("v2", fakeValidation{err: fake.GetError()}))
+
+	require.NotNil(t, srvc.GetFactory())
+
+	initial := fakeVersionStore{}
+
+	_, err := srvc.GetNonce(initial, fake.PublicKey{})
+	require.NoError(t, err)
+	require.NoError(t, srvc.Accept(initial, nil, validation.Leeway{}))
+
+	_, err = srvc.Validate(initial, nil)
+	require.NoError(t, err)
+
+	upgraded := fakeVersionStore{version: []byte("v2")}
+
+	_, err = srvc.GetNonce(upgraded, fake.PublicKey{})
+	require.EqualError(t, err, fake.GetError().Error())
+	require.EqualError(t, srvc.Accept(upgraded, nil, validation.Leeway{}), fake.GetError().Error())
+
+	_, err = srvc.Validate(upgraded, nil)
+	require.EqualError(t, err, fake.GetError().Error())
+}
+
+func TestUpgradableValidation_IsRegistered(t *testing.T) {
+	srvc := newUpgradableValidation(fakeValidation{})
+	require.False(t, srvc.IsRegistered("v2"))
+
+	require.NoError(t, srvc.register("v2", fakeValidation{}))
+	require.True(t, srvc.IsRegistered("v2"))
+	require.False(t, srvc.IsRegistered("v3"))
+}
+
+func TestUpgradableValidation_UnknownVersion(t *testing.T) {
+	srvc := newUpgradableValidation(fakeValidation{})
+
+	unknown := fakeVersionStore{version: []byte("v2")}
+
+	_, err := srvc.GetNonce(unknown, fake.PublicKey{})
+	require.EqualError(t, err, "validation service: version 'v2' is not registered")
+
+	err = srvc.Accept(unknown, nil, validation.Leeway{})
+	require.EqualError(t, err, "validation service: version 'v2' is not registered")
+
+	_, err = srvc.Validate(unknown, nil)
+	require.EqualError(t, err, "validation service: version 'v2' is not registered")
+
+	_, err = srvc.Validate(fakeVersionStore{err: fake.GetError()}, nil)
+	require.EqualError(t, err, fake.Err("validation service: reading version"))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeUpgradeTx(t *testing.T, nonce uint64, version string, signer crypto.Signer) txn.Transaction {
+	tx, err := signed.NewTransaction(
+		nonce,
+		signer.GetPublicKey(),
+		signed.WithArg(native.ContractArg, []byte(upgrade.ContractName)),
+		signed.WithArg(upgrade.VersionArg, []byte(version)),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, tx.Sign(signer))
+
+	return tx
+}
+
+func requireAccepted(t *testing.T, results []validation.TransactionResult, accepted bool) {
+	require.Len(t, results, 1)
+
+	status, _ := results[0].GetStatus()
+	require.Equal(t, accepted, status)
+}
+
+type fakeVersionStore struct {
+	store.Snapshot
+
+	version []byte
+	err     error
+}
+
+func (s fakeVersionStore) Get(key []byte) ([]byte, error) {
+	return s.version, s.err
+}
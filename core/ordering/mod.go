@@ -24,10 +24,14 @@ type Proof interface {
 	GetValue() []byte
 }
 
-// Event describes the current state of the service after an update.
+// Event describes the current state of the service after an update. The
+// rejected transactions are also part of the list of transactions, but they are
+// gathered separately so that a client can learn why they have been refused
+// and submit them again.
 type Event struct {
 	Index        uint64
 	Transactions []validation.TransactionResult
+	Rejected     []validation.TransactionResult
 }
 
 // Service is the interface of an ordering service. It provides the primitives
@@ -254,6 +254,24 @@ func TestMerkleTree_GetPath(t *testing.T) {
 	err := quick.Check(f, nil)
 	require.NoError(t, err)
 
+	// The path of an absent key ends either at an empty node or at the leaf of
+	// another key, and in both cases it must lead to the root of the tree.
+	g := func(key [8]byte) bool {
+		absent := append(key[:], 0xff)
+
+		path, err := tree.GetPath(absent)
+		require.NoError(t, err)
+
+		root, err := path.(Path).computeRoot(tree.hashFactory)
+		require.NoError(t, err)
+		require.Equal(t, root, path.GetRoot())
+
+		return path.GetValue() == nil
+	}
+
+	err = quick.Check(g, nil)
+	require.NoError(t, err)
+
 	_, err = tree.GetPath(make([]byte, MaxDepth+1))
 	require.EqualError(t, err, "couldn't search key: mismatch key length 33 > 32")
 }
@@ -13,13 +13,17 @@ import (
 
 // Path is a path from the root to a leaf, represented as a series of interior
 // nodes hashes. The end of the path is either a leaf with a key holding a
-// value, or an empty node.
+// value, or an empty node. A leaf of a different key proves the absence of the
+// key as well.
 //
 // - implements hashtree.Path
 type Path struct {
 	nonce []byte
 	key   []byte
 	value []byte
+	// Leaf is the leaf at the end of the path when it holds a different key,
+	// which is required to compute the root of a proof of absence.
+	leaf *LeafNode
 	// Root is the root of the hash tree. This value is not serialized and
 	// reproduced from the leaf and the interior nodes when deserializing.
 	root      []byte
@@ -58,6 +62,8 @@ func (s Path) computeRoot(fac crypto.HashFactory) ([]byte, error) {
 	var node TreeNode
 	if s.value != nil {
 		node = NewLeafNode(uint16(len(s.interiors)), key, s.value)
+	} else if s.leaf != nil {
+		node = NewLeafNode(uint16(len(s.interiors)), s.leaf.key, s.leaf.value)
 	} else {
 		node = NewEmptyNode(uint16(len(s.interiors)), key)
 	}
@@ -709,15 +709,22 @@ func (n *LeafNode) GetType() byte {
 // Search implements binprefix.TreeNode. It returns the value if the key
 // matches.
 func (n *LeafNode) Search(key *big.Int, path *Path, b kv.Bucket) ([]byte, error) {
-	if path != nil {
-		path.value = n.value
+	if n.key.Cmp(key) != 0 {
+		if path != nil {
+			// The key is absent but the leaf is required to compute the root.
+			path.value = nil
+			path.leaf = NewLeafNode(n.depth, n.key, n.value)
+		}
+
+		return nil, nil
 	}
 
-	if n.key.Cmp(key) == 0 {
-		return n.value, nil
+	if path != nil {
+		path.value = n.value
+		path.leaf = nil
 	}
 
-	return nil, nil
+	return n.value, nil
 }
 
 // Insert implements binprefix.TreeNode. It replaces the leaf node by an
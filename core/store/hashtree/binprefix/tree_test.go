@@ -525,6 +525,13 @@ func TestLeafNode_Search(t *testing.T) {
 	value, err = node.Search(makeKey([]byte("pong")), nil, nil)
 	require.NoError(t, err)
 	require.Nil(t, value)
+
+	// The leaf is kept in the path to prove the absence of the key.
+	value, err = node.Search(makeKey([]byte("pong")), &path, nil)
+	require.NoError(t, err)
+	require.Nil(t, value)
+	require.Nil(t, path.value)
+	require.Equal(t, []byte("pong"), path.leaf.value)
 }
 
 func TestLeafNode_Insert(t *testing.T) {
@@ -13,6 +13,7 @@ import (
 	"go.dedis.ch/dela/crypto/loader"
 
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/contracts/noop"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/pool"
 	"go.dedis.ch/dela/core/txn/signed"
@@ -36,9 +37,6 @@ type addAction struct {
 
 // Execute implements node.ActionTemplate
 func (a *addAction) Execute(ctx node.Context) error {
-	a.Lock()
-	defer a.Unlock()
-
 	var p pool.Pool
 	err := ctx.Injector.Resolve(&p)
 	if err != nil {
@@ -50,6 +48,14 @@ func (a *addAction) Execute(ctx node.Context) error {
 		return xerrors.Errorf("failed to get args: %v", err)
 	}
 
+	return a.submit(ctx, p, args)
+}
+
+// submit creates a transaction with the arguments and adds it to the pool.
+func (a *addAction) submit(ctx node.Context, p pool.Pool, args []txn.Arg) error {
+	a.Lock()
+	defer a.Unlock()
+
 	signer, err := getSigner(ctx)
 	if err != nil {
 		return xerrors.Errorf("failed to get signer: %v", err)
@@ -80,6 +86,31 @@ func (a *addAction) Execute(ctx node.Context) error {
 	return nil
 }
 
+// noopAction describes an action to add a no-op transaction to the pool. It
+// shares the nonce of the add action so that both can be used in turn.
+//
+// - implements node.ActionTemplate
+type noopAction struct {
+	add *addAction
+}
+
+// Execute implements node.ActionTemplate. It adds a transaction targeting the
+// no-op contract to the pool.
+func (a noopAction) Execute(ctx node.Context) error {
+	var p pool.Pool
+	err := ctx.Injector.Resolve(&p)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	err = a.add.submit(ctx, p, noop.NewArgs())
+	if err != nil {
+		return xerrors.Errorf("failed to submit no-op: %v", err)
+	}
+
+	return nil
+}
+
 // getArgs extracts and parses arguments from the context.
 func getArgs(ctx node.Context) ([]txn.Arg, error) {
 	inArgs := ctx.Flags.StringSlice("args")
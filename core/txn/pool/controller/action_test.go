@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"os"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/contracts/noop"
+	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/pool"
 	"go.dedis.ch/dela/core/txn/pool/mem"
@@ -84,6 +87,52 @@ func TestExecute(t *testing.T) {
 	require.EqualError(t, err, "injector: couldn't find dependency for 'pool.Pool'")
 }
 
+func TestNoopAction_Execute(t *testing.T) {
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    make(node.FlagSet),
+		Out:      ioutil.Discard,
+	}
+
+	p := mem.NewPool()
+
+	action := noopAction{add: &addAction{client: &client{}}}
+	ctx.Injector.Inject(p)
+
+	buf, err := bls.NewSigner().MarshalBinary()
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(os.TempDir(), "key.buf")
+	ctx.Flags.(node.FlagSet)[signerFlag] = keyFile
+	ctx.Flags.(node.FlagSet)[nonceFlag] = 3
+
+	err = ioutil.WriteFile(keyFile, buf, os.ModePerm)
+	require.NoError(t, err)
+	defer os.RemoveAll(keyFile)
+
+	getManager = func(c crypto.Signer, s signed.Client) txn.Manager {
+		return signed.NewManager(c, s)
+	}
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, p.Len())
+
+	tx := p.Gather(context.Background(), pool.Config{Min: 1})
+	require.Equal(t, uint64(3), tx[0].GetNonce())
+	require.Equal(t, []byte(noop.ContractName), tx[0].GetArg(native.ContractArg))
+
+	ctx.Injector = node.NewInjector()
+	ctx.Injector.Inject(&badPool{})
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to submit no-op: failed to include tx: "+
+		fake.Err("failed to add"))
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'pool.Pool'")
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
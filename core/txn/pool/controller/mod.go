@@ -34,6 +34,10 @@ func (miniController) SetCommands(builder node.Builder) {
 	cmd := builder.SetCommand("pool")
 	cmd.SetDescription("interact with the pool")
 
+	add := &addAction{
+		client: &client{},
+	}
+
 	sub := cmd.SetSubCommand("add")
 	sub.SetDescription("add a transaction to the pool")
 	sub.SetFlags(cli.StringSliceFlag{
@@ -49,9 +53,21 @@ func (miniController) SetCommands(builder node.Builder) {
 		Usage:    "path to the private keyfile",
 		Required: true,
 	})
-	sub.SetAction(builder.MakeAction(&addAction{
-		client: &client{},
-	}))
+	sub.SetAction(builder.MakeAction(add))
+
+	sub = cmd.SetSubCommand("submit-noop")
+	sub.SetDescription("add a no-op transaction to the pool")
+	sub.SetFlags(cli.IntFlag{
+		Name:     nonceFlag,
+		Usage:    "nonce to use",
+		Required: false,
+		Value:    -1,
+	}, cli.StringFlag{
+		Name:     signerFlag,
+		Usage:    "path to the private keyfile",
+		Required: true,
+	})
+	sub.SetAction(builder.MakeAction(noopAction{add: add}))
 }
 
 // OnStart implements node.Initializer
@@ -15,7 +15,7 @@ func TestMiniController_Build(t *testing.T) {
 	call := &fake.Call{}
 	ctrl.SetCommands(fakeBuilder{call: call})
 
-	require.Equal(t, 7, call.Len())
+	require.Equal(t, 12, call.Len())
 	require.Equal(t, "pool", call.Get(0, 0))
 	require.Equal(t, "interact with the pool", call.Get(1, 0))
 	require.Equal(t, "add", call.Get(2, 0))
@@ -23,6 +23,11 @@ func TestMiniController_Build(t *testing.T) {
 	require.Len(t, call.Get(4, 0), 3)
 	require.IsType(t, &addAction{}, call.Get(5, 0))
 	require.Nil(t, call.Get(6, 0)) // our fake MakeAction() returns nil
+	require.Equal(t, "submit-noop", call.Get(7, 0))
+	require.Equal(t, "add a no-op transaction to the pool", call.Get(8, 0))
+	require.Len(t, call.Get(9, 0), 2)
+	require.IsType(t, noopAction{}, call.Get(10, 0))
+	require.Nil(t, call.Get(11, 0))
 }
 
 func TestMiniController_OnStart(t *testing.T) {
@@ -5,7 +5,10 @@ import (
 	"context"
 	"sort"
 	"sync"
+	"time"
 
+	"github.com/rs/zerolog"
+	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/validation"
@@ -93,6 +96,18 @@ type item struct {
 	ch  chan []txn.Transaction
 }
 
+// Option is the type of option to configure the gathering of a pool.
+type Option func(*simpleGatherer)
+
+// WithTTL is an option to set the duration after which a transaction that has
+// not been committed is evicted from the pool. A zero value disables the
+// eviction.
+func WithTTL(ttl time.Duration) Option {
+	return func(g *simpleGatherer) {
+		g.ttl = ttl
+	}
+}
+
 // SimpleGatherer is a gatherer of transactions that will use filters to drop
 // invalid transactions. It limits the size for each identity, *as long as* a
 // filter is set, otherwise it can grow indefinitely.
@@ -101,7 +116,9 @@ type item struct {
 type simpleGatherer struct {
 	sync.Mutex
 
+	logger     zerolog.Logger
 	limit      int
+	ttl        time.Duration
 	queue      []item
 	validators []Filter
 
@@ -109,14 +126,30 @@ type simpleGatherer struct {
 	// own list of transactions, so that a limited size can be enforced
 	// independently from each other.
 	txs map[string]transactions
+
+	// The time of arrival of each transaction, indexed by ID, so that the
+	// stale ones can be evicted.
+	arrivals map[string]time.Time
+
+	// now returns the current time, and can be replaced to control the clock.
+	now func() time.Time
 }
 
 // NewSimpleGatherer creates a new gatherer.
-func NewSimpleGatherer() Gatherer {
-	return &simpleGatherer{
-		limit: DefaultIdentitySize,
-		txs:   make(map[string]transactions),
+func NewSimpleGatherer(opts ...Option) Gatherer {
+	g := &simpleGatherer{
+		logger:   dela.Logger.With().Str(dela.SubsystemKey, "pool").Logger(),
+		limit:    DefaultIdentitySize,
+		txs:      make(map[string]transactions),
+		arrivals: make(map[string]time.Time),
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(g)
 	}
+
+	return g
 }
 
 // Len implements pool.Gatherer. It returns the number of transaction available
@@ -125,6 +158,8 @@ func (g *simpleGatherer) Len() int {
 	g.Lock()
 	defer g.Unlock()
 
+	g.evict()
+
 	return g.calculateLength()
 }
 
@@ -158,8 +193,17 @@ func (g *simpleGatherer) Add(tx txn.Transaction) error {
 
 	g.Lock()
 
+	length := len(g.txs[key])
+
 	g.txs[key] = g.txs[key].Add(tx)
 
+	// A transaction with the same nonce as a known one is dropped, in which
+	// case there is no arrival to track.
+	if len(g.txs[key]) > length {
+		g.arrivals[string(tx.GetID())] = g.now()
+	}
+
+	g.evict()
 	g.notify(g.calculateLength())
 
 	g.Unlock()
@@ -178,6 +222,7 @@ func (g *simpleGatherer) Remove(tx txn.Transaction) error {
 	g.Lock()
 
 	g.txs[key] = g.txs[key].Remove(tx)
+	delete(g.arrivals, string(tx.GetID()))
 
 	g.Unlock()
 
@@ -191,6 +236,8 @@ func (g *simpleGatherer) Wait(ctx context.Context, cfg Config) []txn.Transaction
 
 	g.Lock()
 
+	g.evict()
+
 	if g.calculateLength() >= cfg.Min {
 		txs := g.makeArray()
 		g.Unlock()
@@ -220,6 +267,7 @@ func (g *simpleGatherer) Close() {
 	g.Lock()
 
 	g.txs = make(map[string]transactions)
+	g.arrivals = make(map[string]time.Time)
 
 	for _, item := range g.queue {
 		close(item.ch)
@@ -245,6 +293,38 @@ func (g *simpleGatherer) notify(length int) {
 	}
 }
 
+// Evict removes the transactions that have been in the pool for longer than
+// the time-to-live, if any is defined.
+func (g *simpleGatherer) evict() {
+	if g.ttl <= 0 {
+		return
+	}
+
+	now := g.now()
+
+	for key, list := range g.txs {
+		kept := list[:0]
+
+		for _, tx := range list {
+			arrival, found := g.arrivals[string(tx.GetID())]
+			if found && now.Sub(arrival) > g.ttl {
+				delete(g.arrivals, string(tx.GetID()))
+
+				g.logger.Info().
+					Hex("id", tx.GetID()).
+					Str("reason", "time-to-live expired").
+					Msg("transaction evicted")
+
+				continue
+			}
+
+			kept = append(kept, tx)
+		}
+
+		g.txs[key] = kept
+	}
+}
+
 func (g *simpleGatherer) calculateLength() int {
 	num := 0
 	for _, list := range g.txs {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/core/access"
@@ -74,6 +75,48 @@ func TestSimpleGatherer_Remove(t *testing.T) {
 	require.EqualError(t, err, fake.Err("identity key failed"))
 }
 
+func TestSimpleGatherer_TTL(t *testing.T) {
+	logger, check := fake.CheckLog("transaction evicted")
+
+	now := time.Now()
+
+	gatherer := NewSimpleGatherer(WithTTL(20 * time.Millisecond)).(*simpleGatherer)
+	gatherer.logger = logger
+	gatherer.now = func() time.Time { return now }
+
+	err := gatherer.Add(newTx(0, "Alice"))
+	require.NoError(t, err)
+	require.Equal(t, 1, gatherer.Len())
+
+	now = now.Add(40 * time.Millisecond)
+
+	err = gatherer.Add(newTx(1, "Alice"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	txs := gatherer.Wait(ctx, Config{Min: 1})
+	require.Len(t, txs, 1)
+	require.Equal(t, uint64(1), txs[0].GetNonce())
+	require.Equal(t, 1, gatherer.Len())
+	require.Len(t, gatherer.arrivals, 1)
+	check(t)
+
+	// A transaction with the same nonce is dropped without an arrival.
+	dup := newTx(1, "Alice")
+	dup.salt = 1
+
+	err = gatherer.Add(dup)
+	require.NoError(t, err)
+	require.Equal(t, 1, gatherer.Len())
+	require.Len(t, gatherer.arrivals, 1)
+
+	now = now.Add(40 * time.Millisecond)
+	require.Equal(t, 0, gatherer.Len())
+	require.Empty(t, gatherer.arrivals)
+}
+
 func TestSimpleGatherer_Wait(t *testing.T) {
 	gatherer := NewSimpleGatherer().(*simpleGatherer)
 
@@ -138,6 +181,7 @@ type fakeTx struct {
 	txn.Transaction
 
 	id       uint64
+	salt     byte
 	identity access.Identity
 }
 
@@ -149,7 +193,7 @@ func newTx(nonce uint64, identity string) fakeTx {
 }
 
 func (tx fakeTx) GetID() []byte {
-	return []byte{byte(tx.id)}
+	return []byte{byte(tx.id), tx.salt}
 }
 
 func (tx fakeTx) GetNonce() uint64 {
@@ -26,16 +26,16 @@ type Pool struct {
 }
 
 // NewPool creates a new empty pool and starts to gossip incoming transaction.
-func NewPool(gossiper gossip.Gossiper) (*Pool, error) {
+func NewPool(gossiper gossip.Gossiper, opts ...pool.Option) (*Pool, error) {
 	actor, err := gossiper.Listen()
 	if err != nil {
 		return nil, xerrors.Errorf("failed to listen: %v", err)
 	}
 
 	p := &Pool{
-		logger:   dela.Logger,
+		logger:   dela.Logger.With().Str(dela.SubsystemKey, "pool").Logger(),
 		actor:    actor,
-		gatherer: pool.NewSimpleGatherer(),
+		gatherer: pool.NewSimpleGatherer(opts...),
 		closing:  make(chan struct{}),
 	}
 
@@ -18,9 +18,9 @@ type Pool struct {
 }
 
 // NewPool creates a new service.
-func NewPool() *Pool {
+func NewPool(opts ...pool.Option) *Pool {
 	return &Pool{
-		gatherer: pool.NewSimpleGatherer(),
+		gatherer: pool.NewSimpleGatherer(opts...),
 	}
 }
 
@@ -1,8 +1,14 @@
 package dkg
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
 )
 
 // DKG defines the primitive to start a DKG protocol
@@ -19,6 +25,11 @@ type Actor interface {
 	// each node. Each node represented by a player must first execute Listen().
 	Setup(co crypto.CollectiveAuthority, threshold int) (pubKey kyber.Point, err error)
 
+	// SetupContext is like Setup but the protocol is aborted when the context
+	// is done, so that a retry can be made.
+	SetupContext(ctx context.Context, co crypto.CollectiveAuthority,
+		threshold int) (pubKey kyber.Point, err error)
+
 	// GetPublicKey returns the collective public key. Returns an error it the
 	// setup has not been done.
 	GetPublicKey() (kyber.Point, error)
@@ -26,5 +37,64 @@ type Actor interface {
 	Encrypt(message []byte) (K, C kyber.Point, remainder []byte, err error)
 	Decrypt(K, C kyber.Point) ([]byte, error)
 
-	Reshare() error
+	// DecryptWithProof decrypts the message like Decrypt, and returns the
+	// proofs that the share-holders have correctly computed their partial
+	// decryption.
+	DecryptWithProof(K, C kyber.Point) ([]byte, DecryptionProof, error)
+
+	// DecryptBatch decrypts the ciphertexts by collecting the partial
+	// decryptions of all of them at once. The messages are returned in the same
+	// order, and the ones that cannot be decrypted are reported with a
+	// BatchError.
+	DecryptBatch(cs []Ciphertext) ([][]byte, error)
+
+	// Reshare distributes new shares of the collective key to the collective
+	// authority with a new threshold. The public key stays the same.
+	Reshare(co crypto.CollectiveAuthority, threshold int) error
+}
+
+// DecryptionProof is the proof that a message has been correctly decrypted. It
+// contains the partial decryptions used to recover the message, and the public
+// commitments of the distributed key to verify them.
+type DecryptionProof struct {
+	Commits  []kyber.Point
+	Partials []PartialDecryption
+}
+
+// PartialDecryption is the partial decryption of a share-holder, with the proof
+// that the same private share has been used for the public share and for the
+// decryption.
+type PartialDecryption struct {
+	Index int
+	V     kyber.Point
+	Proof *dleq.Proof
+}
+
+// Ciphertext is a message encrypted with the distributed key.
+type Ciphertext struct {
+	K kyber.Point
+	C kyber.Point
+}
+
+// BatchError is the error returned by a batch decryption when some of the
+// ciphertexts cannot be decrypted. It maps the index of the ciphertexts to the
+// reason of the failure.
+type BatchError map[int]error
+
+// Error implements error. It returns the failures sorted by index.
+func (e BatchError) Error() string {
+	indices := make([]int, 0, len(e))
+	for index := range e {
+		indices = append(indices, index)
+	}
+
+	sort.Ints(indices)
+
+	reasons := make([]string, len(indices))
+	for i, index := range indices {
+		reasons[i] = fmt.Sprintf("[%d]: %v", index, e[index])
+	}
+
+	return fmt.Sprintf("failed to decrypt %d ciphertext(s): %s", len(e),
+		strings.Join(reasons, ", "))
 }
@@ -0,0 +1,1662 @@
+// This file contains the implementation of the controller actions.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/dkg"
+	"go.dedis.ch/dela/dkg/pedersen"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/suites"
+	"golang.org/x/xerrors"
+)
+
+const separator = ":"
+
+// defaultSetupTimeout is the maximum amount of time to setup the DKG when it is
+// not specified.
+const defaultSetupTimeout = 5 * time.Minute
+
+// benchMessageSize is the size of the random messages encrypted by the
+// benchmark, which fits in a single point.
+const benchMessageSize = 16
+
+// listenAction is an action to start the DKG protocol on the node so that it
+// can participate to a setup.
+//
+// - implements node.ActionTemplate
+type listenAction struct{}
+
+// Execute implements node.ActionTemplate. It creates the actor of the DKG and
+// injects it, unless the actor already exists because the share has been
+// loaded on start.
+func (a listenAction) Execute(ctx node.Context) error {
+	var d dkg.DKG
+	err := ctx.Injector.Resolve(&d)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	// Listening again would register the RPC of the DKG a second time.
+	var actor dkg.Actor
+	err = ctx.Injector.Resolve(&actor)
+	if err == nil {
+		fmt.Fprint(ctx.Out, "DKG is already listening")
+
+		return nil
+	}
+
+	actor, err = d.Listen()
+	if err != nil {
+		return xerrors.Errorf("failed to listen: %v", err)
+	}
+
+	ctx.Injector.Inject(actor)
+
+	fmt.Fprint(ctx.Out, "DKG is listening")
+
+	return nil
+}
+
+// exportAction is an action to display a base64 string describing the node in
+// the DKG. It is used to provide the members of a setup.
+//
+// - implements node.ActionTemplate
+type exportAction struct{}
+
+// Execute implements node.ActionTemplate. It looks for the node address and
+// the DKG public key and prints "$ADDR_BASE64:$PUBLIC_KEY_BASE64".
+func (a exportAction) Execute(ctx node.Context) error {
+	var m mino.Mino
+	err := ctx.Injector.Resolve(&m)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	addr, err := m.GetAddress().MarshalText()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal address: %v", err)
+	}
+
+	var p *pedersen.Pedersen
+	err = ctx.Injector.Resolve(&p)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	pubkey, err := p.GetPublicKey().MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal public key: %v", err)
+	}
+
+	desc := base64.StdEncoding.EncodeToString(addr) + separator +
+		base64.StdEncoding.EncodeToString(pubkey)
+
+	fmt.Fprint(ctx.Out, desc)
+
+	return nil
+}
+
+// setupAction is an action to create the distributed key with a list of
+// participants.
+//
+// - implements node.ActionTemplate
+type setupAction struct{}
+
+// Execute implements node.ActionTemplate. It reads the list of members and the
+// threshold, and runs the setup of the DKG. In a dry run, it only checks that
+// the members can be decoded and reached.
+func (a setupAction) Execute(ctx node.Context) error {
+	if ctx.Flags.Bool("dry-run") {
+		return a.dryRun(ctx)
+	}
+
+	roster, err := readMembers(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to read roster: %v", err)
+	}
+
+	threshold := ctx.Flags.Int("threshold")
+	if threshold == 0 {
+		threshold = majority(roster.Len())
+	}
+
+	if threshold < 1 || threshold > roster.Len() {
+		return xerrors.Errorf("threshold must be between 1 and %d, got %d",
+			roster.Len(), threshold)
+	}
+
+	var actor dkg.Actor
+	err = ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	timeout := ctx.Flags.Duration("timeout")
+	if timeout <= 0 {
+		timeout = defaultSetupTimeout
+	}
+
+	setupCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	pubkey, err := actor.SetupContext(setupCtx, roster, threshold)
+	if err != nil {
+		return xerrors.Errorf("failed to setup: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "setup done, public key: %s", pubkey)
+
+	return nil
+}
+
+// dryRun decodes every member and pings it, and reports the result of each of
+// them. It returns an error if any member failed either check.
+func (a setupAction) dryRun(ctx node.Context) error {
+	var actor pinger
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	timeout := ctx.Flags.Duration("timeout")
+	if timeout <= 0 {
+		timeout = defaultSetupTimeout
+	}
+
+	pingCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	members, err := resolveMembers(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to read members: %v", err)
+	}
+
+	failures := 0
+
+	for i, member := range members {
+		addr, _, err := decodeMember(ctx, member)
+		if err != nil {
+			fmt.Fprintf(ctx.Out, "member %d: failed to decode: %v\n", i, err)
+			failures++
+			continue
+		}
+
+		err = actor.Ping(pingCtx, addr)
+		if err != nil {
+			fmt.Fprintf(ctx.Out, "member %d (%v): unreachable: %v\n", i, addr, err)
+			failures++
+			continue
+		}
+
+		fmt.Fprintf(ctx.Out, "member %d (%v): reachable\n", i, addr)
+	}
+
+	if failures > 0 {
+		return xerrors.Errorf("dry run failed for %d of %d member(s)",
+			failures, len(members))
+	}
+
+	fmt.Fprintf(ctx.Out, "dry run done, %d member(s) reachable", len(members))
+
+	return nil
+}
+
+// pinger is the interface of an actor that can check that a node is
+// reachable.
+type pinger interface {
+	dkg.Actor
+
+	Ping(ctx context.Context, addr mino.Address) error
+}
+
+// majority returns the smallest number of members that is at least two thirds
+// of the given number.
+func majority(n int) int {
+	return (2*n + 2) / 3
+}
+
+// reshareAction is an action to distribute new shares of the distributed key to
+// a new list of participants. The public key stays the same.
+//
+// - implements node.ActionTemplate
+type reshareAction struct{}
+
+// Execute implements node.ActionTemplate. It reads the new list of members and
+// the new threshold, and runs the resharing of the DKG.
+func (a reshareAction) Execute(ctx node.Context) error {
+	roster, err := readMembers(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to read roster: %v", err)
+	}
+
+	threshold := ctx.Flags.Int("threshold")
+	if threshold == 0 {
+		threshold = majority(roster.Len())
+	}
+
+	if threshold < 1 || threshold > roster.Len() {
+		return xerrors.Errorf("threshold must be between 1 and %d, got %d",
+			roster.Len(), threshold)
+	}
+
+	var actor dkg.Actor
+	err = ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	err = actor.Reshare(roster, threshold)
+	if err != nil {
+		return xerrors.Errorf("failed to reshare: %v", err)
+	}
+
+	pubkey, err := actor.GetPublicKey()
+	if err != nil {
+		return xerrors.Errorf("failed to get public key: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "resharing done, public key: %s", pubkey)
+
+	return nil
+}
+
+func readMembers(ctx node.Context) (authority.Authority, error) {
+	members, err := resolveMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]mino.Address, len(members))
+	pubkeys := make([]crypto.PublicKey, len(members))
+
+	for i, member := range members {
+		addr, pubkey, err := decodeMember(ctx, member)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode: %v", err)
+		}
+
+		addrs[i] = addr
+		pubkeys[i] = pubkey
+	}
+
+	return authority.New(addrs, pubkeys), nil
+}
+
+// resolveMembers returns the members given by the flags, where a member without
+// the separator is the name of an entry of the members file.
+func resolveMembers(ctx node.Context) ([]string, error) {
+	members := ctx.Flags.StringSlice("member")
+
+	path := ctx.Flags.Path("members-file")
+	if path == "" {
+		return members, nil
+	}
+
+	book, err := loadMembersFile(ctx, path)
+	if err != nil {
+		return nil, xerrors.Errorf("members file: %v", err)
+	}
+
+	resolved := make([]string, len(members))
+
+	for i, member := range members {
+		if strings.Contains(member, separator) {
+			resolved[i] = member
+			continue
+		}
+
+		entry, found := book[member]
+		if !found {
+			return nil, xerrors.Errorf("unknown member '%s'", member)
+		}
+
+		resolved[i] = entry
+	}
+
+	return resolved, nil
+}
+
+// loadMembersFile reads the JSON object that maps the names of the members to
+// their "$ADDR:$PK" string. Every entry is decoded so that a malformed file is
+// rejected before it is used.
+func loadMembersFile(ctx node.Context, path string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read: %v", err)
+	}
+
+	book := make(map[string]string)
+
+	err = json.Unmarshal(data, &book)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode: %v", err)
+	}
+
+	for name, member := range book {
+		if name == "" || strings.Contains(name, separator) {
+			return nil, xerrors.Errorf("invalid name '%s'", name)
+		}
+
+		_, _, err = decodeMember(ctx, member)
+		if err != nil {
+			return nil, xerrors.Errorf("entry '%s': %v", name, err)
+		}
+	}
+
+	return book, nil
+}
+
+func decodeMember(ctx node.Context, str string) (mino.Address, crypto.PublicKey, error) {
+	parts := strings.Split(str, separator)
+	if len(parts) != 2 {
+		return nil, nil, xerrors.New("invalid member base64 string")
+	}
+
+	// 1. Deserialize the address.
+	var m mino.Mino
+	err := ctx.Injector.Resolve(&m)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("injector: %v", err)
+	}
+
+	addrBuf, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, xerrors.Errorf("base64 address: %v", err)
+	}
+
+	addr := m.GetAddressFactory().FromText(addrBuf)
+
+	// 2. Deserialize the public key.
+	pubkeyBuf, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, xerrors.Errorf("base64 public key: %v", err)
+	}
+
+	suite, err := getSuite(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	point := suite.Point()
+
+	err = point.UnmarshalBinary(pubkeyBuf)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("failed to decode public key: %v", err)
+	}
+
+	return addr, memberKey{point: point}, nil
+}
+
+// memberKey is the public key of a member of the DKG. It only holds the point,
+// in the suite of the DKG, as it is never used to verify signatures.
+//
+// - implements crypto.PublicKey
+type memberKey struct {
+	point kyber.Point
+}
+
+// GetPoint returns the point of the public key.
+func (pk memberKey) GetPoint() kyber.Point {
+	return pk.point
+}
+
+// Verify implements crypto.PublicKey. It always returns an error as the key
+// of a member does not verify signatures.
+func (pk memberKey) Verify([]byte, crypto.Signature) error {
+	return xerrors.New("member key cannot verify signatures")
+}
+
+// Equal implements crypto.PublicKey. It returns true if the other public key
+// has the same point.
+func (pk memberKey) Equal(other interface{}) bool {
+	pubkey, ok := other.(memberKey)
+	if !ok {
+		return false
+	}
+
+	return pubkey.point.Equal(pk.point)
+}
+
+// Serialize implements serde.Message. It returns the binary representation of
+// the point.
+func (pk memberKey) Serialize(serde.Context) ([]byte, error) {
+	return pk.MarshalBinary()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It returns the binary
+// representation of the point.
+func (pk memberKey) MarshalBinary() ([]byte, error) {
+	return pk.point.MarshalBinary()
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns a text
+// representation of the point.
+func (pk memberKey) MarshalText() ([]byte, error) {
+	buffer, err := pk.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't marshal: %v", err)
+	}
+
+	return []byte(fmt.Sprintf("member:%x", buffer)), nil
+}
+
+// getSuite returns the Kyber suite of the DKG.
+func getSuite(ctx node.Context) (suites.Suite, error) {
+	var p *pedersen.Pedersen
+	err := ctx.Injector.Resolve(&p)
+	if err != nil {
+		return nil, xerrors.Errorf("injector: %v", err)
+	}
+
+	return p.GetSuite(), nil
+}
+
+// shareHolders is the interface of an actor that can describe the share-holders
+// of the distributed key.
+type shareHolders interface {
+	dkg.Actor
+
+	GetThreshold() int
+	GetParticipants() []mino.Address
+}
+
+// statusAction is an action to display the state of the DKG.
+//
+// - implements node.ActionTemplate
+type statusAction struct{}
+
+// Execute implements node.ActionTemplate. It prints the distributed key, the
+// number of share-holders and the threshold, and warns when the share-holders
+// cannot meet the threshold anymore.
+func (a statusAction) Execute(ctx node.Context) error {
+	var actor shareHolders
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	pubkey, err := actor.GetPublicKey()
+	if err != nil {
+		fmt.Fprint(ctx.Out, "DKG is not set up")
+		return nil
+	}
+
+	num := len(actor.GetParticipants())
+	threshold := actor.GetThreshold()
+
+	fmt.Fprintf(ctx.Out, "public key: %s\nshare-holders: %d\nthreshold: %d",
+		pubkey, num, threshold)
+
+	if num < threshold {
+		fmt.Fprintf(ctx.Out, "\nwarning: cannot decrypt: only %d of required "+
+			"%d share-holders available", num, threshold)
+	}
+
+	return nil
+}
+
+// infoAction is an action to display the members of the DKG the node belongs
+// to.
+//
+// - implements node.ActionTemplate
+type infoAction struct{}
+
+// Execute implements node.ActionTemplate. It prints the addresses of the
+// share-holders encoded in base64, the threshold and the fingerprint of the
+// distributed key, which is the SHA256 digest of its binary form.
+func (a infoAction) Execute(ctx node.Context) error {
+	var actor shareHolders
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	pubkey, err := actor.GetPublicKey()
+	if err != nil {
+		return xerrors.Errorf("DKG is not set up: %v", err)
+	}
+
+	buf, err := pubkey.MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal the public key: %v", err)
+	}
+
+	fingerprint := sha256.Sum256(buf)
+
+	fmt.Fprintf(ctx.Out, "fingerprint: %x\nthreshold: %d\nmembers:",
+		fingerprint, actor.GetThreshold())
+
+	for _, addr := range actor.GetParticipants() {
+		text, err := addr.MarshalText()
+		if err != nil {
+			return xerrors.Errorf("failed to marshal address: %v", err)
+		}
+
+		fmt.Fprintf(ctx.Out, "\n%s", base64.StdEncoding.EncodeToString(text))
+	}
+
+	return nil
+}
+
+// getPublicKeyAction is an action to print the distributed key.
+//
+// - implements node.ActionTemplate
+type getPublicKeyAction struct{}
+
+// Execute implements node.ActionTemplate. It writes the distributed key in the
+// format given by the flag, or only logs it when no format is given.
+func (a getPublicKeyAction) Execute(ctx node.Context) error {
+	var actor dkg.Actor
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	pubkey, err := actor.GetPublicKey()
+	if err != nil {
+		return xerrors.Errorf("failed to retrieve the public key: %v", err)
+	}
+
+	buf, err := pubkey.MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal the public key: %v", err)
+	}
+
+	switch format := ctx.Flags.String("format"); format {
+	case "":
+		dela.Logger.Info().Hex("DKG public key", buf).Msg("DKG public key")
+	case "hex":
+		fmt.Fprint(ctx.Out, hex.EncodeToString(buf))
+	case "base64":
+		fmt.Fprint(ctx.Out, base64.StdEncoding.EncodeToString(buf))
+	case "json":
+		suite, err := getSuite(ctx)
+		if err != nil {
+			return err
+		}
+
+		desc := publicKeyDesc{
+			PublicKey: base64.StdEncoding.EncodeToString(buf),
+			Suite:     suite.String(),
+		}
+
+		data, err := json.Marshal(desc)
+		if err != nil {
+			return xerrors.Errorf("failed to encode: %v", err)
+		}
+
+		ctx.Out.Write(data)
+	default:
+		return xerrors.Errorf("unknown format '%s'", format)
+	}
+
+	return nil
+}
+
+// publicKeyDesc is the JSON description of the distributed key.
+type publicKeyDesc struct {
+	PublicKey string `json:"pubkey"`
+	Suite     string `json:"suite"`
+}
+
+// shareStore is the interface of an actor that can export and import the share
+// of the node.
+type shareStore interface {
+	dkg.Actor
+
+	Export(w io.Writer) error
+	Import(r io.Reader) error
+}
+
+// exportShareAction is an action to write the share of the node to a file so
+// that it survives a restart.
+//
+// - implements node.ActionTemplate
+type exportShareAction struct{}
+
+// Execute implements node.ActionTemplate. It writes the share to the output
+// file, which must not exist.
+func (a exportShareAction) Execute(ctx node.Context) error {
+	var actor shareStore
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	path := ctx.Flags.Path("output")
+
+	// The file is only readable by the owner as it contains the private share.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return xerrors.Errorf("failed to create file: %v", err)
+	}
+
+	err = actor.Export(file)
+	file.Close()
+
+	if err != nil {
+		os.Remove(path)
+		return xerrors.Errorf("failed to export: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "share exported to %s", path)
+
+	return nil
+}
+
+// importShareAction is an action to restore the share of the node from a file.
+//
+// - implements node.ActionTemplate
+type importShareAction struct{}
+
+// Execute implements node.ActionTemplate. It reads the share from the input
+// file.
+func (a importShareAction) Execute(ctx node.Context) error {
+	var actor shareStore
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	err = importShare(actor, ctx.Flags.Path("input"))
+	if err != nil {
+		return xerrors.Errorf("failed to import: %v", err)
+	}
+
+	fmt.Fprint(ctx.Out, "share imported")
+
+	return nil
+}
+
+func importShare(actor shareStore, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return xerrors.Errorf("failed to open file: %v", err)
+	}
+
+	defer file.Close()
+
+	err = actor.Import(file)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ciphertext is the JSON representation of an encrypted message, where both
+// points are hex-encoded.
+type ciphertext struct {
+	K string `json:"K"`
+	C string `json:"C"`
+}
+
+// encryptAction is an action to encrypt a message with the distributed key.
+//
+// - implements node.ActionTemplate
+type encryptAction struct{}
+
+// Execute implements node.ActionTemplate. It reads the hex-encoded plaintext
+// and prints the ciphertext as "$K_HEX:$C_HEX" when it fits in a single point,
+// or as a JSON array of the ciphertexts of each chunk otherwise. When an input
+// file is given, its content is encrypted and the ciphertext file is written
+// to the output.
+func (a encryptAction) Execute(ctx node.Context) error {
+	var actor dkg.Actor
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	input := ctx.Flags.Path("inputFile")
+	if input != "" {
+		return encryptFile(ctx, actor, input)
+	}
+
+	msg, err := hex.DecodeString(ctx.Flags.String("plaintext"))
+	if err != nil {
+		return xerrors.Errorf("failed to decode plaintext: %v", err)
+	}
+
+	cts, err := encryptChunks(actor, msg)
+	if err != nil {
+		return xerrors.Errorf("failed to encrypt: %v", err)
+	}
+
+	if len(cts) == 1 {
+		fmt.Fprint(ctx.Out, cts[0].K+separator+cts[0].C)
+		return nil
+	}
+
+	data, err := json.Marshal(cts)
+	if err != nil {
+		return xerrors.Errorf("failed to encode ciphertexts: %v", err)
+	}
+
+	fmt.Fprint(ctx.Out, string(data))
+
+	return nil
+}
+
+// encryptFile encrypts the content of the input file chunk by chunk and writes
+// the ciphertext file to the output.
+func encryptFile(ctx node.Context, actor dkg.Actor, path string) error {
+	inout, err := openInOut(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	defer inout.Close()
+
+	num, err := encryptStream(actor, inout.in, inout.out)
+	if err != nil {
+		return xerrors.Errorf("failed to encrypt: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "%d chunk(s) written", num)
+
+	return nil
+}
+
+// inOut is a pair of input and output files.
+type inOut struct {
+	in  *os.File
+	out *os.File
+}
+
+// openInOut opens the input file and creates the output file given by the
+// flag.
+func openInOut(ctx node.Context, path string) (inOut, error) {
+	output := ctx.Flags.Path("outputFile")
+	if output == "" {
+		return inOut{}, xerrors.New("outputFile is required with an inputFile")
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return inOut{}, xerrors.Errorf("failed to open input: %v", err)
+	}
+
+	out, err := os.Create(output)
+	if err != nil {
+		in.Close()
+		return inOut{}, xerrors.Errorf("failed to create output: %v", err)
+	}
+
+	return inOut{in: in, out: out}, nil
+}
+
+// Close closes both files.
+func (f inOut) Close() {
+	f.in.Close()
+	f.out.Close()
+}
+
+// encryptBatchAction is an action to encrypt a file of messages with the
+// distributed key.
+//
+// - implements node.ActionTemplate
+type encryptBatchAction struct{}
+
+// Execute implements node.ActionTemplate. It reads the input file line by line,
+// each line being a hex-encoded plaintext, and writes a JSON array of the
+// ciphertexts in the same order to the output file. Empty lines are ignored.
+func (a encryptBatchAction) Execute(ctx node.Context) error {
+	var actor dkg.Actor
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	input, err := os.Open(ctx.Flags.String("input"))
+	if err != nil {
+		return xerrors.Errorf("failed to open input: %v", err)
+	}
+
+	defer input.Close()
+
+	path := ctx.Flags.String("output")
+
+	output, err := os.Create(path)
+	if err != nil {
+		return xerrors.Errorf("failed to create output: %v", err)
+	}
+
+	num, err := writeCiphertexts(actor, input, output)
+	output.Close()
+
+	if err != nil {
+		// The output is removed as it would otherwise be a truncated array.
+		os.Remove(path)
+		return xerrors.Errorf("failed to encrypt batch: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "%d ciphertext(s) written", num)
+
+	return nil
+}
+
+// writeCiphertexts encrypts the lines of the reader and writes the ciphertexts
+// as a JSON array, one at a time, so that the whole batch is never in memory.
+func writeCiphertexts(actor dkg.Actor, r io.Reader, w io.Writer) (int, error) {
+	scanner := bufio.NewScanner(r)
+	writer := bufio.NewWriter(w)
+	enc := json.NewEncoder(writer)
+
+	writer.WriteString("[")
+
+	num := 0
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		msg, err := hex.DecodeString(text)
+		if err != nil {
+			return num, xerrors.Errorf("line %d: failed to decode plaintext: %v",
+				line, err)
+		}
+
+		ct, err := encrypt(actor, msg)
+		if err != nil {
+			return num, xerrors.Errorf("line %d: %v", line, err)
+		}
+
+		if num > 0 {
+			writer.WriteString(",")
+		}
+
+		err = enc.Encode(ct)
+		if err != nil {
+			return num, xerrors.Errorf("failed to encode ciphertext: %v", err)
+		}
+
+		num++
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return num, xerrors.Errorf("failed to read input: %v", err)
+	}
+
+	writer.WriteString("]")
+
+	err = writer.Flush()
+	if err != nil {
+		return num, xerrors.Errorf("failed to write output: %v", err)
+	}
+
+	return num, nil
+}
+
+// decryptAction is an action to decrypt a ciphertext with the participants of
+// the DKG.
+//
+// - implements node.ActionTemplate
+type decryptAction struct{}
+
+// Execute implements node.ActionTemplate. It reads the ciphertext, either in
+// the form "$K_HEX:$C_HEX" or as a JSON array of the ciphertexts of each chunk,
+// and prints the hex-encoded plaintext. When an input file is given, the
+// ciphertext file is decrypted chunk by chunk and the plaintext is written to
+// the output.
+func (a decryptAction) Execute(ctx node.Context) error {
+	var actor dkg.Actor
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	suite, err := getSuite(ctx)
+	if err != nil {
+		return err
+	}
+
+	input := ctx.Flags.Path("inputFile")
+	if input != "" {
+		return decryptFile(ctx, actor, suite, input)
+	}
+
+	cts, err := readCiphertexts(ctx.Flags.String("ciphertext"))
+	if err != nil {
+		return xerrors.Errorf("failed to read ciphertext: %v", err)
+	}
+
+	proofFile := ctx.Flags.Path("proofFile")
+	if proofFile == "" {
+		msg, err := decryptChunks(actor, suite, cts)
+		if err != nil {
+			return xerrors.Errorf("failed to decrypt: %v", err)
+		}
+
+		fmt.Fprint(ctx.Out, hex.EncodeToString(msg))
+
+		return nil
+	}
+
+	msg, proofs, err := decryptChunksWithProof(actor, suite, cts)
+	if err != nil {
+		return xerrors.Errorf("failed to decrypt: %v", err)
+	}
+
+	err = writeProofs(proofFile, proofs)
+	if err != nil {
+		return xerrors.Errorf("failed to write proofs: %v", err)
+	}
+
+	fmt.Fprint(ctx.Out, hex.EncodeToString(msg))
+
+	return nil
+}
+
+// decryptFile decrypts the ciphertext file chunk by chunk and writes the
+// plaintext to the output. The proofs are not supported for a file.
+func decryptFile(ctx node.Context, actor dkg.Actor, suite suites.Suite, path string) error {
+	if ctx.Flags.Path("proofFile") != "" {
+		return xerrors.New("proofs are not supported with an inputFile")
+	}
+
+	inout, err := openInOut(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	defer inout.Close()
+
+	num, err := decryptStream(actor, suite, inout.in, inout.out)
+	if err != nil {
+		return xerrors.Errorf("failed to decrypt: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "%d chunk(s) decrypted", num)
+
+	return nil
+}
+
+// decryptBatchAction is an action to decrypt a file of ciphertexts with a
+// single collection of the partial decryptions.
+//
+// - implements node.ActionTemplate
+type decryptBatchAction struct{}
+
+// Execute implements node.ActionTemplate. It reads the JSON array of
+// ciphertexts from the input file, as written by encryptBatch, and writes a
+// JSON array of the results in the same order to the output file. A ciphertext
+// that cannot be decrypted is reported with its error in place of the
+// plaintext.
+func (a decryptBatchAction) Execute(ctx node.Context) error {
+	var actor dkg.Actor
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	suite, err := getSuite(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(ctx.Flags.String("input"))
+	if err != nil {
+		return xerrors.Errorf("failed to read input: %v", err)
+	}
+
+	var cts []ciphertext
+
+	err = json.Unmarshal(data, &cts)
+	if err != nil {
+		return xerrors.Errorf("failed to decode input: %v", err)
+	}
+
+	results, err := decryptBatch(actor, suite, cts)
+	if err != nil {
+		return xerrors.Errorf("failed to decrypt batch: %v", err)
+	}
+
+	data, err = json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("failed to encode results: %v", err)
+	}
+
+	err = ioutil.WriteFile(ctx.Flags.String("output"), data, 0644)
+	if err != nil {
+		return xerrors.Errorf("failed to write output: %v", err)
+	}
+
+	failures := 0
+	for _, res := range results {
+		if res.Error != "" {
+			failures++
+		}
+	}
+
+	fmt.Fprintf(ctx.Out, "%d ciphertext(s) decrypted, %d failure(s)",
+		len(results)-failures, failures)
+
+	return nil
+}
+
+// batchResult is the JSON representation of the result of the decryption of a
+// ciphertext in a batch, which is either the hex-encoded plaintext or the
+// error.
+type batchResult struct {
+	Plaintext string `json:"plaintext,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// decryptBatch decrypts the ciphertexts in a single batch. The failures of
+// individual ciphertexts are reported in the results, and an error is returned
+// only when the whole batch fails.
+func decryptBatch(actor dkg.Actor, suite suites.Suite,
+	cts []ciphertext) ([]batchResult, error) {
+
+	results := make([]batchResult, len(cts))
+	batch := make([]dkg.Ciphertext, len(cts))
+
+	for i, ct := range cts {
+		K, C, err := decodeCiphertext(suite, ct)
+		if err != nil {
+			// The ciphertext is left incomplete so that the actor reports it.
+			results[i].Error = xerrors.Errorf("failed to decode: %v", err).Error()
+			continue
+		}
+
+		batch[i] = dkg.Ciphertext{K: K, C: C}
+	}
+
+	msgs, err := actor.DecryptBatch(batch)
+
+	failures, isBatchErr := err.(dkg.BatchError)
+	if err != nil && !isBatchErr {
+		return nil, err
+	}
+
+	for i := range results {
+		if results[i].Error != "" {
+			continue
+		}
+
+		reason, failed := failures[i]
+		if failed {
+			results[i].Error = reason.Error()
+			continue
+		}
+
+		results[i].Plaintext = hex.EncodeToString(msgs[i])
+	}
+
+	return results, nil
+}
+
+// verifyDecryptAction is an action to verify the decryption of a ciphertext
+// with the proofs written by the decrypt command.
+//
+// - implements node.ActionTemplate
+type verifyDecryptAction struct{}
+
+// Execute implements node.ActionTemplate. It verifies the proof of each chunk
+// of the ciphertext against the distributed key, and compares the recovered
+// message with the claimed plaintext.
+func (a verifyDecryptAction) Execute(ctx node.Context) error {
+	suite, err := getSuite(ctx)
+	if err != nil {
+		return err
+	}
+
+	cts, err := readCiphertexts(ctx.Flags.String("ciphertext"))
+	if err != nil {
+		return xerrors.Errorf("failed to read ciphertext: %v", err)
+	}
+
+	plaintext, err := hex.DecodeString(ctx.Flags.String("plaintext"))
+	if err != nil {
+		return xerrors.Errorf("failed to decode plaintext: %v", err)
+	}
+
+	pubkey, err := decodePoint(suite, ctx.Flags.String("pubkey"))
+	if err != nil {
+		return xerrors.Errorf("failed to decode public key: %v", err)
+	}
+
+	proofs, err := readProofs(suite, ctx.Flags.Path("proofFile"))
+	if err != nil {
+		return xerrors.Errorf("failed to read proofs: %v", err)
+	}
+
+	if len(proofs) != len(cts) {
+		return xerrors.Errorf("expected %d proof(s), got %d", len(cts), len(proofs))
+	}
+
+	msg := []byte{}
+
+	for i, ct := range cts {
+		K, C, err := decodeCiphertext(suite, ct)
+		if err != nil {
+			return xerrors.Errorf("chunk %d: failed to decode: %v", i, err)
+		}
+
+		chunk, err := pedersen.VerifyDecryptionWithSuite(suite, pubkey, K, C, proofs[i])
+		if err != nil {
+			return xerrors.Errorf("chunk %d: %v", i, err)
+		}
+
+		msg = append(msg, chunk...)
+	}
+
+	if !bytes.Equal(msg, plaintext) {
+		return xerrors.New("plaintext does not match the decryption")
+	}
+
+	fmt.Fprint(ctx.Out, "decryption is valid")
+
+	return nil
+}
+
+func readCiphertexts(str string) ([]ciphertext, error) {
+	if strings.HasPrefix(str, "[") {
+		var cts []ciphertext
+
+		err := json.Unmarshal([]byte(str), &cts)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode JSON: %v", err)
+		}
+
+		if len(cts) == 0 {
+			return nil, xerrors.New("empty list of ciphertexts")
+		}
+
+		return cts, nil
+	}
+
+	parts := strings.Split(str, separator)
+	if len(parts) != 2 {
+		return nil, xerrors.New("invalid ciphertext, expected $K_HEX:$C_HEX")
+	}
+
+	return []ciphertext{{K: parts[0], C: parts[1]}}, nil
+}
+
+// encrypt encrypts a message that must fit in a single point.
+func encrypt(actor dkg.Actor, msg []byte) (ciphertext, error) {
+	ct, remainder, err := encryptChunk(actor, msg)
+	if err != nil {
+		return ct, err
+	}
+
+	if len(remainder) > 0 {
+		return ciphertext{}, xerrors.Errorf("message too long by %d byte(s)",
+			len(remainder))
+	}
+
+	return ct, nil
+}
+
+// encryptChunks splits the message into chunks that fit in a point and
+// encrypts each of them. An empty message gives a single ciphertext.
+func encryptChunks(actor dkg.Actor, msg []byte) ([]ciphertext, error) {
+	var cts []ciphertext
+
+	for {
+		ct, remainder, err := encryptChunk(actor, msg)
+		if err != nil {
+			return nil, xerrors.Errorf("chunk %d: %v", len(cts), err)
+		}
+
+		cts = append(cts, ct)
+
+		if len(remainder) == 0 {
+			return cts, nil
+		}
+
+		if len(remainder) >= len(msg) {
+			return nil, xerrors.Errorf("chunk %d: no data embedded", len(cts)-1)
+		}
+
+		msg = remainder
+	}
+}
+
+// encryptChunk encrypts as much as possible of the message in a single point
+// and returns the data left.
+func encryptChunk(actor dkg.Actor, msg []byte) (ciphertext, []byte, error) {
+	K, C, remainder, err := actor.Encrypt(msg)
+	if err != nil {
+		return ciphertext{}, nil, xerrors.Errorf("encryption failed: %v", err)
+	}
+
+	kbuf, err := K.MarshalBinary()
+	if err != nil {
+		return ciphertext{}, nil, xerrors.Errorf("failed to marshal K: %v", err)
+	}
+
+	cbuf, err := C.MarshalBinary()
+	if err != nil {
+		return ciphertext{}, nil, xerrors.Errorf("failed to marshal C: %v", err)
+	}
+
+	ct := ciphertext{K: hex.EncodeToString(kbuf), C: hex.EncodeToString(cbuf)}
+
+	return ct, remainder, nil
+}
+
+// decryptChunks decrypts the ciphertexts and reassembles the message.
+func decryptChunks(actor dkg.Actor, suite suites.Suite, cts []ciphertext) ([]byte, error) {
+	msg := []byte{}
+
+	for i, ct := range cts {
+		K, C, err := decodeCiphertext(suite, ct)
+		if err != nil {
+			return nil, xerrors.Errorf("chunk %d: failed to decode: %v", i, err)
+		}
+
+		chunk, err := actor.Decrypt(K, C)
+		if err != nil {
+			return nil, xerrors.Errorf("chunk %d: %v", i, err)
+		}
+
+		msg = append(msg, chunk...)
+	}
+
+	return msg, nil
+}
+
+// decryptChunksWithProof decrypts the ciphertexts and reassembles the message,
+// and returns the proof of the decryption of each chunk.
+func decryptChunksWithProof(actor dkg.Actor, suite suites.Suite,
+	cts []ciphertext) ([]byte, []dkg.DecryptionProof, error) {
+
+	msg := []byte{}
+	proofs := make([]dkg.DecryptionProof, len(cts))
+
+	for i, ct := range cts {
+		K, C, err := decodeCiphertext(suite, ct)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("chunk %d: failed to decode: %v", i, err)
+		}
+
+		chunk, proof, err := actor.DecryptWithProof(K, C)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("chunk %d: %v", i, err)
+		}
+
+		msg = append(msg, chunk...)
+		proofs[i] = proof
+	}
+
+	return msg, proofs, nil
+}
+
+// decryptionProof is the JSON representation of the proof of the decryption
+// of a chunk, where the points and the scalars are hex-encoded.
+type decryptionProof struct {
+	Commits  []string            `json:"commits"`
+	Partials []partialDecryption `json:"partials"`
+}
+
+// partialDecryption is the JSON representation of a partial decryption and of
+// its DLEQ proof.
+type partialDecryption struct {
+	Index int    `json:"index"`
+	V     string `json:"V"`
+	C     string `json:"C"`
+	R     string `json:"R"`
+	VG    string `json:"VG"`
+	VH    string `json:"VH"`
+}
+
+func writeProofs(path string, proofs []dkg.DecryptionProof) error {
+	out := make([]decryptionProof, len(proofs))
+
+	for i, proof := range proofs {
+		for _, commit := range proof.Commits {
+			str, err := encodeBinary(commit)
+			if err != nil {
+				return xerrors.Errorf("failed to encode commit: %v", err)
+			}
+
+			out[i].Commits = append(out[i].Commits, str)
+		}
+
+		for _, partial := range proof.Partials {
+			p, err := encodePartial(partial)
+			if err != nil {
+				return xerrors.Errorf("failed to encode partial %d: %v",
+					partial.Index, err)
+			}
+
+			out[i].Partials = append(out[i].Partials, p)
+		}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("failed to marshal: %v", err)
+	}
+
+	err = ioutil.WriteFile(path, data, 0644)
+	if err != nil {
+		return xerrors.Errorf("failed to write file: %v", err)
+	}
+
+	return nil
+}
+
+func encodePartial(partial dkg.PartialDecryption) (partialDecryption, error) {
+	values := []encoding.BinaryMarshaler{
+		partial.V, partial.Proof.C, partial.Proof.R, partial.Proof.VG, partial.Proof.VH,
+	}
+
+	strs := make([]string, len(values))
+
+	for i, value := range values {
+		str, err := encodeBinary(value)
+		if err != nil {
+			return partialDecryption{}, err
+		}
+
+		strs[i] = str
+	}
+
+	p := partialDecryption{
+		Index: partial.Index,
+		V:     strs[0],
+		C:     strs[1],
+		R:     strs[2],
+		VG:    strs[3],
+		VH:    strs[4],
+	}
+
+	return p, nil
+}
+
+func readProofs(suite suites.Suite, path string) ([]dkg.DecryptionProof, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read file: %v", err)
+	}
+
+	var in []decryptionProof
+
+	err = json.Unmarshal(data, &in)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal: %v", err)
+	}
+
+	proofs := make([]dkg.DecryptionProof, len(in))
+
+	for i, proof := range in {
+		for _, str := range proof.Commits {
+			commit, err := decodePoint(suite, str)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to decode commit: %v", err)
+			}
+
+			proofs[i].Commits = append(proofs[i].Commits, commit)
+		}
+
+		for _, p := range proof.Partials {
+			partial, err := decodePartial(suite, p)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to decode partial %d: %v",
+					p.Index, err)
+			}
+
+			proofs[i].Partials = append(proofs[i].Partials, partial)
+		}
+	}
+
+	return proofs, nil
+}
+
+func decodePartial(suite suites.Suite, p partialDecryption) (dkg.PartialDecryption, error) {
+	partial := dkg.PartialDecryption{
+		Index: p.Index,
+		Proof: &dleq.Proof{},
+	}
+
+	var err error
+
+	partial.V, err = decodePoint(suite, p.V)
+	if err != nil {
+		return partial, xerrors.Errorf("V: %v", err)
+	}
+
+	partial.Proof.C, err = decodeScalar(suite, p.C)
+	if err != nil {
+		return partial, xerrors.Errorf("C: %v", err)
+	}
+
+	partial.Proof.R, err = decodeScalar(suite, p.R)
+	if err != nil {
+		return partial, xerrors.Errorf("R: %v", err)
+	}
+
+	partial.Proof.VG, err = decodePoint(suite, p.VG)
+	if err != nil {
+		return partial, xerrors.Errorf("VG: %v", err)
+	}
+
+	partial.Proof.VH, err = decodePoint(suite, p.VH)
+	if err != nil {
+		return partial, xerrors.Errorf("VH: %v", err)
+	}
+
+	return partial, nil
+}
+
+func encodeBinary(value encoding.BinaryMarshaler) (string, error) {
+	buf, err := value.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+func decodeScalar(suite suites.Suite, str string) (kyber.Scalar, error) {
+	buf, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, xerrors.Errorf("hex: %v", err)
+	}
+
+	scalar := suite.Scalar()
+
+	err = scalar.UnmarshalBinary(buf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal scalar: %v", err)
+	}
+
+	return scalar, nil
+}
+
+func decodeCiphertext(suite suites.Suite, ct ciphertext) (kyber.Point, kyber.Point, error) {
+	K, err := decodePoint(suite, ct.K)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("K: %v", err)
+	}
+
+	C, err := decodePoint(suite, ct.C)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("C: %v", err)
+	}
+
+	return K, C, nil
+}
+
+// decodePoint decodes a hex-encoded point of the suite. A point of another
+// suite is rejected by its length before it is unmarshaled, so that the error
+// is explicit.
+func decodePoint(suite suites.Suite, str string) (kyber.Point, error) {
+	buf, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, xerrors.Errorf("hex: %v", err)
+	}
+
+	point := suite.Point()
+
+	if len(buf) != point.MarshalSize() {
+		return nil, xerrors.Errorf("not a point of suite %s: expected %d "+
+			"byte(s), got %d", suite, point.MarshalSize(), len(buf))
+	}
+
+	err = point.UnmarshalBinary(buf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal point: %v", err)
+	}
+
+	return point, nil
+}
+
+// benchReport is the result of a decryption benchmark. The durations are in
+// milliseconds.
+type benchReport struct {
+	Count      int     `json:"count"`
+	Total      float64 `json:"total_ms"`
+	Throughput float64 `json:"throughput"`
+	P50        float64 `json:"p50_ms"`
+	P90        float64 `json:"p90_ms"`
+	P99        float64 `json:"p99_ms"`
+	Max        float64 `json:"max_ms"`
+}
+
+// benchDecryptAction is an action to measure the throughput of the threshold
+// decryption of the DKG.
+//
+// - implements node.ActionTemplate
+type benchDecryptAction struct{}
+
+// Execute implements node.ActionTemplate. It encrypts random messages with the
+// distributed key and decrypts them with the participants of the DKG, then it
+// prints a JSON report of the throughput and the latency of the decryptions.
+func (a benchDecryptAction) Execute(ctx node.Context) error {
+	count := ctx.Flags.Int("count")
+	if count <= 0 {
+		return xerrors.Errorf("count must be positive, got %d", count)
+	}
+
+	var actor dkg.Actor
+	err := ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("injector: %v", err)
+	}
+
+	type ciphertext struct {
+		K, C kyber.Point
+		msg  []byte
+	}
+
+	ciphertexts := make([]ciphertext, count)
+
+	for i := range ciphertexts {
+		msg := make([]byte, benchMessageSize)
+
+		_, err = rand.Read(msg)
+		if err != nil {
+			return xerrors.Errorf("failed to generate message: %v", err)
+		}
+
+		K, C, _, err := actor.Encrypt(msg)
+		if err != nil {
+			return xerrors.Errorf("failed to encrypt: %v", err)
+		}
+
+		ciphertexts[i] = ciphertext{K: K, C: C, msg: msg}
+	}
+
+	latencies := make([]time.Duration, count)
+
+	start := time.Now()
+
+	for i, ct := range ciphertexts {
+		opStart := time.Now()
+
+		msg, err := actor.Decrypt(ct.K, ct.C)
+		if err != nil {
+			return xerrors.Errorf("failed to decrypt: %v", err)
+		}
+
+		latencies[i] = time.Since(opStart)
+
+		if !bytes.Equal(msg, ct.msg) {
+			return xerrors.Errorf("decryption %d does not match the message", i)
+		}
+	}
+
+	total := time.Since(start)
+
+	report := makeBenchReport(latencies, total)
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return xerrors.Errorf("failed to encode report: %v", err)
+	}
+
+	fmt.Fprint(ctx.Out, string(data))
+
+	return nil
+}
+
+func makeBenchReport(latencies []time.Duration, total time.Duration) benchReport {
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	percentile := func(p int) float64 {
+		index := (len(sorted)*p+99)/100 - 1
+		if index < 0 {
+			index = 0
+		}
+
+		return toMillis(sorted[index])
+	}
+
+	report := benchReport{
+		Count: len(sorted),
+		Total: toMillis(total),
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+		Max:   toMillis(sorted[len(sorted)-1]),
+	}
+
+	if total > 0 {
+		report.Throughput = float64(len(sorted)) / total.Seconds()
+	}
+
+	return report
+}
+
+func toMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
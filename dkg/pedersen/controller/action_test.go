@@ -0,0 +1,1527 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/dkg"
+	"go.dedis.ch/dela/dkg/pedersen"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/suites"
+	"golang.org/x/xerrors"
+)
+
+// suite is the Kyber suite of the Pedersen DKG.
+var suite = suites.MustFind("Ed25519")
+
+func TestListenAction_Execute(t *testing.T) {
+	action := listenAction{}
+
+	ctx := prepContext()
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+
+	var actor dkg.Actor
+	require.NoError(t, ctx.Injector.Resolve(&actor))
+
+	// The actor is not created again when the DKG is already listening.
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "DKG is already listening", buffer.String())
+
+	var same dkg.Actor
+	require.NoError(t, ctx.Injector.Resolve(&same))
+	require.True(t, actor == same)
+
+	ctx.Injector = node.NewInjector()
+	ctx.Injector.Inject(fakeDKG{err: fake.GetError()})
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to listen"))
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'dkg.DKG'")
+}
+
+func TestExportAction_Execute(t *testing.T) {
+	action := exportAction{}
+
+	ctx := prepContext()
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+	require.Regexp(t, "^AAAAAA==:[a-zA-Z0-9+/]+=*$", buffer.String())
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'mino.Mino'")
+
+	ctx.Injector.Inject(fake.NewBadMino())
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to marshal address"))
+
+	ctx.Injector.Inject(fake.Mino{})
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for '*pedersen.Pedersen'")
+}
+
+func TestSetupAction_Execute(t *testing.T) {
+	action := setupAction{}
+
+	actor := &fakeActor{}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{makeMember(t), makeMember(t)}
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, actor.threshold)
+	require.Regexp(t, "^setup done, public key: ", buffer.String())
+	require.WithinDuration(t, time.Now().Add(defaultSetupTimeout), actor.deadline, time.Minute)
+
+	ctx.Flags.(node.FlagSet)["timeout"] = float64(time.Second)
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Second), actor.deadline, time.Second)
+
+	ctx.Flags.(node.FlagSet)["threshold"] = 1
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, actor.threshold)
+
+	ctx.Flags.(node.FlagSet)["threshold"] = 3
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "threshold must be between 1 and 2, got 3")
+
+	// A threshold of zero is the default 2/3 majority.
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{
+		makeMember(t), makeMember(t), makeMember(t), makeMember(t),
+	}
+	ctx.Flags.(node.FlagSet)["threshold"] = 0
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, actor.threshold)
+
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{makeMember(t), makeMember(t)}
+
+	ctx.Flags.(node.FlagSet)["threshold"] = -1
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "threshold must be between 1 and 2, got -1")
+
+	ctx.Flags.(node.FlagSet)["threshold"] = 2
+	ctx.Injector.Inject(&fakeActor{err: fake.GetError()})
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to setup"))
+
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{""}
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"failed to read roster: failed to decode: invalid member base64 string")
+
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{makeMember(t)}
+	ctx.Flags.(node.FlagSet)["threshold"] = 1
+	ctx.Injector = prepContext().Injector
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'dkg.Actor'")
+}
+
+func TestSetupAction_DryRun_Execute(t *testing.T) {
+	action := setupAction{}
+
+	unreachable := fake.NewAddress(1)
+
+	actor := &fakeActor{unreachable: unreachable}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["dry-run"] = true
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{makeMember(t), makeMember(t)}
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, actor.threshold)
+	require.Equal(t, "member 0 (fake.Address[0]): reachable\n"+
+		"member 1 (fake.Address[0]): reachable\n"+
+		"dry run done, 2 member(s) reachable", buffer.String())
+
+	buffer.Reset()
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{
+		makeMember(t), "", makeMemberAt(t, unreachable),
+	}
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "dry run failed for 2 of 3 member(s)")
+	require.Equal(t, "member 0 (fake.Address[0]): reachable\n"+
+		"member 1: failed to decode: invalid member base64 string\n"+
+		"member 2 (fake.Address[1]): unreachable: "+fake.Err("unreachable")+"\n",
+		buffer.String())
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'controller.pinger'")
+}
+
+func TestMajority(t *testing.T) {
+	require.Equal(t, 1, majority(1))
+	require.Equal(t, 2, majority(2))
+	require.Equal(t, 2, majority(3))
+	require.Equal(t, 3, majority(4))
+	require.Equal(t, 4, majority(5))
+	require.Equal(t, 4, majority(6))
+	require.Equal(t, 7, majority(10))
+}
+
+func TestReshareAction_Execute(t *testing.T) {
+	action := reshareAction{}
+
+	actor := &fakeActor{}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{
+		makeMember(t), makeMember(t), makeMember(t), makeMember(t),
+	}
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	// The threshold defaults to a 2/3 majority of the new members.
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, actor.threshold)
+	require.Regexp(t, "^resharing done, public key: ", buffer.String())
+
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{makeMember(t), makeMember(t)}
+
+	ctx.Flags.(node.FlagSet)["threshold"] = 1
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, actor.threshold)
+
+	ctx.Flags.(node.FlagSet)["threshold"] = 3
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "threshold must be between 1 and 2, got 3")
+
+	ctx.Flags.(node.FlagSet)["threshold"] = 2
+	actor.reshareErr = fake.GetError()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to reshare"))
+
+	actor.reshareErr = nil
+	actor.err = fake.GetError()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to get public key"))
+
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{""}
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"failed to read roster: failed to decode: invalid member base64 string")
+
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{makeMember(t)}
+	ctx.Flags.(node.FlagSet)["threshold"] = 1
+	ctx.Injector = prepContext().Injector
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'dkg.Actor'")
+}
+
+func TestGetPublicKeyAction_Execute(t *testing.T) {
+	action := getPublicKeyAction{}
+
+	actor := &fakeActor{}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	pubkey, err := suite.Point().MarshalBinary()
+	require.NoError(t, err)
+
+	// Without a format, the key is only logged.
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Empty(t, buffer.String())
+
+	ctx.Flags.(node.FlagSet)["format"] = "hex"
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(pubkey), buffer.String())
+
+	buffer.Reset()
+	ctx.Flags.(node.FlagSet)["format"] = "base64"
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, base64.StdEncoding.EncodeToString(pubkey), buffer.String())
+
+	buffer.Reset()
+	ctx.Flags.(node.FlagSet)["format"] = "json"
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.JSONEq(t, fmt.Sprintf(`{"pubkey":"%s","suite":"Ed25519"}`,
+		base64.StdEncoding.EncodeToString(pubkey)), buffer.String())
+
+	ctx.Flags.(node.FlagSet)["format"] = "xml"
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "unknown format 'xml'")
+
+	actor.err = fake.GetError()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to retrieve the public key"))
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'dkg.Actor'")
+}
+
+func TestStatusAction_Execute(t *testing.T) {
+	action := statusAction{}
+
+	actor := &fakeActor{
+		threshold:    2,
+		participants: []mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+	}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+	require.Regexp(t, "^public key: .+\nshare-holders: 2\nthreshold: 2$", buffer.String())
+
+	buffer.Reset()
+	actor.participants = actor.participants[:1]
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Contains(t, buffer.String(),
+		"warning: cannot decrypt: only 1 of required 2 share-holders available")
+
+	// The Pedersen actor is not set up yet.
+	var p *pedersen.Pedersen
+	require.NoError(t, ctx.Injector.Resolve(&p))
+
+	pactor, err := p.Listen()
+	require.NoError(t, err)
+
+	buffer.Reset()
+	ctx.Injector = node.NewInjector()
+	ctx.Injector.Inject(pactor)
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "DKG is not set up", buffer.String())
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'controller.shareHolders'")
+}
+
+func TestInfoAction_Execute(t *testing.T) {
+	actor := &fakeActor{}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{
+		makeMemberAt(t, fake.NewAddress(1)), makeMemberAt(t, fake.NewAddress(2)),
+	}
+
+	err := setupAction{}.Execute(ctx)
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err = infoAction{}.Execute(ctx)
+	require.NoError(t, err)
+
+	lines := strings.Split(buffer.String(), "\n")
+	require.Len(t, lines, 5)
+	require.Regexp(t, "^fingerprint: [0-9a-f]{64}$", lines[0])
+	require.Equal(t, "threshold: 2", lines[1])
+	require.Equal(t, "members:", lines[2])
+
+	// The members are the ones passed to the setup.
+	for i, member := range ctx.Flags.StringSlice("member") {
+		require.Equal(t, strings.Split(member, separator)[0], lines[3+i])
+	}
+
+	actor.participants = []mino.Address{fake.NewBadAddress()}
+	err = infoAction{}.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to marshal address"))
+
+	// The Pedersen actor is not set up yet.
+	var p *pedersen.Pedersen
+	require.NoError(t, ctx.Injector.Resolve(&p))
+
+	pactor, err := p.Listen()
+	require.NoError(t, err)
+
+	ctx.Injector = node.NewInjector()
+	ctx.Injector.Inject(pactor)
+	err = infoAction{}.Execute(ctx)
+	require.EqualError(t, err, "DKG is not set up: DKG has not been initialized")
+
+	ctx.Injector = node.NewInjector()
+	err = infoAction{}.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'controller.shareHolders'")
+}
+
+func TestExportShareAction_Execute(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "share")
+
+	action := exportShareAction{}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(&fakeActor{})
+	ctx.Flags.(node.FlagSet)["output"] = path
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "share exported to "+path, buffer.String())
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "share", string(data))
+
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to create file: ")
+
+	ctx.Flags.(node.FlagSet)["output"] = filepath.Join(dir, "bad")
+	ctx.Injector = node.NewInjector()
+	ctx.Injector.Inject(&fakeActor{shareErr: fake.GetError()})
+
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to export"))
+	require.NoFileExists(t, filepath.Join(dir, "bad"))
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'controller.shareStore'")
+}
+
+func TestImportShareAction_Execute(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "share")
+	require.NoError(t, ioutil.WriteFile(path, []byte("share"), 0600))
+
+	action := importShareAction{}
+
+	actor := &fakeActor{}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["input"] = path
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "share imported", buffer.String())
+	require.Equal(t, []byte("share"), actor.messages["share"])
+
+	actor.shareErr = fake.GetError()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to import"))
+
+	ctx.Flags.(node.FlagSet)["input"] = filepath.Join(dir, "unknown")
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to import: failed to open file: ")
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for 'controller.shareStore'")
+}
+
+func TestEncryptAction_Execute(t *testing.T) {
+	action := encryptAction{}
+
+	actor := &fakeActor{chunk: 4}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["plaintext"] = "deadbeef"
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+	require.Regexp(t, "^[0-9a-f]{64}:[0-9a-f]{64}$", buffer.String())
+
+	// A message longer than a point is written as a JSON array.
+	buffer.Reset()
+	ctx.Flags.(node.FlagSet)["plaintext"] = "deadbeefcafe"
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+
+	var cts []ciphertext
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &cts))
+	require.Len(t, cts, 2)
+
+	actor.remainder = []byte{1, 2, 3, 4, 5, 6}
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to encrypt: chunk 0: no data embedded")
+
+	ctx.Injector.Inject(&fakeActor{encErr: fake.GetError()})
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to encrypt: chunk 0: encryption failed"))
+
+	ctx.Flags.(node.FlagSet)["plaintext"] = "zz"
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"failed to decode plaintext: encoding/hex: invalid byte: U+007A 'z'")
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'dkg.Actor'")
+}
+
+func TestEncryptAction_File_Execute(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	plaintext := filepath.Join(dir, "plaintext")
+	ciphertext := filepath.Join(dir, "ciphertext")
+	decrypted := filepath.Join(dir, "decrypted")
+
+	msg := bytes.Repeat([]byte{0xaa}, 10)
+	require.NoError(t, ioutil.WriteFile(plaintext, msg, 0644))
+
+	actor := &fakeActor{chunk: 4}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["inputFile"] = plaintext
+	ctx.Flags.(node.FlagSet)["outputFile"] = ciphertext
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err = encryptAction{}.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "3 chunk(s) written", buffer.String())
+
+	buffer.Reset()
+	ctx.Flags.(node.FlagSet)["inputFile"] = ciphertext
+	ctx.Flags.(node.FlagSet)["outputFile"] = decrypted
+
+	err = decryptAction{}.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "3 chunk(s) decrypted", buffer.String())
+
+	data, err := ioutil.ReadFile(decrypted)
+	require.NoError(t, err)
+	require.Equal(t, msg, data)
+
+	ctx.Flags.(node.FlagSet)["proofFile"] = filepath.Join(dir, "proofs")
+	err = decryptAction{}.Execute(ctx)
+	require.EqualError(t, err, "proofs are not supported with an inputFile")
+
+	delete(ctx.Flags.(node.FlagSet), "proofFile")
+	ctx.Injector.Inject(&fakeActor{decErr: fake.GetError()})
+	err = decryptAction{}.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to decrypt: chunk 0"))
+
+	ctx.Injector.Inject(&fakeActor{encErr: fake.GetError()})
+	ctx.Flags.(node.FlagSet)["inputFile"] = plaintext
+	ctx.Flags.(node.FlagSet)["outputFile"] = ciphertext
+	err = encryptAction{}.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to encrypt: chunk 0: encryption failed"))
+
+	ctx.Flags.(node.FlagSet)["outputFile"] = filepath.Join(dir, "unknown", "ciphertext")
+	err = encryptAction{}.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to create output: ", err.Error())
+
+	ctx.Flags.(node.FlagSet)["inputFile"] = filepath.Join(dir, "unknown")
+	err = encryptAction{}.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to open input: ", err.Error())
+
+	delete(ctx.Flags.(node.FlagSet), "outputFile")
+	err = encryptAction{}.Execute(ctx)
+	require.EqualError(t, err, "outputFile is required with an inputFile")
+}
+
+func TestEncryptChunks(t *testing.T) {
+	actor := &fakeActor{chunk: 4}
+
+	msgs := [][]byte{
+		{},
+		{1, 2, 3},
+		{1, 2, 3, 4},
+		{1, 2, 3, 4, 5, 6, 7, 8},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+	}
+
+	chunks := []int{1, 1, 1, 2, 3}
+
+	for i, msg := range msgs {
+		cts, err := encryptChunks(actor, msg)
+		require.NoError(t, err)
+		require.Len(t, cts, chunks[i])
+
+		res, err := decryptChunks(actor, suite, cts)
+		require.NoError(t, err)
+		require.Equal(t, msg, res)
+	}
+}
+
+func TestEncryptBatchAction_Execute(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.txt")
+	output := filepath.Join(dir, "output.json")
+
+	plaintexts := []string{"aa", "bbbb", "cccccc"}
+
+	content := "aa\n\nbbbb\n   \ncccccc"
+	require.NoError(t, ioutil.WriteFile(input, []byte(content), 0644))
+
+	action := encryptBatchAction{}
+
+	actor := &fakeActor{}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["input"] = input
+	ctx.Flags.(node.FlagSet)["output"] = output
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "3 ciphertext(s) written", buffer.String())
+
+	data, err := ioutil.ReadFile(output)
+	require.NoError(t, err)
+
+	var cts []ciphertext
+	require.NoError(t, json.Unmarshal(data, &cts))
+	require.Len(t, cts, len(plaintexts))
+
+	// Round-trip through the decrypt command.
+	for i, ct := range cts {
+		buffer.Reset()
+		ctx.Flags.(node.FlagSet)["ciphertext"] = ct.K + separator + ct.C
+
+		err = decryptAction{}.Execute(ctx)
+		require.NoError(t, err)
+		require.Equal(t, plaintexts[i], buffer.String())
+	}
+
+	// An empty input gives an empty array.
+	require.NoError(t, ioutil.WriteFile(input, nil, 0644))
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+
+	data, err = ioutil.ReadFile(output)
+	require.NoError(t, err)
+	require.Equal(t, "[]", string(data))
+
+	require.NoError(t, ioutil.WriteFile(input, []byte("aa\nbbbbbbbb"), 0644))
+	actor.chunk = 2
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"failed to encrypt batch: line 2: message too long by 2 byte(s)")
+
+	require.NoError(t, ioutil.WriteFile(input, []byte("aa\nzz"), 0644))
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to encrypt batch: line 2: failed to "+
+		"decode plaintext: encoding/hex: invalid byte: U+007A 'z'")
+	require.NoFileExists(t, output)
+
+	// Empty lines are counted in the line number of the error.
+	require.NoError(t, ioutil.WriteFile(input, []byte("aa\n\n\nzz"), 0644))
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to encrypt batch: line 4: failed to "+
+		"decode plaintext: encoding/hex: invalid byte: U+007A 'z'")
+
+	require.NoError(t, ioutil.WriteFile(input, []byte("aa"), 0644))
+	ctx.Injector.Inject(&fakeActor{encErr: fake.GetError()})
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		fake.Err("failed to encrypt batch: line 1: encryption failed"))
+
+	ctx.Flags.(node.FlagSet)["output"] = filepath.Join(dir, "unknown", "output.json")
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to create output: ", err.Error())
+
+	ctx.Flags.(node.FlagSet)["input"] = filepath.Join(dir, "unknown.txt")
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to open input: ", err.Error())
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'dkg.Actor'")
+}
+
+func TestDecryptAction_Execute(t *testing.T) {
+	action := decryptAction{}
+
+	actor := &fakeActor{chunk: 2}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	// Both the single pair and the JSON array are accepted.
+	ct, err := encrypt(actor, []byte{0xaa})
+	require.NoError(t, err)
+
+	ctx.Flags.(node.FlagSet)["ciphertext"] = ct.K + separator + ct.C
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "aa", buffer.String())
+
+	cts, err := encryptChunks(actor, []byte{0xaa, 0xbb, 0xcc})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(cts)
+	require.NoError(t, err)
+
+	buffer.Reset()
+	ctx.Flags.(node.FlagSet)["ciphertext"] = string(data)
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "aabbcc", buffer.String())
+
+	point := hex.EncodeToString(mustMarshal(t, suite.Point()))
+
+	ctx.Injector.Inject(&fakeActor{decErr: fake.GetError()})
+	ctx.Flags.(node.FlagSet)["ciphertext"] = point + separator + point
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to decrypt: chunk 0"))
+
+	ctx.Flags.(node.FlagSet)["ciphertext"] = point + separator + "aa"
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.EqualError(t, err, "failed to decrypt: chunk 0: failed to decode: C: "+
+		"not a point of suite Ed25519: expected 32 byte(s), got 1")
+
+	ctx.Flags.(node.FlagSet)["ciphertext"] = point + separator + "02" + strings.Repeat("00", 31)
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to decrypt: chunk 0: failed to decode: C: "+
+		"failed to unmarshal point: invalid Ed25519 curve point")
+
+	ctx.Flags.(node.FlagSet)["ciphertext"] = "zz" + separator + point
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to decrypt: chunk 0: failed to decode: "+
+		"K: hex: encoding/hex: invalid byte: U+007A 'z'")
+
+	ctx.Flags.(node.FlagSet)["ciphertext"] = point
+	err = action.Execute(ctx)
+	require.EqualError(t, err,
+		"failed to read ciphertext: invalid ciphertext, expected $K_HEX:$C_HEX")
+
+	ctx.Flags.(node.FlagSet)["ciphertext"] = "[]"
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to read ciphertext: empty list of ciphertexts")
+
+	ctx.Flags.(node.FlagSet)["ciphertext"] = "["
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to read ciphertext: failed to decode "+
+		"JSON: unexpected end of JSON input")
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'dkg.Actor'")
+}
+
+func TestDecryptAction_WithProof_Execute(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	message := []byte("Hello world")
+	_, ct, proof := makeDecryptionProof(t, message)
+
+	action := decryptAction{}
+
+	actor := &fakeActor{proof: proof, messages: map[string][]byte{}}
+	actor.messages[ct.C] = message
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["ciphertext"] = ct.K + separator + ct.C
+	ctx.Flags.(node.FlagSet)["proofFile"] = filepath.Join(dir, "proofs.json")
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(message), buffer.String())
+
+	proofs, err := readProofs(suite, filepath.Join(dir, "proofs.json"))
+	require.NoError(t, err)
+	require.Len(t, proofs, 1)
+	require.Len(t, proofs[0].Commits, 2)
+	require.Len(t, proofs[0].Partials, 3)
+
+	actor.decErr = fake.GetError()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to decrypt: chunk 0"))
+
+	actor.decErr = nil
+	ctx.Flags.(node.FlagSet)["proofFile"] = filepath.Join(dir, "unknown", "proofs.json")
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to write proofs: failed to write file: ")
+}
+
+func TestEncryptAction_Suite_Execute(t *testing.T) {
+	p256 := suites.MustFind("P256")
+
+	actor := &fakeActor{suite: p256}
+
+	ctx := prepContextWithSuite(p256)
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["plaintext"] = "aabb"
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err := encryptAction{}.Execute(ctx)
+	require.NoError(t, err)
+
+	ct := buffer.String()
+
+	buffer.Reset()
+	ctx.Flags.(node.FlagSet)["ciphertext"] = ct
+	err = decryptAction{}.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "aabb", buffer.String())
+
+	// A ciphertext of the default suite is rejected.
+	ed25519Ctx := prepContext()
+	ed25519Ctx.Injector.Inject(&fakeActor{})
+	ed25519Ctx.Flags.(node.FlagSet)["plaintext"] = "aabb"
+
+	buffer.Reset()
+	ed25519Ctx.Out = buffer
+	err = encryptAction{}.Execute(ed25519Ctx)
+	require.NoError(t, err)
+
+	ctx.Flags.(node.FlagSet)["ciphertext"] = buffer.String()
+	err = decryptAction{}.Execute(ctx)
+	require.EqualError(t, err, "failed to decrypt: chunk 0: failed to decode: "+
+		"K: not a point of suite P256: expected 65 byte(s), got 32")
+
+	ctx.Injector = node.NewInjector()
+	ctx.Injector.Inject(actor)
+	err = decryptAction{}.Execute(ctx)
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for '*pedersen.Pedersen'")
+}
+
+func TestDecryptBatchAction_Execute(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.json")
+	output := filepath.Join(dir, "output.json")
+
+	action := decryptBatchAction{}
+
+	actor := &fakeActor{}
+
+	cts := make([]ciphertext, 2, 3)
+	for i, msg := range [][]byte{{0xaa}, {0xbb, 0xcc}} {
+		cts[i], err = encrypt(actor, msg)
+		require.NoError(t, err)
+	}
+
+	cts = append(cts, ciphertext{K: "zz", C: cts[0].C})
+
+	data, err := json.Marshal(cts)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(input, data, 0644))
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["input"] = input
+	ctx.Flags.(node.FlagSet)["output"] = output
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "2 ciphertext(s) decrypted, 1 failure(s)", buffer.String())
+
+	data, err = ioutil.ReadFile(output)
+	require.NoError(t, err)
+
+	var results []batchResult
+	require.NoError(t, json.Unmarshal(data, &results))
+	require.Equal(t, []batchResult{
+		{Plaintext: "aa"},
+		{Plaintext: "bbcc"},
+		{Error: "failed to decode: K: hex: encoding/hex: invalid byte: U+007A 'z'"},
+	}, results)
+
+	ctx.Injector.Inject(&fakeActor{decErr: fake.GetError()})
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to decrypt batch"))
+
+	ctx.Flags.(node.FlagSet)["output"] = filepath.Join(dir, "unknown", "output.json")
+	ctx.Injector.Inject(actor)
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to write output: ", err.Error())
+
+	require.NoError(t, ioutil.WriteFile(input, []byte("["), 0644))
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to decode input: unexpected end of JSON input")
+
+	ctx.Flags.(node.FlagSet)["input"] = filepath.Join(dir, "unknown.json")
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read input: ", err.Error())
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'dkg.Actor'")
+}
+
+func TestVerifyDecryptAction_Execute(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "proofs.json")
+
+	message := []byte("Hello world")
+	pubkey, ct, proof := makeDecryptionProof(t, message)
+
+	require.NoError(t, writeProofs(path, []dkg.DecryptionProof{proof}))
+
+	action := verifyDecryptAction{}
+
+	ctx := prepContext()
+	ctx.Flags.(node.FlagSet)["ciphertext"] = ct.K + separator + ct.C
+	ctx.Flags.(node.FlagSet)["plaintext"] = hex.EncodeToString(message)
+	ctx.Flags.(node.FlagSet)["pubkey"] = pubkey.String()
+	ctx.Flags.(node.FlagSet)["proofFile"] = path
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err = action.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "decryption is valid", buffer.String())
+
+	ctx.Flags.(node.FlagSet)["plaintext"] = hex.EncodeToString([]byte("Hello"))
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "plaintext does not match the decryption")
+
+	// A malicious share-holder has swapped its partial decryption with the one
+	// of another share-holder.
+	swapped := dkg.DecryptionProof{
+		Commits:  proof.Commits,
+		Partials: append([]dkg.PartialDecryption{}, proof.Partials...),
+	}
+	swapped.Partials[0].V, swapped.Partials[1].V = proof.Partials[1].V, proof.Partials[0].V
+
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, writeProofs(path, []dkg.DecryptionProof{swapped}))
+
+	ctx.Flags.(node.FlagSet)["plaintext"] = hex.EncodeToString(message)
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "chunk 0: partial decryption 0: invalid proof: invalid proof")
+
+	ctx.Flags.(node.FlagSet)["ciphertext"] = "[" + mustJSON(t, ct) + "," + mustJSON(t, ct) + "]"
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "expected 2 proof(s), got 1")
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("["), 0644))
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to read proofs: failed to unmarshal: "+
+		"unexpected end of JSON input")
+
+	ctx.Flags.(node.FlagSet)["proofFile"] = filepath.Join(dir, "unknown")
+	err = action.Execute(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to read proofs: failed to read file: ")
+
+	ctx.Flags.(node.FlagSet)["pubkey"] = "zz"
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to decode public key: hex: "+
+		"encoding/hex: invalid byte: U+007A 'z'")
+
+	ctx.Flags.(node.FlagSet)["plaintext"] = "zz"
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to decode plaintext: "+
+		"encoding/hex: invalid byte: U+007A 'z'")
+
+	ctx.Flags.(node.FlagSet)["ciphertext"] = "[]"
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "failed to read ciphertext: empty list of ciphertexts")
+}
+
+func TestReadProofs(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "proofs.json")
+
+	_, _, proof := makeDecryptionProof(t, []byte("abc"))
+	require.NoError(t, writeProofs(path, []dkg.DecryptionProof{proof}))
+
+	proofs, err := readProofs(suite, path)
+	require.NoError(t, err)
+	require.Len(t, proofs, 1)
+
+	for i, partial := range proofs[0].Partials {
+		require.Equal(t, proof.Partials[i].Index, partial.Index)
+		require.True(t, proof.Partials[i].V.Equal(partial.V))
+		require.True(t, proof.Partials[i].Proof.C.Equal(partial.Proof.C))
+		require.True(t, proof.Partials[i].Proof.R.Equal(partial.Proof.R))
+		require.True(t, proof.Partials[i].Proof.VG.Equal(partial.Proof.VG))
+		require.True(t, proof.Partials[i].Proof.VH.Equal(partial.Proof.VH))
+	}
+
+	for _, field := range []string{"V", "C", "R", "VG", "VH"} {
+		data := fmt.Sprintf(`[{"partials":[{"index":1,"%s":"zz"}]}]`, field)
+		require.NoError(t, ioutil.WriteFile(path, []byte(data), 0644))
+
+		_, err = readProofs(suite, path)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decode partial 1: ")
+	}
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`[{"commits":["zz"]}]`), 0644))
+
+	_, err = readProofs(suite, path)
+	require.EqualError(t, err, "failed to decode commit: hex: "+
+		"encoding/hex: invalid byte: U+007A 'z'")
+
+	err = writeProofs(path, []dkg.DecryptionProof{{Commits: []kyber.Point{badPoint{}}}})
+	require.EqualError(t, err, fake.Err("failed to encode commit"))
+
+	partial := proof.Partials[0]
+	partial.V = badPoint{}
+
+	err = writeProofs(path, []dkg.DecryptionProof{{Partials: []dkg.PartialDecryption{partial}}})
+	require.EqualError(t, err, fake.Err("failed to encode partial 0"))
+}
+
+func TestBenchDecryptAction_Execute(t *testing.T) {
+	action := benchDecryptAction{}
+
+	actor := &fakeActor{}
+
+	ctx := prepContext()
+	ctx.Injector.Inject(actor)
+	ctx.Flags.(node.FlagSet)["count"] = 5
+
+	buffer := new(bytes.Buffer)
+	ctx.Out = buffer
+
+	err := action.Execute(ctx)
+	require.NoError(t, err)
+
+	var report benchReport
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &report))
+	require.Equal(t, 5, report.Count)
+	require.LessOrEqual(t, report.P50, report.P99)
+
+	actor.corrupt = true
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "decryption 0 does not match the message")
+
+	ctx.Injector.Inject(&fakeActor{decErr: fake.GetError()})
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to decrypt"))
+
+	ctx.Injector.Inject(&fakeActor{encErr: fake.GetError()})
+	err = action.Execute(ctx)
+	require.EqualError(t, err, fake.Err("failed to encrypt"))
+
+	ctx.Injector = node.NewInjector()
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "injector: couldn't find dependency for 'dkg.Actor'")
+
+	ctx.Flags.(node.FlagSet)["count"] = 0
+	err = action.Execute(ctx)
+	require.EqualError(t, err, "count must be positive, got 0")
+}
+
+func TestMakeBenchReport(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(100-i) * time.Millisecond
+	}
+
+	report := makeBenchReport(latencies, time.Second)
+	require.Equal(t, 100, report.Count)
+	require.Equal(t, float64(1000), report.Total)
+	require.Equal(t, float64(100), report.Throughput)
+	require.Equal(t, float64(50), report.P50)
+	require.Equal(t, float64(90), report.P90)
+	require.Equal(t, float64(99), report.P99)
+	require.Equal(t, float64(100), report.Max)
+
+	report = makeBenchReport([]time.Duration{time.Millisecond}, 0)
+	require.Equal(t, float64(1), report.P50)
+	require.Equal(t, float64(0), report.Throughput)
+}
+
+func TestResolveMembers(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	alice := makeMemberAt(t, fake.NewAddress(0))
+	bob := makeMemberAt(t, fake.NewAddress(1))
+	carol := makeMemberAt(t, fake.NewAddress(2))
+
+	path := filepath.Join(dir, "members.json")
+
+	data, err := json.Marshal(map[string]string{"alice": alice, "bob": bob})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, data, 0644))
+
+	ctx := prepContext()
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{"bob", carol, "alice"}
+
+	// Without the file, the members are returned as they are.
+	members, err := resolveMembers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bob", carol, "alice"}, members)
+
+	ctx.Flags.(node.FlagSet)["members-file"] = path
+
+	members, err = resolveMembers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{bob, carol, alice}, members)
+
+	roster, err := readMembers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, roster.Len())
+
+	ctx.Flags.(node.FlagSet)["member"] = []interface{}{"alice", "dave"}
+	_, err = resolveMembers(ctx)
+	require.EqualError(t, err, "unknown member 'dave'")
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"alice":"a:a"}`), 0644))
+	_, err = resolveMembers(ctx)
+	require.EqualError(t, err, "members file: entry 'alice': base64 address: "+
+		"illegal base64 data at input byte 0")
+
+	data, err = json.Marshal(map[string]string{"al:ice": alice})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, data, 0644))
+	_, err = resolveMembers(ctx)
+	require.EqualError(t, err, "members file: invalid name 'al:ice'")
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`[]`), 0644))
+	_, err = resolveMembers(ctx)
+	require.EqualError(t, err, "members file: failed to decode: json: cannot "+
+		"unmarshal array into Go value of type map[string]string")
+
+	ctx.Flags.(node.FlagSet)["members-file"] = filepath.Join(dir, "unknown.json")
+	_, err = resolveMembers(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^members file: failed to read: ", err.Error())
+
+	_, err = readMembers(ctx)
+	require.Error(t, err)
+
+	err = setupAction{}.Execute(ctx)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read roster: members file: ", err.Error())
+}
+
+func TestDecodeMember(t *testing.T) {
+	ctx := prepContext()
+
+	_, _, err := decodeMember(ctx, "a:a")
+	require.EqualError(t, err, "base64 address: illegal base64 data at input byte 0")
+
+	_, _, err = decodeMember(ctx, ":a")
+	require.EqualError(t, err, "base64 public key: illegal base64 data at input byte 0")
+
+	_, _, err = decodeMember(ctx, ":")
+	require.Error(t, err)
+	require.Regexp(t, "^failed to decode public key: ", err.Error())
+
+	// The public key is decoded with the suite of the DKG.
+	p256 := suites.MustFind("P256")
+
+	_, pubkey, err := decodeMember(prepContextWithSuite(p256), makeMember(t))
+	require.Error(t, err)
+	require.Regexp(t, "^failed to decode public key: ", err.Error())
+
+	point := p256.Point().Pick(p256.RandomStream())
+	member := "AAAAAA==" + separator + base64.StdEncoding.EncodeToString(mustMarshal(t, point))
+
+	_, pubkey, err = decodeMember(prepContextWithSuite(p256), member)
+	require.NoError(t, err)
+	require.True(t, point.Equal(pubkey.(memberKey).GetPoint()))
+
+	ctx.Injector = node.NewInjector()
+	ctx.Injector.Inject(fake.Mino{})
+	_, _, err = decodeMember(ctx, makeMember(t))
+	require.EqualError(t, err,
+		"injector: couldn't find dependency for '*pedersen.Pedersen'")
+
+	ctx.Injector = node.NewInjector()
+	_, _, err = decodeMember(ctx, ":")
+	require.EqualError(t, err, "injector: couldn't find dependency for 'mino.Mino'")
+}
+
+func TestMemberKey(t *testing.T) {
+	p256 := suites.MustFind("P256")
+
+	point := p256.Point().Pick(p256.RandomStream())
+	pubkey := memberKey{point: point}
+
+	require.True(t, pubkey.Equal(memberKey{point: point.Clone()}))
+	require.False(t, pubkey.Equal(memberKey{point: p256.Point().Base()}))
+	require.False(t, pubkey.Equal(fake.PublicKey{}))
+
+	err := pubkey.Verify([]byte{}, fake.Signature{})
+	require.EqualError(t, err, "member key cannot verify signatures")
+
+	data, err := pubkey.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, mustMarshal(t, point), data)
+
+	text, err := pubkey.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("member:%x", data), string(text))
+
+	_, err = memberKey{point: badPoint{}}.MarshalText()
+	require.EqualError(t, err, fake.Err("couldn't marshal"))
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func prepContext() node.Context {
+	return prepContextWithSuite(suite)
+}
+
+func prepContextWithSuite(s suites.Suite) node.Context {
+	ctx := node.Context{
+		Injector: node.NewInjector(),
+		Flags:    make(node.FlagSet),
+		Out:      ioutil.Discard,
+	}
+
+	p, _ := pedersen.NewPedersen(fake.Mino{}, pedersen.WithSuite(s))
+
+	ctx.Injector.Inject(fake.Mino{})
+	ctx.Injector.Inject(p)
+
+	return ctx
+}
+
+func mustMarshal(t *testing.T, point kyber.Point) []byte {
+	buf, err := point.MarshalBinary()
+	require.NoError(t, err)
+
+	return buf
+}
+
+func makeMember(t *testing.T) string {
+	return makeMemberAt(t, fake.NewAddress(0))
+}
+
+func makeMemberAt(t *testing.T, addr mino.Address) string {
+	addrBuf, err := addr.MarshalText()
+	require.NoError(t, err)
+
+	pubkey, err := suite.Point().Pick(suite.RandomStream()).MarshalBinary()
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(addrBuf) + separator +
+		base64.StdEncoding.EncodeToString(pubkey)
+}
+
+type fakeDKG struct {
+	err error
+}
+
+func (d fakeDKG) Listen() (dkg.Actor, error) {
+	return nil, d.err
+}
+
+type fakeActor struct {
+	dkg.Actor
+
+	threshold  int
+	err        error
+	encErr     error
+	decErr     error
+	reshareErr error
+	shareErr   error
+	proof      dkg.DecryptionProof
+	corrupt    bool
+	chunk      int
+	remainder  []byte
+	messages   map[string][]byte
+
+	participants []mino.Address
+	deadline     time.Time
+	unreachable  mino.Address
+	suite        suites.Suite
+}
+
+func (a *fakeActor) Setup(co crypto.CollectiveAuthority, threshold int) (kyber.Point, error) {
+	a.threshold = threshold
+	a.participants = nil
+
+	iter := co.AddressIterator()
+	for iter.HasNext() {
+		a.participants = append(a.participants, iter.GetNext())
+	}
+
+	return suite.Point(), a.err
+}
+
+func (a *fakeActor) SetupContext(ctx context.Context, co crypto.CollectiveAuthority,
+	threshold int) (kyber.Point, error) {
+
+	a.deadline, _ = ctx.Deadline()
+
+	return a.Setup(co, threshold)
+}
+
+func (a *fakeActor) Reshare(co crypto.CollectiveAuthority, threshold int) error {
+	a.threshold = threshold
+
+	return a.reshareErr
+}
+
+func (a *fakeActor) Encrypt(msg []byte) (kyber.Point, kyber.Point, []byte, error) {
+	if a.messages == nil {
+		a.messages = make(map[string][]byte)
+	}
+
+	remainder := a.remainder
+	if remainder == nil && a.chunk > 0 && len(msg) > a.chunk {
+		msg, remainder = msg[:a.chunk], msg[a.chunk:]
+	}
+
+	s := a.suite
+	if s == nil {
+		s = suite
+	}
+
+	C := s.Point().Pick(s.RandomStream())
+	a.messages[C.String()] = append([]byte{}, msg...)
+
+	return s.Point().Base(), C, remainder, a.encErr
+}
+
+func (a *fakeActor) Decrypt(K, C kyber.Point) ([]byte, error) {
+	if a.corrupt {
+		return nil, nil
+	}
+
+	return a.messages[C.String()], a.decErr
+}
+
+func (a *fakeActor) DecryptBatch(cs []dkg.Ciphertext) ([][]byte, error) {
+	if a.decErr != nil {
+		return nil, a.decErr
+	}
+
+	msgs := make([][]byte, len(cs))
+	failures := dkg.BatchError{}
+
+	for i, c := range cs {
+		if c.K == nil || c.C == nil {
+			failures[i] = xerrors.New("incomplete ciphertext")
+			continue
+		}
+
+		msgs[i] = a.messages[c.C.String()]
+	}
+
+	if len(failures) > 0 {
+		return msgs, failures
+	}
+
+	return msgs, nil
+}
+
+func (a *fakeActor) DecryptWithProof(K, C kyber.Point) ([]byte, dkg.DecryptionProof, error) {
+	msg, err := a.Decrypt(K, C)
+	if err != nil {
+		return nil, dkg.DecryptionProof{}, err
+	}
+
+	return msg, a.proof, nil
+}
+
+func (a *fakeActor) Ping(ctx context.Context, addr mino.Address) error {
+	if addr.Equal(a.unreachable) {
+		return xerrors.Errorf("unreachable: %v", fake.GetError())
+	}
+
+	return nil
+}
+
+func (a *fakeActor) GetPublicKey() (kyber.Point, error) {
+	return suite.Point(), a.err
+}
+
+func (a *fakeActor) Export(w io.Writer) error {
+	if a.shareErr != nil {
+		return a.shareErr
+	}
+
+	_, err := w.Write([]byte("share"))
+	return err
+}
+
+func (a *fakeActor) Import(r io.Reader) error {
+	if a.shareErr != nil {
+		return a.shareErr
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	a.messages = map[string][]byte{"share": data}
+
+	return nil
+}
+
+func (a *fakeActor) GetThreshold() int {
+	return a.threshold
+}
+
+func (a *fakeActor) GetParticipants() []mino.Address {
+	return a.participants
+}
+
+// makeDecryptionProof creates a distributed key of three shares with a
+// threshold of two, encrypts the message and returns the proof of the
+// decryption by every share-holder.
+func makeDecryptionProof(t *testing.T, message []byte) (kyber.Point, ciphertext,
+	dkg.DecryptionProof) {
+
+	priPoly := share.NewPriPoly(suite, 2, nil, suite.RandomStream())
+	_, commits := priPoly.Commit(nil).Info()
+
+	pubkey := commits[0]
+
+	M := suite.Point().Embed(message, suite.RandomStream())
+	k := suite.Scalar().Pick(suite.RandomStream())
+	K := suite.Point().Mul(k, nil)
+	S := suite.Point().Mul(k, pubkey)
+	C := S.Add(S, M)
+
+	proof := dkg.DecryptionProof{Commits: commits}
+
+	for _, priShare := range priPoly.Shares(3) {
+		p, _, xK, err := dleq.NewDLEQProof(suite, suite.Point().Base(), K, priShare.V)
+		require.NoError(t, err)
+
+		proof.Partials = append(proof.Partials, dkg.PartialDecryption{
+			Index: priShare.I,
+			V:     suite.Point().Sub(C, xK),
+			Proof: p,
+		})
+	}
+
+	ct := ciphertext{
+		K: hex.EncodeToString(mustMarshal(t, K)),
+		C: hex.EncodeToString(mustMarshal(t, C)),
+	}
+
+	return pubkey, ct, proof
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+type badPoint struct {
+	kyber.Point
+}
+
+func (badPoint) MarshalBinary() ([]byte, error) {
+	return nil, fake.GetError()
+}
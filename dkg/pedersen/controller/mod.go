@@ -1,14 +1,26 @@
 package controller
 
 import (
+	"os"
+	"path/filepath"
+
 	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/cli"
 	"go.dedis.ch/dela/cli/node"
 	"go.dedis.ch/dela/dkg/pedersen"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/kyber/v3/suites"
 	"golang.org/x/xerrors"
 )
 
+// shareFile is the name of the file in the configuration folder that is loaded
+// on start, if it exists.
+const shareFile = "dkg.share"
+
+// defaultSuite is the name of the Kyber suite of the DKG when it is not
+// specified.
+const defaultSuite = "Ed25519"
+
 // NewMinimal returns a new minimal initializer
 func NewMinimal() node.Initializer {
 	return minimal{}
@@ -20,10 +32,230 @@ func NewMinimal() node.Initializer {
 // - implements node.Initializer
 type minimal struct{}
 
-// Build implements node.Initializer. In this case we don't need any command.
-func (m minimal) SetCommands(_ node.Builder) {}
+// SetCommands implements node.Initializer. It sets the commands to control the
+// DKG.
+func (m minimal) SetCommands(builder node.Builder) {
+	builder.SetStartFlags(
+		cli.StringFlag{
+			Name:  "suite",
+			Usage: "name of the Kyber suite of the DKG, as known by kyber/suites",
+			Value: defaultSuite,
+		},
+	)
+
+	cmd := builder.SetCommand("dkg")
+	cmd.SetDescription("interact with the DKG service")
+
+	sub := cmd.SetSubCommand("listen")
+	sub.SetDescription("starts the RPC to participate to a DKG")
+	sub.SetAction(builder.MakeAction(listenAction{}))
+
+	sub = cmd.SetSubCommand("export")
+	sub.SetDescription("export the node information of the DKG")
+	sub.SetAction(builder.MakeAction(exportAction{}))
+
+	sub = cmd.SetSubCommand("setup")
+	sub.SetDescription("creates the distributed key with the given members")
+	sub.SetFlags(
+		cli.StringSliceFlag{
+			Name:     "member",
+			Required: true,
+			Usage:    "one or several members of the DKG, or their name in the members file",
+		},
+		cli.StringFlag{
+			Name:  "members-file",
+			Usage: "path to a JSON file mapping the names of the members to their $ADDR:$PK",
+		},
+		cli.IntFlag{
+			Name:  "threshold",
+			Usage: "number of members required to decrypt, defaults to a 2/3 majority",
+		},
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "maximum amount of time to setup",
+			Value: defaultSetupTimeout,
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "only checks that the members can be decoded and reached",
+		},
+	)
+	sub.SetAction(builder.MakeAction(setupAction{}))
+
+	sub = cmd.SetSubCommand("reshare")
+	sub.SetDescription("distributes new shares of the distributed key to the " +
+		"given members")
+	sub.SetFlags(
+		cli.StringSliceFlag{
+			Name:     "member",
+			Required: true,
+			Usage:    "one or several members of the DKG after the resharing, or their name in the members file",
+		},
+		cli.StringFlag{
+			Name:  "members-file",
+			Usage: "path to a JSON file mapping the names of the members to their $ADDR:$PK",
+		},
+		cli.IntFlag{
+			Name:  "threshold",
+			Usage: "number of members required to decrypt, defaults to a 2/3 majority",
+		},
+	)
+	sub.SetAction(builder.MakeAction(reshareAction{}))
+
+	sub = cmd.SetSubCommand("export-share")
+	sub.SetDescription("writes the share of the node to a file, which is " +
+		"loaded on start when written to $config/" + shareFile)
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "output",
+			Required: true,
+			Usage:    "path to the file, which must not exist",
+		},
+	)
+	sub.SetAction(builder.MakeAction(exportShareAction{}))
+
+	sub = cmd.SetSubCommand("import-share")
+	sub.SetDescription("restores the share of the node from a file")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "input",
+			Required: true,
+			Usage:    "path to the file written by export-share",
+		},
+	)
+	sub.SetAction(builder.MakeAction(importShareAction{}))
+
+	sub = cmd.SetSubCommand("status")
+	sub.SetDescription("displays the state of the DKG")
+	sub.SetAction(builder.MakeAction(statusAction{}))
+
+	sub = cmd.SetSubCommand("info")
+	sub.SetDescription("displays the members and the threshold of the DKG")
+	sub.SetAction(builder.MakeAction(infoAction{}))
+
+	sub = cmd.SetSubCommand("getPublicKey")
+	sub.SetDescription("prints the distributed public key")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "output format of the key: hex, base64 or json",
+		},
+	)
+	sub.SetAction(builder.MakeAction(getPublicKeyAction{}))
+
+	sub = cmd.SetSubCommand("encrypt")
+	sub.SetDescription("encrypts a message with the distributed key")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:  "plaintext",
+			Usage: "the hex-encoded message to encrypt",
+		},
+		cli.StringFlag{
+			Name:  "inputFile",
+			Usage: "path to a file to encrypt instead of the plaintext",
+		},
+		cli.StringFlag{
+			Name:  "outputFile",
+			Usage: "path to the ciphertext file written when the inputFile is set",
+		},
+	)
+	sub.SetAction(builder.MakeAction(encryptAction{}))
+
+	sub = cmd.SetSubCommand("encryptBatch")
+	sub.SetDescription("encrypts a file of messages with the distributed key")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "input",
+			Required: true,
+			Usage:    "path to the file with one hex-encoded message per line",
+		},
+		cli.StringFlag{
+			Name:     "output",
+			Required: true,
+			Usage:    "path to the JSON file of the ciphertexts",
+		},
+	)
+	sub.SetAction(builder.MakeAction(encryptBatchAction{}))
+
+	sub = cmd.SetSubCommand("decrypt")
+	sub.SetDescription("decrypts a ciphertext with the members of the DKG")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:  "ciphertext",
+			Usage: "the ciphertext as $K_HEX:$C_HEX",
+		},
+		cli.StringFlag{
+			Name:  "proofFile",
+			Usage: "path to the file where the proofs of the decryption are written",
+		},
+		cli.StringFlag{
+			Name:  "inputFile",
+			Usage: "path to a ciphertext file to decrypt instead of the ciphertext",
+		},
+		cli.StringFlag{
+			Name:  "outputFile",
+			Usage: "path to the plaintext file written when the inputFile is set",
+		},
+	)
+	sub.SetAction(builder.MakeAction(decryptAction{}))
+
+	sub = cmd.SetSubCommand("decryptBatch")
+	sub.SetDescription("decrypts a file of ciphertexts with the members of the DKG")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "input",
+			Required: true,
+			Usage:    "path to the JSON file of the ciphertexts written by encryptBatch",
+		},
+		cli.StringFlag{
+			Name:     "output",
+			Required: true,
+			Usage:    "path to the JSON file of the plaintexts",
+		},
+	)
+	sub.SetAction(builder.MakeAction(decryptBatchAction{}))
+
+	sub = cmd.SetSubCommand("verifyDecrypt")
+	sub.SetDescription("verifies the decryption of a ciphertext with its proofs")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "ciphertext",
+			Required: true,
+			Usage:    "the ciphertext as $K_HEX:$C_HEX",
+		},
+		cli.StringFlag{
+			Name:     "plaintext",
+			Required: true,
+			Usage:    "the hex-encoded plaintext claimed for the ciphertext",
+		},
+		cli.StringFlag{
+			Name:     "proofFile",
+			Required: true,
+			Usage:    "path to the file of the proofs written by decrypt",
+		},
+		cli.StringFlag{
+			Name:     "pubkey",
+			Required: true,
+			Usage:    "the hex-encoded distributed public key",
+		},
+	)
+	sub.SetAction(builder.MakeAction(verifyDecryptAction{}))
+
+	sub = cmd.SetSubCommand("bench-decrypt")
+	sub.SetDescription("measures the throughput of the decryption")
+	sub.SetFlags(
+		cli.IntFlag{
+			Name:  "count",
+			Usage: "number of ciphertexts to decrypt",
+			Value: 10,
+		},
+	)
+	sub.SetAction(builder.MakeAction(benchDecryptAction{}))
+}
 
-// OnStart implements node.Initializer. It creates and registers a pedersen DKG.
+// OnStart implements node.Initializer. It creates and registers a pedersen DKG
+// with the suite given by the flag. If a share has been exported to the
+// configuration folder, the DKG starts to listen and the share is imported.
 func (m minimal) OnStart(ctx cli.Flags, inj node.Injector) error {
 	var no mino.Mino
 	err := inj.Resolve(&no)
@@ -31,7 +263,17 @@ func (m minimal) OnStart(ctx cli.Flags, inj node.Injector) error {
 		return xerrors.Errorf("failed to resolve mino: %v", err)
 	}
 
-	dkg, pubkey := pedersen.NewPedersen(no)
+	name := ctx.String("suite")
+	if name == "" {
+		name = defaultSuite
+	}
+
+	suite, err := suites.Find(name)
+	if err != nil {
+		return xerrors.Errorf("invalid suite '%s': %v", name, err)
+	}
+
+	dkg, pubkey := pedersen.NewPedersen(no, pedersen.WithSuite(suite))
 
 	inj.Inject(dkg)
 
@@ -41,9 +283,35 @@ func (m minimal) OnStart(ctx cli.Flags, inj node.Injector) error {
 	}
 
 	dela.Logger.Info().
+		Str(dela.SubsystemKey, "dkg").
+		Str("suite", suite.String()).
 		Hex("public key", pubkeyBuf).
 		Msg("perdersen public key")
 
+	path := filepath.Join(ctx.Path("config"), shareFile)
+
+	_, err = os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	actor, err := dkg.Listen()
+	if err != nil {
+		return xerrors.Errorf("failed to listen: %v", err)
+	}
+
+	err = importShare(actor.(shareStore), path)
+	if err != nil {
+		return xerrors.Errorf("failed to import share: %v", err)
+	}
+
+	inj.Inject(actor)
+
+	dela.Logger.Info().
+		Str(dela.SubsystemKey, "dkg").
+		Str("path", path).
+		Msg("share loaded, the DKG is listening")
+
 	return nil
 }
 
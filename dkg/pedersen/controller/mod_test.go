@@ -1,36 +1,124 @@
 package controller
 
 import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/dkg"
 	"go.dedis.ch/dela/dkg/pedersen"
 	"go.dedis.ch/dela/internal/testing/fake"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/suites"
 	"golang.org/x/xerrors"
 )
 
 func TestMinimal_SetCommands(t *testing.T) {
 	minimal := NewMinimal()
 
-	minimal.SetCommands(nil)
+	b := node.NewBuilder()
+	minimal.SetCommands(b)
 }
 
 func TestMinimal_OnStart(t *testing.T) {
 	minimal := NewMinimal()
 
 	inj := newInjector(fake.Mino{})
-	err := minimal.OnStart(nil, inj)
+	err := minimal.OnStart(make(node.FlagSet), inj)
 	require.NoError(t, err)
 
 	require.Len(t, inj.(*fakeInjector).history, 1)
 	require.IsType(t, &pedersen.Pedersen{}, inj.(*fakeInjector).history[0])
 
-	err = minimal.OnStart(nil, newBadInjector())
+	p := inj.(*fakeInjector).history[0].(*pedersen.Pedersen)
+	require.Equal(t, suites.MustFind(defaultSuite), p.GetSuite())
+
+	err = minimal.OnStart(make(node.FlagSet), newBadInjector())
 	require.EqualError(t, err, fake.Err("failed to resolve mino"))
 }
 
+func TestMinimal_Suite_OnStart(t *testing.T) {
+	minimal := NewMinimal()
+
+	flags := make(node.FlagSet)
+	flags["suite"] = "P256"
+
+	inj := newInjector(fake.Mino{})
+	err := minimal.OnStart(flags, inj)
+	require.NoError(t, err)
+
+	p := inj.(*fakeInjector).history[0].(*pedersen.Pedersen)
+	require.Equal(t, suites.MustFind("P256"), p.GetSuite())
+
+	flags["suite"] = "unknown"
+	err = minimal.OnStart(flags, inj)
+	require.EqualError(t, err, "invalid suite 'unknown': unknown suite")
+}
+
+func TestMinimal_Share_OnStart(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, shareFile), []byte("{"), 0600)
+	require.NoError(t, err)
+
+	flags := make(node.FlagSet)
+	flags["config"] = dir
+
+	minimal := NewMinimal()
+
+	err = minimal.OnStart(flags, newInjector(fake.Mino{}))
+	require.EqualError(t, err,
+		"failed to import share: failed to decode: unexpected EOF")
+}
+
+func TestMinimal_ShareListen_OnStart(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	writeShare(t, filepath.Join(dir, shareFile))
+
+	flags := make(node.FlagSet)
+	flags["config"] = dir
+
+	inj := node.NewInjector()
+	inj.Inject(fake.Mino{})
+
+	err = NewMinimal().OnStart(flags, inj)
+	require.NoError(t, err)
+
+	var actor dkg.Actor
+	require.NoError(t, inj.Resolve(&actor))
+
+	// The listen command keeps the actor that holds the imported share.
+	buffer := new(bytes.Buffer)
+
+	ctx := node.Context{
+		Injector: inj,
+		Flags:    flags,
+		Out:      buffer,
+	}
+
+	err = listenAction{}.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "DKG is already listening", buffer.String())
+
+	var same dkg.Actor
+	require.NoError(t, inj.Resolve(&same))
+	require.True(t, actor == same)
+}
+
 func TestMinimal_OnStop(t *testing.T) {
 	minimal := NewMinimal()
 
@@ -41,6 +129,43 @@ func TestMinimal_OnStop(t *testing.T) {
 // -----------------------------------------------------------------------------
 // Utility functions
 
+// writeShare writes a valid share of a DKG with a single participant.
+func writeShare(t *testing.T, path string) {
+	suite := suites.MustFind(defaultSuite)
+
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	pubPoly := share.NewPriPoly(suite, 1, secret, suite.RandomStream()).Commit(nil)
+
+	_, commits := pubPoly.Info()
+
+	marshal := func(point kyber.Point) []byte {
+		buffer, err := point.MarshalBinary()
+		require.NoError(t, err)
+
+		return buffer
+	}
+
+	secretBuf, err := secret.MarshalBinary()
+	require.NoError(t, err)
+
+	addr, err := fake.NewAddress(0).MarshalText()
+	require.NoError(t, err)
+
+	file := map[string]interface{}{
+		"Index":        0,
+		"Share":        secretBuf,
+		"Commits":      [][]byte{marshal(commits[0])},
+		"Threshold":    1,
+		"Participants": [][]byte{addr},
+		"PublicKeys":   [][]byte{marshal(suite.Point().Pick(suite.RandomStream()))},
+	}
+
+	data, err := json.Marshal(file)
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+}
+
 func newInjector(mino mino.Mino) node.Injector {
 	return &fakeInjector{
 		mino: mino,
@@ -0,0 +1,305 @@
+// This file contains the implementation of the ciphertext files, which store
+// the K/C pairs of the chunks of a message so that they can be read and
+// written one at a time.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"go.dedis.ch/dela/dkg"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/suites"
+	"golang.org/x/xerrors"
+)
+
+// streamMagic is the header of a ciphertext file that uses the length-delimited
+// framing. A file without it is read as a legacy ciphertext.
+var streamMagic = []byte("DKGCT\x01")
+
+// streamWindow is the maximum number of bytes of the plaintext read at once
+// when a file is encrypted.
+const streamWindow = 4096
+
+// maxFrameSize is the maximum size of a frame, which is far above the size of a
+// point of any suite, so that a corrupted length does not allocate a large
+// buffer.
+const maxFrameSize = 1024
+
+// ciphertextWriter writes the K/C pairs of a ciphertext file. Each point is
+// prefixed by its length as an unsigned varint.
+type ciphertextWriter struct {
+	w   *bufio.Writer
+	num int
+}
+
+// newCiphertextWriter returns a writer of K/C pairs after writing the header of
+// the file.
+func newCiphertextWriter(w io.Writer) (*ciphertextWriter, error) {
+	writer := bufio.NewWriter(w)
+
+	_, err := writer.Write(streamMagic)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to write header: %v", err)
+	}
+
+	return &ciphertextWriter{w: writer}, nil
+}
+
+// Write writes the pair of points.
+func (cw *ciphertextWriter) Write(K, C kyber.Point) error {
+	for _, point := range []kyber.Point{K, C} {
+		buf, err := point.MarshalBinary()
+		if err != nil {
+			return xerrors.Errorf("failed to marshal point: %v", err)
+		}
+
+		err = cw.writeFrame(buf)
+		if err != nil {
+			return xerrors.Errorf("failed to write frame: %v", err)
+		}
+	}
+
+	cw.num++
+
+	return nil
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (cw *ciphertextWriter) Flush() error {
+	return cw.w.Flush()
+}
+
+func (cw *ciphertextWriter) writeFrame(buf []byte) error {
+	prefix := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(prefix, uint64(len(buf)))
+
+	_, err := cw.w.Write(prefix[:n])
+	if err != nil {
+		return err
+	}
+
+	_, err = cw.w.Write(buf)
+	return err
+}
+
+// ciphertextReader reads the K/C pairs of a ciphertext file one at a time.
+type ciphertextReader interface {
+	// Next returns the next pair of points, or io.EOF when the file has been
+	// entirely read.
+	Next() (kyber.Point, kyber.Point, error)
+}
+
+// newCiphertextReader returns a reader of the K/C pairs. A file without the
+// header of the framing is read entirely as a legacy ciphertext, either as
+// "$K_HEX:$C_HEX" or as a JSON array of the ciphertexts of each chunk.
+func newCiphertextReader(r io.Reader, suite suites.Suite) (ciphertextReader, error) {
+	reader := bufio.NewReader(r)
+
+	header, err := reader.Peek(len(streamMagic))
+	if err == nil && bytes.Equal(header, streamMagic) {
+		reader.Discard(len(streamMagic))
+
+		return &frameReader{r: reader, suite: suite}, nil
+	}
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read: %v", err)
+	}
+
+	cts, err := readCiphertexts(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, xerrors.Errorf("legacy ciphertext: %v", err)
+	}
+
+	return &legacyReader{cts: cts, suite: suite}, nil
+}
+
+// frameReader reads the pairs with the length-delimited framing.
+//
+// - implements controller.ciphertextReader
+type frameReader struct {
+	r     *bufio.Reader
+	suite suites.Suite
+	num   int
+}
+
+// Next implements controller.ciphertextReader. It reads and decodes the frames
+// of the next pair.
+func (fr *frameReader) Next() (kyber.Point, kyber.Point, error) {
+	_, err := fr.r.Peek(1)
+	if err == io.EOF {
+		return nil, nil, io.EOF
+	}
+
+	K, err := fr.readPoint()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("chunk %d: K: %v", fr.num, err)
+	}
+
+	C, err := fr.readPoint()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("chunk %d: C: %v", fr.num, err)
+	}
+
+	fr.num++
+
+	return K, C, nil
+}
+
+func (fr *frameReader) readPoint() (kyber.Point, error) {
+	size, err := binary.ReadUvarint(fr.r)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read length: %v", err)
+	}
+
+	if size > maxFrameSize {
+		return nil, xerrors.Errorf("frame of %d byte(s) is too large", size)
+	}
+
+	buf := make([]byte, size)
+
+	_, err = io.ReadFull(fr.r, buf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read frame: %v", err)
+	}
+
+	point := fr.suite.Point()
+
+	err = point.UnmarshalBinary(buf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal point: %v", err)
+	}
+
+	return point, nil
+}
+
+// legacyReader reads the pairs of a legacy ciphertext.
+//
+// - implements controller.ciphertextReader
+type legacyReader struct {
+	cts   []ciphertext
+	suite suites.Suite
+	num   int
+}
+
+// Next implements controller.ciphertextReader. It decodes the next ciphertext
+// of the list.
+func (lr *legacyReader) Next() (kyber.Point, kyber.Point, error) {
+	if lr.num >= len(lr.cts) {
+		return nil, nil, io.EOF
+	}
+
+	K, C, err := decodeCiphertext(lr.suite, lr.cts[lr.num])
+	if err != nil {
+		return nil, nil, xerrors.Errorf("chunk %d: %v", lr.num, err)
+	}
+
+	lr.num++
+
+	return K, C, nil
+}
+
+// encryptStream encrypts the reader by windows of at most streamWindow bytes
+// and writes the pairs of each chunk to the writer. It returns the number of
+// chunks.
+func encryptStream(actor dkg.Actor, r io.Reader, w io.Writer) (int, error) {
+	writer, err := newCiphertextWriter(w)
+	if err != nil {
+		return 0, err
+	}
+
+	reader := bufio.NewReader(r)
+	window := make([]byte, streamWindow)
+
+	for {
+		n, err := io.ReadFull(reader, window)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return writer.num, xerrors.Errorf("failed to read input: %v", err)
+		}
+
+		msg := window[:n]
+
+		for len(msg) > 0 {
+			K, C, remainder, err := actor.Encrypt(msg)
+			if err != nil {
+				return writer.num, xerrors.Errorf("chunk %d: encryption failed: %v",
+					writer.num, err)
+			}
+
+			if len(remainder) >= len(msg) {
+				return writer.num, xerrors.Errorf("chunk %d: no data embedded",
+					writer.num)
+			}
+
+			err = writer.Write(K, C)
+			if err != nil {
+				return writer.num, xerrors.Errorf("chunk %d: %v", writer.num, err)
+			}
+
+			msg = remainder
+		}
+
+		if n < len(window) {
+			break
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return writer.num, xerrors.Errorf("failed to write output: %v", err)
+	}
+
+	return writer.num, nil
+}
+
+// decryptStream decrypts the pairs of the reader one at a time and writes the
+// chunks of the message to the writer. It returns the number of chunks.
+func decryptStream(actor dkg.Actor, suite suites.Suite, r io.Reader, w io.Writer) (int, error) {
+	reader, err := newCiphertextReader(r, suite)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to read input: %v", err)
+	}
+
+	writer := bufio.NewWriter(w)
+	num := 0
+
+	for {
+		K, C, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return num, xerrors.Errorf("failed to read input: %v", err)
+		}
+
+		chunk, err := actor.Decrypt(K, C)
+		if err != nil {
+			return num, xerrors.Errorf("chunk %d: %v", num, err)
+		}
+
+		_, err = writer.Write(chunk)
+		if err != nil {
+			return num, xerrors.Errorf("failed to write output: %v", err)
+		}
+
+		num++
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return num, xerrors.Errorf("failed to write output: %v", err)
+	}
+
+	return num, nil
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+)
+
+func TestEncryptStream_1MB(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dela-dkg")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	msg := make([]byte, 1<<20)
+	_, err = rand.Read(msg)
+	require.NoError(t, err)
+
+	actor := &fakeActor{chunk: 1000}
+
+	file, err := os.Create(filepath.Join(dir, "ciphertext"))
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	num, err := encryptStream(actor, bytes.NewReader(msg), file)
+	require.NoError(t, err)
+	// Each window of 4096 bytes is split in 5 chunks.
+	require.Equal(t, 256*5, num)
+
+	_, err = file.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+
+	// The decoder only reads the buffered part of the file to get the first
+	// pair, whatever the size of the file.
+	counter := &countingReader{r: file}
+
+	reader, err := newCiphertextReader(counter, suite)
+	require.NoError(t, err)
+
+	_, _, err = reader.Next()
+	require.NoError(t, err)
+	require.LessOrEqual(t, counter.n, 4096)
+
+	_, err = file.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+
+	num, err = decryptStream(actor, suite, file, out)
+	require.NoError(t, err)
+	require.Equal(t, 256*5, num)
+	require.Equal(t, msg, out.Bytes())
+}
+
+func TestEncryptStream_Failures(t *testing.T) {
+	_, err := encryptStream(&fakeActor{}, bytes.NewReader([]byte{1}), badWriter{})
+	require.EqualError(t, err, fake.Err("failed to write output"))
+
+	_, err = encryptStream(&fakeActor{}, badReader{}, ioutil.Discard)
+	require.EqualError(t, err, fake.Err("failed to read input"))
+
+	actor := &fakeActor{encErr: fake.GetError()}
+	_, err = encryptStream(actor, bytes.NewReader([]byte{1}), ioutil.Discard)
+	require.EqualError(t, err, fake.Err("chunk 0: encryption failed"))
+
+	actor = &fakeActor{remainder: []byte{1}}
+	_, err = encryptStream(actor, bytes.NewReader([]byte{1}), ioutil.Discard)
+	require.EqualError(t, err, "chunk 0: no data embedded")
+
+	num, err := encryptStream(&fakeActor{}, bytes.NewReader(nil), ioutil.Discard)
+	require.NoError(t, err)
+	require.Equal(t, 0, num)
+}
+
+func TestDecryptStream_Legacy(t *testing.T) {
+	actor := &fakeActor{chunk: 1}
+
+	cts, err := encryptChunks(actor, []byte{0xaa, 0xbb})
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+
+	num, err := decryptStream(actor, suite, bytes.NewBufferString(cts[0].K+separator+cts[0].C+"\n"), out)
+	require.NoError(t, err)
+	require.Equal(t, 1, num)
+	require.Equal(t, []byte{0xaa}, out.Bytes())
+
+	data, err := json.Marshal(cts)
+	require.NoError(t, err)
+
+	out.Reset()
+
+	num, err = decryptStream(actor, suite, bytes.NewReader(data), out)
+	require.NoError(t, err)
+	require.Equal(t, 2, num)
+	require.Equal(t, []byte{0xaa, 0xbb}, out.Bytes())
+
+	_, err = decryptStream(actor, suite, bytes.NewBufferString("aa"), out)
+	require.EqualError(t, err, "failed to read input: legacy ciphertext: "+
+		"invalid ciphertext, expected $K_HEX:$C_HEX")
+
+	_, err = decryptStream(actor, suite, bytes.NewBufferString("zz:zz"), out)
+	require.EqualError(t, err, "failed to read input: chunk 0: K: hex: "+
+		"encoding/hex: invalid byte: U+007A 'z'")
+
+	_, err = decryptStream(actor, suite, badReader{}, out)
+	require.EqualError(t, err, fake.Err("failed to read input: failed to read"))
+}
+
+func TestDecryptStream_Failures(t *testing.T) {
+	actor := &fakeActor{}
+
+	buffer := new(bytes.Buffer)
+
+	_, err := encryptStream(actor, bytes.NewReader([]byte{0xaa}), buffer)
+	require.NoError(t, err)
+
+	data := buffer.Bytes()
+
+	_, err = decryptStream(actor, suite, bytes.NewReader(data), badWriter{})
+	require.EqualError(t, err, fake.Err("failed to write output"))
+
+	_, err = decryptStream(&fakeActor{decErr: fake.GetError()}, suite,
+		bytes.NewReader(data), ioutil.Discard)
+	require.EqualError(t, err, fake.Err("chunk 0"))
+
+	_, err = decryptStream(actor, suite, bytes.NewReader(data[:len(data)-1]), ioutil.Discard)
+	require.EqualError(t, err, "failed to read input: chunk 0: C: "+
+		"failed to read frame: unexpected EOF")
+
+	_, err = decryptStream(actor, suite, bytes.NewReader(data[:len(streamMagic)+1]), ioutil.Discard)
+	require.EqualError(t, err, "failed to read input: chunk 0: K: "+
+		"failed to read frame: EOF")
+
+	large := append([]byte{}, streamMagic...)
+	large = append(large, make([]byte, binary.MaxVarintLen64)...)
+	large = large[:len(streamMagic)+binary.PutUvarint(large[len(streamMagic):], maxFrameSize+1)]
+
+	_, err = decryptStream(actor, suite, bytes.NewReader(large), ioutil.Discard)
+	require.EqualError(t, err, "failed to read input: chunk 0: K: "+
+		"frame of 1025 byte(s) is too large")
+
+	bad := append([]byte{}, streamMagic...)
+	bad = append(bad, 1, 0)
+
+	_, err = decryptStream(actor, suite, bytes.NewReader(bad), ioutil.Discard)
+	require.Error(t, err)
+	require.Regexp(t, "^failed to read input: chunk 0: K: failed to unmarshal point: ", err.Error())
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+
+	return n, err
+}
+
+type badReader struct{}
+
+func (badReader) Read([]byte) (int, error) {
+	return 0, fake.GetError()
+}
+
+type badWriter struct{}
+
+func (badWriter) Write([]byte) (int, error) {
+	return 0, fake.GetError()
+}
@@ -5,13 +5,15 @@ import (
 	"sync"
 	"time"
 
-	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/dkg/pedersen/types"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
 	"go.dedis.ch/kyber/v3/share"
 	pedersen "go.dedis.ch/kyber/v3/share/dkg/pedersen"
 	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+	"go.dedis.ch/kyber/v3/suites"
 	"golang.org/x/xerrors"
 )
 
@@ -25,6 +27,9 @@ type state struct {
 	sync.Mutex
 	distrKey     kyber.Point
 	participants []mino.Address
+	pubkeys      []kyber.Point
+	commits      []kyber.Point
+	threshold    int
 }
 
 func (s *state) Done() bool {
@@ -57,6 +62,64 @@ func (s *state) SetParticipants(addrs []mino.Address) {
 	s.Unlock()
 }
 
+func (s *state) GetPublicKeys() []kyber.Point {
+	s.Lock()
+	defer s.Unlock()
+	return s.pubkeys
+}
+
+func (s *state) SetPublicKeys(pubkeys []kyber.Point) {
+	s.Lock()
+	s.pubkeys = pubkeys
+	s.Unlock()
+}
+
+func (s *state) GetCommits() []kyber.Point {
+	s.Lock()
+	defer s.Unlock()
+	return s.commits
+}
+
+func (s *state) SetCommits(commits []kyber.Point) {
+	s.Lock()
+	s.commits = commits
+	s.Unlock()
+}
+
+// clearPartial removes the parameters of a setup that has not completed.
+func (s *state) clearPartial() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.distrKey != nil {
+		return
+	}
+
+	s.participants = nil
+	s.pubkeys = nil
+	s.commits = nil
+	s.threshold = 0
+}
+
+// GetThreshold returns the number of share-holders required to decrypt. It
+// defaults to all the participants when unknown.
+func (s *state) GetThreshold() int {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.threshold <= 0 {
+		return len(s.participants)
+	}
+
+	return s.threshold
+}
+
+func (s *state) SetThreshold(threshold int) {
+	s.Lock()
+	s.threshold = threshold
+	s.Unlock()
+}
+
 // Handler represents the RPC executed on each node
 //
 // - implements mino.Handler
@@ -67,15 +130,18 @@ type Handler struct {
 	privKey   kyber.Scalar
 	me        mino.Address
 	privShare *share.PriShare
+	distShare *pedersen.DistKeyShare
 	startRes  *state
+	suite     suites.Suite
 }
 
 // NewHandler creates a new handler
-func NewHandler(privKey kyber.Scalar, me mino.Address) *Handler {
+func NewHandler(privKey kyber.Scalar, me mino.Address, suite suites.Suite) *Handler {
 	return &Handler{
 		privKey:  privKey,
 		me:       me,
 		startRes: &state{},
+		suite:    suite,
 	}
 }
 
@@ -108,6 +174,12 @@ mainSwitch:
 			return xerrors.Errorf("failed to start: %v", err)
 		}
 
+	case types.StartResharing:
+		err := h.reshare(msg, deals, responses, from, out, in)
+		if err != nil {
+			return xerrors.Errorf("failed to reshare: %v", err)
+		}
+
 	case types.Deal:
 		// This is a special case where a DKG started, some nodes received the
 		// start signal and started sending their deals but we have not yet
@@ -141,19 +213,31 @@ mainSwitch:
 
 		// TODO: check if started before
 		h.RLock()
-		S := suite.Point().Mul(h.privShare.V, msg.K)
+		privShare := h.privShare
 		h.RUnlock()
 
-		partial := suite.Point().Sub(msg.C, S)
+		// A node that left the group during a resharing has no share anymore.
+		if privShare == nil {
+			return xerrors.New("node is not a share-holder")
+		}
 
-		h.RLock()
-		decryptReply := types.NewDecryptReply(
+		// The proof shows that the same private share is used for the public
+		// share and for the partial decryption S = xK.
+		proof, _, S, err := dleq.NewDLEQProof(h.suite, h.suite.Point().Base(),
+			msg.K, privShare.V)
+		if err != nil {
+			return xerrors.Errorf("failed to create proof: %v", err)
+		}
+
+		partial := h.suite.Point().Sub(msg.C, S)
+
+		decryptReply := types.NewVerifiableDecryptReply(
 			// TODO: check if using the private index is the same as the public
 			// index.
-			int64(h.privShare.I),
+			int64(privShare.I),
 			partial,
+			proof,
 		)
-		h.RUnlock()
 
 		errs := out.Send(decryptReply, from)
 		err = <-errs
@@ -162,6 +246,41 @@ mainSwitch:
 				"reply: %v", err)
 		}
 
+	case types.DecryptBatchRequest:
+		if !h.startRes.Done() {
+			return xerrors.Errorf("you must first initialize DKG. Did you " +
+				"call setup() first?")
+		}
+
+		ks := msg.GetKs()
+		cs := msg.GetCs()
+
+		if len(ks) != len(cs) {
+			return xerrors.Errorf("mismatch number of K %d != %d", len(ks), len(cs))
+		}
+
+		h.RLock()
+		privShare := h.privShare
+		h.RUnlock()
+
+		// A node that left the group during a resharing has no share anymore.
+		if privShare == nil {
+			return xerrors.New("node is not a share-holder")
+		}
+
+		partials := make([]kyber.Point, len(ks))
+		for i := range ks {
+			S := h.suite.Point().Mul(privShare.V, ks[i])
+			partials[i] = h.suite.Point().Sub(cs[i], S)
+		}
+
+		errs := out.Send(types.NewDecryptBatchReply(int64(privShare.I), partials), from)
+		err = <-errs
+		if err != nil {
+			return xerrors.Errorf("got an error while sending the decrypt "+
+				"batch reply: %v", err)
+		}
+
 	default:
 		return xerrors.Errorf("expected Start message, decrypt request or "+
 			"Deal as first message, got: %T", msg)
@@ -170,6 +289,24 @@ mainSwitch:
 	return nil
 }
 
+// Process implements mino.Handler. It returns the distributed key to a peer
+// requesting it, or an error if the DKG is not set up. A ping is answered
+// regardless of the setup.
+func (h *Handler) Process(req mino.Request) (serde.Message, error) {
+	switch req.Message.(type) {
+	case types.PublicKeyRequest:
+		if !h.startRes.Done() {
+			return nil, xerrors.New("DKG has not been initialized")
+		}
+
+		return types.NewPublicKeyReply(h.startRes.GetDistKey()), nil
+	case types.PingRequest:
+		return types.NewPingReply(), nil
+	default:
+		return nil, xerrors.Errorf("unsupported message of type '%T'", req.Message)
+	}
+}
+
 // start is called when the node has received its start message. Note that we
 // might have already received some deals from other nodes in the meantime. The
 // function handles the DKG creation protocol.
@@ -183,7 +320,7 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 	}
 
 	// 1. Create the DKG
-	d, err := pedersen.NewDistKeyGenerator(suite, h.privKey, start.GetPublicKeys(), start.GetThreshold())
+	d, err := pedersen.NewDistKeyGenerator(h.suite, h.privKey, start.GetPublicKeys(), start.GetThreshold())
 	if err != nil {
 		return xerrors.Errorf("failed to create new DKG: %v", err)
 	}
@@ -195,35 +332,7 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 		return xerrors.Errorf("failed to compute the deals: %v", err)
 	}
 
-	// use a waitgroup to send all the deals asynchronously and wait
-	var wg sync.WaitGroup
-	wg.Add(len(deals))
-
-	for i, deal := range deals {
-		dealMsg := types.NewDeal(
-			deal.Index,
-			deal.Signature,
-			types.NewEncryptedDeal(
-				deal.Deal.DHKey,
-				deal.Deal.Signature,
-				deal.Deal.Nonce,
-				deal.Deal.Cipher,
-			),
-		)
-
-		errs := out.Send(dealMsg, start.GetAddresses()[i])
-		go func(errs <-chan error) {
-			err, more := <-errs
-			if more {
-				dela.Logger.Warn().Msgf("got an error while sending deal: %v", err)
-			}
-			wg.Done()
-		}(errs)
-	}
-
-	wg.Wait()
-
-	dela.Logger.Trace().Msgf("%s sent all its deals", h.me)
+	h.sendDeals(deals, start.GetAddresses(), out)
 
 	numReceivedDeals := 0
 
@@ -231,7 +340,7 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 	for _, deal := range receivedDeals {
 		err = h.handleDeal(deal, from, start.GetAddresses(), out)
 		if err != nil {
-			dela.Logger.Warn().Msgf("%s failed to handle received deal "+
+			logger.Warn().Msgf("%s failed to handle received deal "+
 				"from %s: %v", h.me, from, err)
 		}
 		numReceivedDeals++
@@ -252,7 +361,7 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 			// 4. Process the Deal and Send the response to all the other nodes
 			err = h.handleDeal(msg, from, start.GetAddresses(), out)
 			if err != nil {
-				dela.Logger.Warn().Msgf("%s failed to handle received deal "+
+				logger.Warn().Msgf("%s failed to handle received deal "+
 					"from %s: %v", h.me, from, err)
 				return xerrors.Errorf("failed to handle deal from '%s': %v", from, err)
 			}
@@ -260,7 +369,7 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 
 		case types.Response:
 			// 5. Processing responses
-			dela.Logger.Trace().Msgf("%s received response from %s", h.me, from)
+			logger.Trace().Msgf("%s received response from %s", h.me, from)
 			response := &pedersen.Response{
 				Index: msg.GetIndex(),
 				Response: &vss.Response{
@@ -277,6 +386,8 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 		}
 	}
 
+	h.startRes.SetThreshold(start.GetThreshold())
+	h.startRes.SetPublicKeys(start.GetPublicKeys())
 	h.startRes.SetParticipants(start.GetAddresses())
 
 	err = h.certify(receivedResps, out, in, from)
@@ -287,13 +398,194 @@ func (h *Handler) start(start types.Start, receivedDeals []types.Deal,
 	return nil
 }
 
+// reshare is called when the node has received the resharing message. The node
+// can be an old share-holder that deals its share to the new ones, a new
+// share-holder that receives a share, or both. The distributed key does not
+// change.
+func (h *Handler) reshare(start types.StartResharing, receivedDeals []types.Deal,
+	receivedResps []*pedersen.Response, from mino.Address, out mino.Sender,
+	in mino.Receiver) error {
+
+	addrsNew := start.GetAddrsNew()
+	pubkeysNew := start.GetPubkeysNew()
+
+	if len(addrsNew) != len(pubkeysNew) {
+		return xerrors.Errorf("there should be as many new players as "+
+			"pubKey: %d := %d", len(addrsNew), len(pubkeysNew))
+	}
+
+	pubkey := h.suite.Point().Mul(h.privKey, nil)
+
+	isOld := containsPoint(start.GetPubkeysOld(), pubkey)
+	isNew := containsPoint(pubkeysNew, pubkey)
+
+	config := &pedersen.Config{
+		Suite:        h.suite,
+		Longterm:     h.privKey,
+		OldNodes:     start.GetPubkeysOld(),
+		NewNodes:     pubkeysNew,
+		Threshold:    start.GetThresholdNew(),
+		OldThreshold: start.GetThresholdOld(),
+	}
+
+	if isOld {
+		h.RLock()
+		config.Share = h.distShare
+		h.RUnlock()
+
+		if config.Share == nil {
+			return xerrors.New("no share to reshare")
+		}
+	} else {
+		// Without the commitments, the DKG would be created from scratch.
+		if len(start.GetCommits()) == 0 {
+			return xerrors.New("missing the public commitments")
+		}
+
+		config.PublicCoeffs = start.GetCommits()
+	}
+
+	// 1. Create the DKG for the resharing
+	d, err := pedersen.NewDistKeyHandler(config)
+	if err != nil {
+		return xerrors.Errorf("failed to create resharing DKG: %v", err)
+	}
+	h.dkg = d
+
+	// 2. The old share-holders send their deals to the new ones
+	deals, err := d.Deals()
+	if err != nil {
+		return xerrors.Errorf("failed to compute the deals: %v", err)
+	}
+
+	h.sendDeals(deals, addrsNew, out)
+
+	if !isNew {
+		// The node is leaving the group, thus it drops its share but keeps
+		// track of the new share-holders.
+		h.Lock()
+		h.privShare = nil
+		h.distShare = nil
+		h.Unlock()
+
+		// The commitments of the new shares are only known by the new
+		// share-holders.
+		h.startRes.SetThreshold(start.GetThresholdNew())
+		h.startRes.SetPublicKeys(pubkeysNew)
+		h.startRes.SetCommits(nil)
+		h.startRes.SetParticipants(addrsNew)
+
+		err = <-out.Send(types.NewStartDone(h.startRes.GetDistKey()), from)
+		if err != nil {
+			return xerrors.Errorf("got an error while sending pub key: %v", err)
+		}
+
+		return nil
+	}
+
+	// 3. The new share-holders process the deals and send the responses to
+	// the other new share-holders
+	numReceivedDeals := 0
+
+	for _, deal := range receivedDeals {
+		err = h.handleDeal(deal, from, addrsNew, out)
+		if err != nil {
+			logger.Warn().Msgf("%s failed to handle received deal "+
+				"from %s: %v", h.me, from, err)
+		}
+		numReceivedDeals++
+	}
+
+	for numReceivedDeals < d.ExpectedDeals() {
+		from, msg, err := in.Recv(context.Background())
+		if err != nil {
+			return xerrors.Errorf("failed to receive after sending deals: %v", err)
+		}
+
+		switch msg := msg.(type) {
+
+		case types.Deal:
+			err = h.handleDeal(msg, from, addrsNew, out)
+			if err != nil {
+				return xerrors.Errorf("failed to handle deal from '%s': %v", from, err)
+			}
+			numReceivedDeals++
+
+		case types.Response:
+			logger.Trace().Msgf("%s received response from %s", h.me, from)
+			response := &pedersen.Response{
+				Index: msg.GetIndex(),
+				Response: &vss.Response{
+					SessionID: msg.GetResponse().GetSessionID(),
+					Index:     msg.GetResponse().GetIndex(),
+					Status:    msg.GetResponse().GetStatus(),
+					Signature: msg.GetResponse().GetSignature(),
+				},
+			}
+			receivedResps = append(receivedResps, response)
+
+		default:
+			return xerrors.Errorf("unexpected message: %T", msg)
+		}
+	}
+
+	h.startRes.SetThreshold(start.GetThresholdNew())
+	h.startRes.SetPublicKeys(pubkeysNew)
+	h.startRes.SetParticipants(addrsNew)
+
+	// 4. Wait for the responses and send back the public key, which is the
+	// same as before
+	err = h.certify(receivedResps, out, in, from)
+	if err != nil {
+		return xerrors.Errorf("failed to certify: %v", err)
+	}
+
+	return nil
+}
+
+// sendDeals sends the deals to the participants. The index of a deal is the
+// index of the participant in the list of addresses.
+func (h *Handler) sendDeals(deals map[int]*pedersen.Deal, addrs []mino.Address,
+	out mino.Sender) {
+
+	// use a waitgroup to send all the deals asynchronously and wait
+	var wg sync.WaitGroup
+	wg.Add(len(deals))
+
+	for i, deal := range deals {
+		dealMsg := types.NewDeal(
+			deal.Index,
+			deal.Signature,
+			types.NewEncryptedDeal(
+				deal.Deal.DHKey,
+				deal.Deal.Signature,
+				deal.Deal.Nonce,
+				deal.Deal.Cipher,
+			),
+		)
+
+		errs := out.Send(dealMsg, addrs[i])
+		go func(errs <-chan error) {
+			err, more := <-errs
+			if more {
+				logger.Warn().Msgf("got an error while sending deal: %v", err)
+			}
+			wg.Done()
+		}(errs)
+	}
+
+	wg.Wait()
+
+	logger.Trace().Msgf("%s sent all its deals", h.me)
+}
+
 func (h *Handler) certify(resps []*pedersen.Response, out mino.Sender,
 	in mino.Receiver, from mino.Address) error {
 
 	for _, response := range resps {
 		_, err := h.dkg.ProcessResponse(response)
 		if err != nil {
-			dela.Logger.Warn().Msgf("%s failed to process response: %v", h.me, err)
+			logger.Warn().Msgf("%s failed to process response: %v", h.me, err)
 		}
 	}
 
@@ -311,7 +603,7 @@ func (h *Handler) certify(resps []*pedersen.Response, out mino.Sender,
 
 		case types.Response:
 			// 5. Processing responses
-			dela.Logger.Trace().Msgf("%s received response from %s", h.me, from)
+			logger.Trace().Msgf("%s received response from %s", h.me, from)
 			response := &pedersen.Response{
 				Index: msg.GetIndex(),
 				Response: &vss.Response{
@@ -324,7 +616,7 @@ func (h *Handler) certify(resps []*pedersen.Response, out mino.Sender,
 
 			_, err = h.dkg.ProcessResponse(response)
 			if err != nil {
-				dela.Logger.Warn().Msgf("%s, failed to process response "+
+				logger.Warn().Msgf("%s, failed to process response "+
 					"from '%s': %v", h.me, from, err)
 			}
 
@@ -333,7 +625,7 @@ func (h *Handler) certify(resps []*pedersen.Response, out mino.Sender,
 		}
 	}
 
-	dela.Logger.Trace().Msgf("%s is certified", h.me)
+	logger.Trace().Msgf("%s is certified", h.me)
 
 	// 6. Send back the public DKG key
 	distrKey, err := h.dkg.DistKeyShare()
@@ -344,9 +636,11 @@ func (h *Handler) certify(resps []*pedersen.Response, out mino.Sender,
 	// 7. Update the state before sending to acknowledgement to the
 	// orchestrator, so that it can process decrypt requests right away.
 	h.startRes.SetDistKey(distrKey.Public())
+	h.startRes.SetCommits(distrKey.Commits)
 
 	h.Lock()
 	h.privShare = distrKey.PriShare()
+	h.distShare = distrKey
 	h.Unlock()
 
 	done := types.NewStartDone(distrKey.Public())
@@ -362,7 +656,7 @@ func (h *Handler) certify(resps []*pedersen.Response, out mino.Sender,
 func (h *Handler) handleDeal(msg types.Deal, from mino.Address, addrs []mino.Address,
 	out mino.Sender) error {
 
-	dela.Logger.Trace().Msgf("%s received deal from %s", h.me, from)
+	logger.Trace().Msgf("%s received deal from %s", h.me, from)
 
 	deal := &pedersen.Deal{
 		Index: msg.GetIndex(),
@@ -399,7 +693,7 @@ func (h *Handler) handleDeal(msg types.Deal, from mino.Address, addrs []mino.Add
 		errs := out.Send(resp, addr)
 		err = <-errs
 		if err != nil {
-			dela.Logger.Warn().Msgf("got an error while sending "+
+			logger.Warn().Msgf("got an error while sending "+
 				"response: %v", err)
 			return xerrors.Errorf("failed to send response to '%s': %v", addr, err)
 		}
@@ -408,3 +702,13 @@ func (h *Handler) handleDeal(msg types.Deal, from mino.Address, addrs []mino.Add
 
 	return nil
 }
+
+func containsPoint(points []kyber.Point, point kyber.Point) bool {
+	for _, p := range points {
+		if p.Equal(point) {
+			return true
+		}
+	}
+
+	return false
+}
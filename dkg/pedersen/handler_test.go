@@ -14,7 +14,7 @@ import (
 )
 
 func TestHandler_Stream(t *testing.T) {
-	h := Handler{startRes: &state{}}
+	h := Handler{startRes: &state{}, suite: suite}
 	receiver := fake.NewBadReceiver()
 	err := h.Stream(fake.Sender{}, receiver)
 	require.EqualError(t, err, fake.Err("failed to receive"))
@@ -35,6 +35,35 @@ func TestHandler_Stream(t *testing.T) {
 	err = h.Stream(fake.NewBadSender(), receiver)
 	require.EqualError(t, err, fake.Err("got an error while sending the decrypt reply"))
 
+	receiver = fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewDecryptBatchRequest(
+			[]kyber.Point{suite.Point()}, nil)),
+	)
+	err = h.Stream(fake.Sender{}, receiver)
+	require.EqualError(t, err, "mismatch number of K 1 != 0")
+
+	receiver = fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewDecryptBatchRequest(
+			[]kyber.Point{suite.Point()}, []kyber.Point{suite.Point()})),
+	)
+	err = h.Stream(fake.NewBadSender(), receiver)
+	require.EqualError(t, err, fake.Err("got an error while sending the decrypt batch reply"))
+
+	// A node that left the group during a resharing cannot decrypt.
+	h.privShare = nil
+	receiver = fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.DecryptRequest{C: suite.Point()}),
+	)
+	err = h.Stream(fake.Sender{}, receiver)
+	require.EqualError(t, err, "node is not a share-holder")
+
+	receiver = fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewDecryptBatchRequest(
+			[]kyber.Point{suite.Point()}, []kyber.Point{suite.Point()})),
+	)
+	err = h.Stream(fake.Sender{}, receiver)
+	require.EqualError(t, err, "node is not a share-holder")
+
 	receiver = fake.NewReceiver(
 		fake.NewRecvMsg(fake.NewAddress(0), fake.Message{}),
 	)
@@ -42,11 +71,42 @@ func TestHandler_Stream(t *testing.T) {
 	require.EqualError(t, err, "expected Start message, decrypt request or Deal as first message, got: fake.Message")
 }
 
+func TestHandler_Process(t *testing.T) {
+	h := Handler{startRes: &state{}, suite: suite}
+
+	req := mino.Request{
+		Address: fake.NewAddress(0),
+		Message: types.NewPublicKeyRequest(),
+	}
+
+	_, err := h.Process(req)
+	require.EqualError(t, err, "DKG has not been initialized")
+
+	// A ping is answered even before the setup.
+	resp, err := h.Process(mino.Request{Message: types.NewPingRequest()})
+	require.NoError(t, err)
+	require.Equal(t, types.NewPingReply(), resp)
+
+	pubkey := suite.Point().Pick(suite.RandomStream())
+
+	h.startRes.distrKey = pubkey
+	h.startRes.participants = []mino.Address{fake.NewAddress(0)}
+
+	resp, err = h.Process(req)
+	require.NoError(t, err)
+	require.Equal(t, types.NewPublicKeyReply(pubkey), resp)
+
+	req.Message = fake.Message{}
+	_, err = h.Process(req)
+	require.EqualError(t, err, "unsupported message of type 'fake.Message'")
+}
+
 func TestHandler_Start(t *testing.T) {
 	privKey := suite.Scalar().Pick(suite.RandomStream())
 	pubKey := suite.Point().Mul(privKey, nil)
 
 	h := Handler{
+		suite:    suite,
 		startRes: &state{},
 		privKey:  privKey,
 	}
@@ -82,6 +142,61 @@ func TestHandler_Start(t *testing.T) {
 	require.EqualError(t, err, "failed to certify: expected a response, got: <nil>")
 }
 
+func TestHandler_Reshare(t *testing.T) {
+	privKey := suite.Scalar().Pick(suite.RandomStream())
+	pubKey := suite.Point().Mul(privKey, nil)
+
+	h := Handler{
+		suite:    suite,
+		startRes: &state{},
+		privKey:  privKey,
+	}
+
+	start := types.NewStartResharing(1, 1,
+		[]mino.Address{fake.NewAddress(0)}, []kyber.Point{},
+		nil, nil, nil)
+
+	err := h.reshare(start, nil, nil, nil, nil, nil)
+	require.EqualError(t, err, "there should be as many new players as pubKey: 1 := 0")
+
+	start = types.NewStartResharing(2, 1,
+		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		[]kyber.Point{pubKey, suite.Point()},
+		[]mino.Address{fake.NewAddress(0)}, []kyber.Point{pubKey}, nil)
+
+	err = h.reshare(start, nil, nil, nil, nil, nil)
+	require.EqualError(t, err, "no share to reshare")
+
+	start = types.NewStartResharing(2, 1,
+		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		[]kyber.Point{pubKey, suite.Point()},
+		[]mino.Address{fake.NewAddress(1)}, []kyber.Point{suite.Point()}, nil)
+
+	err = h.reshare(start, nil, nil, nil, nil, nil)
+	require.EqualError(t, err, "missing the public commitments")
+
+	start = types.NewStartResharing(2, 1,
+		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		[]kyber.Point{pubKey, suite.Point()},
+		nil, nil, []kyber.Point{suite.Point()})
+
+	err = h.reshare(start, nil, nil, nil, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to create resharing DKG: ")
+
+	start = types.NewStartResharing(2, 1,
+		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		[]kyber.Point{pubKey, suite.Point()},
+		[]mino.Address{fake.NewAddress(1)}, []kyber.Point{suite.Point()},
+		[]kyber.Point{suite.Point()})
+
+	err = h.reshare(start, nil, nil, nil, fake.Sender{}, fake.NewBadReceiver())
+	require.EqualError(t, err, fake.Err("failed to receive after sending deals"))
+
+	err = h.reshare(start, nil, nil, nil, fake.Sender{}, &fake.Receiver{})
+	require.EqualError(t, err, "unexpected message: <nil>")
+}
+
 func TestHandler_Certify(t *testing.T) {
 	privKey := suite.Scalar().Pick(suite.RandomStream())
 	pubKey := suite.Point().Mul(privKey, nil)
@@ -90,6 +205,7 @@ func TestHandler_Certify(t *testing.T) {
 	require.NoError(t, err)
 
 	h := Handler{
+		suite:    suite,
 		startRes: &state{},
 		dkg:      dkg,
 	}
@@ -138,7 +254,8 @@ func TestHandler_HandleDeal(t *testing.T) {
 	)
 
 	h := Handler{
-		dkg: dkg1,
+		suite: suite,
+		dkg:   dkg1,
 	}
 	err = h.handleDeal(dealMsg, nil, []mino.Address{fake.NewAddress(0)}, fake.NewBadSender())
 	require.EqualError(t, err, fake.Err("failed to send response to 'fake.Address[0]'"))
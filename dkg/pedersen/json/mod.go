@@ -5,6 +5,7 @@ import (
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
 	"go.dedis.ch/kyber/v3/suites"
 	"golang.org/x/xerrors"
 )
@@ -23,6 +24,16 @@ type Start struct {
 	PublicKeys []PublicKey
 }
 
+type StartResharing struct {
+	ThresholdNew int
+	ThresholdOld int
+	AddrsNew     []Address
+	PubkeysNew   []PublicKey
+	AddrsOld     []Address
+	PubkeysOld   []PublicKey
+	Commits      []PublicKey
+}
+
 type EncryptedDeal struct {
 	DHKey     []byte
 	Signature []byte
@@ -52,23 +63,60 @@ type StartDone struct {
 	PublicKey PublicKey
 }
 
+type PublicKeyRequest struct{}
+
+type PublicKeyReply struct {
+	PublicKey PublicKey
+}
+
+type PingRequest struct{}
+
+type PingReply struct{}
+
 type DecryptRequest struct {
 	K []byte
 	C []byte
 }
 
 type DecryptReply struct {
-	V []byte
-	I int64
+	V     []byte
+	I     int64
+	Proof *Proof `json:",omitempty"`
+}
+
+type DecryptBatchRequest struct {
+	Ks []PublicKey
+	Cs []PublicKey
+}
+
+type DecryptBatchReply struct {
+	I  int64
+	Vs []PublicKey
+}
+
+// Proof is the JSON representation of a DLEQ proof.
+type Proof struct {
+	C  []byte
+	R  []byte
+	VG []byte
+	VH []byte
 }
 
 type Message struct {
-	Start          *Start          `json:",omitempty"`
-	Deal           *Deal           `json:",omitempty"`
-	Response       *Response       `json:",omitempty"`
-	StartDone      *StartDone      `json:",omitempty"`
-	DecryptRequest *DecryptRequest `json:",omitempty"`
-	DecryptReply   *DecryptReply   `json:",omitempty"`
+	Start            *Start            `json:",omitempty"`
+	StartResharing   *StartResharing   `json:",omitempty"`
+	Deal             *Deal             `json:",omitempty"`
+	Response         *Response         `json:",omitempty"`
+	StartDone        *StartDone        `json:",omitempty"`
+	PublicKeyRequest *PublicKeyRequest `json:",omitempty"`
+	PublicKeyReply   *PublicKeyReply   `json:",omitempty"`
+	PingRequest      *PingRequest      `json:",omitempty"`
+	PingReply        *PingReply        `json:",omitempty"`
+	DecryptRequest   *DecryptRequest   `json:",omitempty"`
+	DecryptReply     *DecryptReply     `json:",omitempty"`
+
+	DecryptBatchRequest *DecryptBatchRequest `json:",omitempty"`
+	DecryptBatchReply   *DecryptBatchReply   `json:",omitempty"`
 }
 
 // MsgFormat is the engine to encode and decode dkg messages in JSON format.
@@ -91,24 +139,14 @@ func (f msgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error)
 
 	switch in := msg.(type) {
 	case types.Start:
-		addrs := make([]Address, len(in.GetAddresses()))
-		for i, addr := range in.GetAddresses() {
-			data, err := addr.MarshalText()
-			if err != nil {
-				return nil, xerrors.Errorf("couldn't marshal address: %v", err)
-			}
-
-			addrs[i] = data
+		addrs, err := encodeAddresses(in.GetAddresses())
+		if err != nil {
+			return nil, err
 		}
 
-		pubkeys := make([]PublicKey, len(in.GetPublicKeys()))
-		for i, pubkey := range in.GetPublicKeys() {
-			data, err := pubkey.MarshalBinary()
-			if err != nil {
-				return nil, xerrors.Errorf("couldn't marshal public key: %v", err)
-			}
-
-			pubkeys[i] = data
+		pubkeys, err := encodePoints(in.GetPublicKeys())
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't marshal public key: %v", err)
 		}
 
 		start := Start{
@@ -118,6 +156,13 @@ func (f msgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error)
 		}
 
 		m = Message{Start: &start}
+	case types.StartResharing:
+		start, err := encodeStartResharing(in)
+		if err != nil {
+			return nil, err
+		}
+
+		m = Message{StartResharing: start}
 	case types.Deal:
 		d := Deal{
 			Index:     in.GetIndex(),
@@ -154,6 +199,23 @@ func (f msgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error)
 		}
 
 		m = Message{StartDone: &ack}
+	case types.PublicKeyRequest:
+		m = Message{PublicKeyRequest: &PublicKeyRequest{}}
+	case types.PublicKeyReply:
+		pubkey, err := in.GetPublicKey().MarshalBinary()
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't marshal public key: %v", err)
+		}
+
+		resp := PublicKeyReply{
+			PublicKey: pubkey,
+		}
+
+		m = Message{PublicKeyReply: &resp}
+	case types.PingRequest:
+		m = Message{PingRequest: &PingRequest{}}
+	case types.PingReply:
+		m = Message{PingReply: &PingReply{}}
 	case types.DecryptRequest:
 		k, err := in.GetK().MarshalBinary()
 		if err != nil {
@@ -182,7 +244,43 @@ func (f msgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error)
 			I: in.GetI(),
 		}
 
+		if in.GetProof() != nil {
+			resp.Proof, err = encodeProof(in.GetProof())
+			if err != nil {
+				return nil, xerrors.Errorf("couldn't marshal proof: %v", err)
+			}
+		}
+
 		m = Message{DecryptReply: &resp}
+	case types.DecryptBatchRequest:
+		ks, err := encodePoints(in.GetKs())
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't marshal K: %v", err)
+		}
+
+		cs, err := encodePoints(in.GetCs())
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't marshal C: %v", err)
+		}
+
+		req := DecryptBatchRequest{
+			Ks: ks,
+			Cs: cs,
+		}
+
+		m = Message{DecryptBatchRequest: &req}
+	case types.DecryptBatchReply:
+		vs, err := encodePoints(in.GetVs())
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't marshal V: %v", err)
+		}
+
+		resp := DecryptBatchReply{
+			I:  in.GetI(),
+			Vs: vs,
+		}
+
+		m = Message{DecryptBatchReply: &resp}
 	default:
 		return nil, xerrors.Errorf("unsupported message of type '%T'", msg)
 	}
@@ -204,10 +302,20 @@ func (f msgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error)
 		return nil, xerrors.Errorf("couldn't deserialize message: %v", err)
 	}
 
+	// The points are decoded with the suite of the DKG when it is known.
+	factory, ok := ctx.GetFactory(types.SuiteKey{}).(types.SuiteFactory)
+	if ok {
+		f.suite = factory.GetSuite()
+	}
+
 	if m.Start != nil {
 		return f.decodeStart(ctx, m.Start)
 	}
 
+	if m.StartResharing != nil {
+		return f.decodeStartResharing(ctx, m.StartResharing)
+	}
+
 	if m.Deal != nil {
 		deal := types.NewDeal(
 			m.Deal.Index,
@@ -249,6 +357,28 @@ func (f msgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error)
 		return ack, nil
 	}
 
+	if m.PublicKeyRequest != nil {
+		return types.NewPublicKeyRequest(), nil
+	}
+
+	if m.PublicKeyReply != nil {
+		point := f.suite.Point()
+		err := point.UnmarshalBinary(m.PublicKeyReply.PublicKey)
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't unmarshal public key: %v", err)
+		}
+
+		return types.NewPublicKeyReply(point), nil
+	}
+
+	if m.PingRequest != nil {
+		return types.NewPingRequest(), nil
+	}
+
+	if m.PingReply != nil {
+		return types.NewPingReply(), nil
+	}
+
 	if m.DecryptRequest != nil {
 		k := f.suite.Point()
 		err = k.UnmarshalBinary(m.DecryptRequest.K)
@@ -274,39 +404,251 @@ func (f msgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error)
 			return nil, xerrors.Errorf("couldn't unmarshal V: %v", err)
 		}
 
-		resp := types.NewDecryptReply(m.DecryptReply.I, v)
+		if m.DecryptReply.Proof == nil {
+			return types.NewDecryptReply(m.DecryptReply.I, v), nil
+		}
+
+		proof, err := f.decodeProof(m.DecryptReply.Proof)
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't unmarshal proof: %v", err)
+		}
+
+		resp := types.NewVerifiableDecryptReply(m.DecryptReply.I, v, proof)
 
 		return resp, nil
 	}
 
+	if m.DecryptBatchRequest != nil {
+		ks, err := f.decodePoints(m.DecryptBatchRequest.Ks)
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't unmarshal K: %v", err)
+		}
+
+		cs, err := f.decodePoints(m.DecryptBatchRequest.Cs)
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't unmarshal C: %v", err)
+		}
+
+		return types.NewDecryptBatchRequest(ks, cs), nil
+	}
+
+	if m.DecryptBatchReply != nil {
+		vs, err := f.decodePoints(m.DecryptBatchReply.Vs)
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't unmarshal V: %v", err)
+		}
+
+		return types.NewDecryptBatchReply(m.DecryptBatchReply.I, vs), nil
+	}
+
 	return nil, xerrors.New("message is empty")
 }
 
+func encodeProof(proof *dleq.Proof) (*Proof, error) {
+	c, err := proof.C.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("challenge: %v", err)
+	}
+
+	r, err := proof.R.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("response: %v", err)
+	}
+
+	vg, err := proof.VG.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("commitment VG: %v", err)
+	}
+
+	vh, err := proof.VH.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("commitment VH: %v", err)
+	}
+
+	return &Proof{C: c, R: r, VG: vg, VH: vh}, nil
+}
+
+func (f msgFormat) decodeProof(in *Proof) (*dleq.Proof, error) {
+	proof := &dleq.Proof{
+		C:  f.suite.Scalar(),
+		R:  f.suite.Scalar(),
+		VG: f.suite.Point(),
+		VH: f.suite.Point(),
+	}
+
+	err := proof.C.UnmarshalBinary(in.C)
+	if err != nil {
+		return nil, xerrors.Errorf("challenge: %v", err)
+	}
+
+	err = proof.R.UnmarshalBinary(in.R)
+	if err != nil {
+		return nil, xerrors.Errorf("response: %v", err)
+	}
+
+	err = proof.VG.UnmarshalBinary(in.VG)
+	if err != nil {
+		return nil, xerrors.Errorf("commitment VG: %v", err)
+	}
+
+	err = proof.VH.UnmarshalBinary(in.VH)
+	if err != nil {
+		return nil, xerrors.Errorf("commitment VH: %v", err)
+	}
+
+	return proof, nil
+}
+
 func (f msgFormat) decodeStart(ctx serde.Context, start *Start) (serde.Message, error) {
-	factory := ctx.GetFactory(types.AddrKey{})
+	fac, err := getAddressFactory(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	fac, ok := factory.(mino.AddressFactory)
-	if !ok {
-		return nil, xerrors.Errorf("invalid factory of type '%T'", factory)
+	pubkeys, err := f.decodePoints(start.PublicKeys)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't unmarshal public key: %v", err)
 	}
 
-	addrs := make([]mino.Address, len(start.Addresses))
-	for i, addr := range start.Addresses {
-		addrs[i] = fac.FromText(addr)
+	s := types.NewStart(start.Threshold, decodeAddresses(fac, start.Addresses), pubkeys)
+
+	return s, nil
+}
+
+func (f msgFormat) decodeStartResharing(ctx serde.Context,
+	start *StartResharing) (serde.Message, error) {
+
+	fac, err := getAddressFactory(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	pubkeys := make([]kyber.Point, len(start.PublicKeys))
-	for i, pubkey := range start.PublicKeys {
+	pubkeysNew, err := f.decodePoints(start.PubkeysNew)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't unmarshal new public key: %v", err)
+	}
+
+	pubkeysOld, err := f.decodePoints(start.PubkeysOld)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't unmarshal old public key: %v", err)
+	}
+
+	commits, err := f.decodePoints(start.Commits)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't unmarshal commit: %v", err)
+	}
+
+	s := types.NewStartResharing(
+		start.ThresholdNew,
+		start.ThresholdOld,
+		decodeAddresses(fac, start.AddrsNew),
+		pubkeysNew,
+		decodeAddresses(fac, start.AddrsOld),
+		pubkeysOld,
+		commits,
+	)
+
+	return s, nil
+}
+
+func (f msgFormat) decodePoints(data []PublicKey) ([]kyber.Point, error) {
+	points := make([]kyber.Point, len(data))
+	for i, buf := range data {
 		point := f.suite.Point()
-		err := point.UnmarshalBinary(pubkey)
+		err := point.UnmarshalBinary(buf)
 		if err != nil {
-			return nil, xerrors.Errorf("couldn't unmarshal public key: %v", err)
+			return nil, err
 		}
 
-		pubkeys[i] = point
+		points[i] = point
 	}
 
-	s := types.NewStart(start.Threshold, addrs, pubkeys)
+	return points, nil
+}
 
-	return s, nil
+func encodeStartResharing(in types.StartResharing) (*StartResharing, error) {
+	addrsNew, err := encodeAddresses(in.GetAddrsNew())
+	if err != nil {
+		return nil, err
+	}
+
+	addrsOld, err := encodeAddresses(in.GetAddrsOld())
+	if err != nil {
+		return nil, err
+	}
+
+	pubkeysNew, err := encodePoints(in.GetPubkeysNew())
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't marshal new public key: %v", err)
+	}
+
+	pubkeysOld, err := encodePoints(in.GetPubkeysOld())
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't marshal old public key: %v", err)
+	}
+
+	commits, err := encodePoints(in.GetCommits())
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't marshal commit: %v", err)
+	}
+
+	start := &StartResharing{
+		ThresholdNew: in.GetThresholdNew(),
+		ThresholdOld: in.GetThresholdOld(),
+		AddrsNew:     addrsNew,
+		PubkeysNew:   pubkeysNew,
+		AddrsOld:     addrsOld,
+		PubkeysOld:   pubkeysOld,
+		Commits:      commits,
+	}
+
+	return start, nil
+}
+
+func encodeAddresses(addrs []mino.Address) ([]Address, error) {
+	res := make([]Address, len(addrs))
+	for i, addr := range addrs {
+		data, err := addr.MarshalText()
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't marshal address: %v", err)
+		}
+
+		res[i] = data
+	}
+
+	return res, nil
+}
+
+func encodePoints(points []kyber.Point) ([]PublicKey, error) {
+	res := make([]PublicKey, len(points))
+	for i, point := range points {
+		data, err := point.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = data
+	}
+
+	return res, nil
+}
+
+func getAddressFactory(ctx serde.Context) (mino.AddressFactory, error) {
+	factory := ctx.GetFactory(types.AddrKey{})
+
+	fac, ok := factory.(mino.AddressFactory)
+	if !ok {
+		return nil, xerrors.Errorf("invalid factory of type '%T'", factory)
+	}
+
+	return fac, nil
+}
+
+func decodeAddresses(fac mino.AddressFactory, data []Address) []mino.Address {
+	addrs := make([]mino.Address, len(data))
+	for i, addr := range data {
+		addrs[i] = fac.FromText(addr)
+	}
+
+	return addrs
 }
@@ -10,6 +10,7 @@ import (
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
 	"go.dedis.ch/kyber/v3/suites"
 )
 
@@ -42,6 +43,46 @@ func TestMessageFormat_Start_Encode(t *testing.T) {
 	require.EqualError(t, err, "unsupported message of type 'fake.Message'")
 }
 
+func TestMessageFormat_StartResharing_Encode(t *testing.T) {
+	addrs := []mino.Address{fake.NewAddress(0)}
+	points := []kyber.Point{suite.Point()}
+
+	start := types.NewStartResharing(1, 2, addrs, points, addrs, points, points)
+
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	data, err := format.Encode(ctx, start)
+	require.NoError(t, err)
+	regexp := `{"StartResharing":{"ThresholdNew":1,"ThresholdOld":2,` +
+		`"AddrsNew":\["AAAAAA=="\],"PubkeysNew":\["[^"]+"\],` +
+		`"AddrsOld":\["AAAAAA=="\],"PubkeysOld":\["[^"]+"\],"Commits":\["[^"]+"\]}}`
+	require.Regexp(t, regexp, string(data))
+
+	bad := []mino.Address{fake.NewBadAddress()}
+	badPoints := []kyber.Point{badPoint{}}
+
+	start = types.NewStartResharing(0, 0, bad, nil, nil, nil, nil)
+	_, err = format.Encode(ctx, start)
+	require.EqualError(t, err, fake.Err("couldn't marshal address"))
+
+	start = types.NewStartResharing(0, 0, nil, nil, bad, nil, nil)
+	_, err = format.Encode(ctx, start)
+	require.EqualError(t, err, fake.Err("couldn't marshal address"))
+
+	start = types.NewStartResharing(0, 0, nil, badPoints, nil, nil, nil)
+	_, err = format.Encode(ctx, start)
+	require.EqualError(t, err, fake.Err("couldn't marshal new public key"))
+
+	start = types.NewStartResharing(0, 0, nil, nil, nil, badPoints, nil)
+	_, err = format.Encode(ctx, start)
+	require.EqualError(t, err, fake.Err("couldn't marshal old public key"))
+
+	start = types.NewStartResharing(0, 0, nil, nil, nil, nil, badPoints)
+	_, err = format.Encode(ctx, start)
+	require.EqualError(t, err, fake.Err("couldn't marshal commit"))
+}
+
 func TestMessageFormat_Deal_Encode(t *testing.T) {
 	deal := types.NewDeal(1, []byte{1}, types.EncryptedDeal{})
 
@@ -81,6 +122,35 @@ func TestMessageFormat_StartDone_Encode(t *testing.T) {
 	require.EqualError(t, err, fake.Err("couldn't marshal public key"))
 }
 
+func TestMessageFormat_PublicKey_Encode(t *testing.T) {
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	data, err := format.Encode(ctx, types.NewPublicKeyRequest())
+	require.NoError(t, err)
+	require.Equal(t, `{"PublicKeyRequest":{}}`, string(data))
+
+	data, err = format.Encode(ctx, types.NewPublicKeyReply(suite.Point()))
+	require.NoError(t, err)
+	require.Regexp(t, `^{"PublicKeyReply":{"PublicKey":"[^"]+"}}$`, string(data))
+
+	_, err = format.Encode(ctx, types.NewPublicKeyReply(badPoint{}))
+	require.EqualError(t, err, fake.Err("couldn't marshal public key"))
+}
+
+func TestMessageFormat_Ping_Encode(t *testing.T) {
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	data, err := format.Encode(ctx, types.NewPingRequest())
+	require.NoError(t, err)
+	require.Equal(t, `{"PingRequest":{}}`, string(data))
+
+	data, err = format.Encode(ctx, types.NewPingReply())
+	require.NoError(t, err)
+	require.Equal(t, `{"PingReply":{}}`, string(data))
+}
+
 func TestMessageFormat_DecryptRequest_Encode(t *testing.T) {
 	req := types.NewDecryptRequest(suite.Point(), suite.Point())
 
@@ -114,6 +184,79 @@ func TestMessageFormat_DecryptReply_Encode(t *testing.T) {
 	resp.V = badPoint{}
 	_, err = format.Encode(ctx, resp)
 	require.EqualError(t, err, fake.Err("couldn't marshal V"))
+
+	proof, _, _, err := dleq.NewDLEQProof(suite, suite.Point().Base(),
+		suite.Point().Pick(suite.RandomStream()), suite.Scalar().Pick(suite.RandomStream()))
+	require.NoError(t, err)
+
+	resp = types.NewVerifiableDecryptReply(5, suite.Point(), proof)
+
+	data, err = format.Encode(ctx, resp)
+	require.NoError(t, err)
+	require.Regexp(t, `"DecryptReply":{"V":"[^"]+","I":5,"Proof":{"C":"[^"]+","R":"[^"]+","VG":"[^"]+","VH":"[^"]+"}}`, string(data))
+
+	decoded, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.True(t, proof.C.Equal(decoded.(types.DecryptReply).GetProof().C))
+	require.True(t, proof.R.Equal(decoded.(types.DecryptReply).GetProof().R))
+	require.True(t, proof.VG.Equal(decoded.(types.DecryptReply).GetProof().VG))
+	require.True(t, proof.VH.Equal(decoded.(types.DecryptReply).GetProof().VH))
+
+	resp.Proof = &dleq.Proof{C: proof.C, R: proof.R, VG: proof.VG, VH: badPoint{}}
+	_, err = format.Encode(ctx, resp)
+	require.EqualError(t, err, fake.Err("couldn't marshal proof: commitment VH"))
+
+	resp.Proof.VG = badPoint{}
+	_, err = format.Encode(ctx, resp)
+	require.EqualError(t, err, fake.Err("couldn't marshal proof: commitment VG"))
+}
+
+func TestMessageFormat_DecryptBatchRequest_Encode(t *testing.T) {
+	req := types.NewDecryptBatchRequest(
+		[]kyber.Point{suite.Point(), suite.Point()},
+		[]kyber.Point{suite.Point(), suite.Point()},
+	)
+
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	data, err := format.Encode(ctx, req)
+	require.NoError(t, err)
+	require.Regexp(t, `"DecryptBatchRequest":{"Ks":\["[^"]+","[^"]+"\],"Cs":\["[^"]+","[^"]+"\]}`, string(data))
+
+	decoded, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Len(t, decoded.(types.DecryptBatchRequest).GetKs(), 2)
+	require.Len(t, decoded.(types.DecryptBatchRequest).GetCs(), 2)
+
+	req.Ks[1] = badPoint{}
+	_, err = format.Encode(ctx, req)
+	require.EqualError(t, err, fake.Err("couldn't marshal K"))
+
+	req.Ks[1] = suite.Point()
+	req.Cs[0] = badPoint{}
+	_, err = format.Encode(ctx, req)
+	require.EqualError(t, err, fake.Err("couldn't marshal C"))
+}
+
+func TestMessageFormat_DecryptBatchReply_Encode(t *testing.T) {
+	resp := types.NewDecryptBatchReply(5, []kyber.Point{suite.Point()})
+
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	data, err := format.Encode(ctx, resp)
+	require.NoError(t, err)
+	require.Regexp(t, `"DecryptBatchReply":{"I":5,"Vs":\["[^"]+"\]}`, string(data))
+
+	decoded, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), decoded.(types.DecryptBatchReply).GetI())
+	require.Len(t, decoded.(types.DecryptBatchReply).GetVs(), 1)
+
+	resp.Vs[0] = badPoint{}
+	_, err = format.Encode(ctx, resp)
+	require.EqualError(t, err, fake.Err("couldn't marshal V"))
 }
 
 func TestMessageFormat_Decode(t *testing.T) {
@@ -145,6 +288,44 @@ func TestMessageFormat_Decode(t *testing.T) {
 	_, err = format.Decode(badCtx, []byte(`{"Start":{}}`))
 	require.EqualError(t, err, "invalid factory of type '<nil>'")
 
+	// Decode start resharing messages.
+	expectedResharing := types.NewStartResharing(
+		2, 3,
+		[]mino.Address{fake.NewAddress(0)},
+		[]kyber.Point{suite.Point()},
+		[]mino.Address{fake.NewAddress(1), fake.NewAddress(2)},
+		[]kyber.Point{suite.Point(), suite.Point()},
+		[]kyber.Point{suite.Point(), suite.Point(), suite.Point()},
+	)
+
+	data, err = format.Encode(ctx, expectedResharing)
+	require.NoError(t, err)
+
+	resharing, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, 2, resharing.(types.StartResharing).GetThresholdNew())
+	require.Equal(t, 3, resharing.(types.StartResharing).GetThresholdOld())
+	require.Len(t, resharing.(types.StartResharing).GetAddrsNew(), 1)
+	require.Len(t, resharing.(types.StartResharing).GetPubkeysNew(), 1)
+	require.Len(t, resharing.(types.StartResharing).GetAddrsOld(), 2)
+	require.Len(t, resharing.(types.StartResharing).GetPubkeysOld(), 2)
+	require.Len(t, resharing.(types.StartResharing).GetCommits(), 3)
+
+	_, err = format.Decode(ctx, []byte(`{"StartResharing":{"PubkeysNew":[[]]}}`))
+	require.EqualError(t, err,
+		"couldn't unmarshal new public key: invalid Ed25519 curve point")
+
+	_, err = format.Decode(ctx, []byte(`{"StartResharing":{"PubkeysOld":[[]]}}`))
+	require.EqualError(t, err,
+		"couldn't unmarshal old public key: invalid Ed25519 curve point")
+
+	_, err = format.Decode(ctx, []byte(`{"StartResharing":{"Commits":[[]]}}`))
+	require.EqualError(t, err,
+		"couldn't unmarshal commit: invalid Ed25519 curve point")
+
+	_, err = format.Decode(badCtx, []byte(`{"StartResharing":{}}`))
+	require.EqualError(t, err, "invalid factory of type '<nil>'")
+
 	// Decode deal messages.
 	deal, err := format.Decode(ctx, []byte(`{"Deal":{}}`))
 	require.NoError(t, err)
@@ -166,9 +347,33 @@ func TestMessageFormat_Decode(t *testing.T) {
 	require.EqualError(t, err,
 		"couldn't unmarshal public key: invalid Ed25519 curve point")
 
+	// Decode public key messages.
+	req, err := format.Decode(ctx, []byte(`{"PublicKeyRequest":{}}`))
+	require.NoError(t, err)
+	require.Equal(t, types.NewPublicKeyRequest(), req)
+
+	data = []byte(fmt.Sprintf(`{"PublicKeyReply":{"PublicKey":"%s"}}`, testPoint))
+	reply, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.IsType(t, types.PublicKeyReply{}, reply)
+
+	data = []byte(`{"PublicKeyReply":{"PublicKey":[]}}`)
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err,
+		"couldn't unmarshal public key: invalid Ed25519 curve point")
+
+	// Decode ping messages.
+	req, err = format.Decode(ctx, []byte(`{"PingRequest":{}}`))
+	require.NoError(t, err)
+	require.Equal(t, types.NewPingRequest(), req)
+
+	reply, err = format.Decode(ctx, []byte(`{"PingReply":{}}`))
+	require.NoError(t, err)
+	require.Equal(t, types.NewPingReply(), reply)
+
 	// Decode decryption request messages.
 	data = []byte(fmt.Sprintf(`{"DecryptRequest":{"K":"%s","C":"%s"}}`, testPoint, testPoint))
-	req, err := format.Decode(ctx, data)
+	req, err = format.Decode(ctx, data)
 	require.NoError(t, err)
 	require.IsType(t, types.DecryptRequest{}, req)
 
@@ -193,6 +398,27 @@ func TestMessageFormat_Decode(t *testing.T) {
 	require.EqualError(t, err,
 		"couldn't unmarshal V: invalid Ed25519 curve point")
 
+	data = []byte(fmt.Sprintf(`{"DecryptReply":{"I":4,"V":"%s","Proof":{}}}`, testPoint))
+	_, err = format.Decode(ctx, data)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "couldn't unmarshal proof: challenge: ")
+
+	// Decode batch decryption messages.
+	data = []byte(`{"DecryptBatchRequest":{"Ks":[[]],"Cs":[]}}`)
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err,
+		"couldn't unmarshal K: invalid Ed25519 curve point")
+
+	data = []byte(fmt.Sprintf(`{"DecryptBatchRequest":{"Ks":["%s"],"Cs":[[]]}}`, testPoint))
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err,
+		"couldn't unmarshal C: invalid Ed25519 curve point")
+
+	data = []byte(`{"DecryptBatchReply":{"I":1,"Vs":[[]]}}`)
+	_, err = format.Decode(ctx, data)
+	require.EqualError(t, err,
+		"couldn't unmarshal V: invalid Ed25519 curve point")
+
 	_, err = format.Decode(fake.NewBadContext(), []byte(`{}`))
 	require.EqualError(t, err, fake.Err("couldn't deserialize message"))
 
@@ -200,6 +426,27 @@ func TestMessageFormat_Decode(t *testing.T) {
 	require.EqualError(t, err, "message is empty")
 }
 
+func TestMessageFormat_Suite_Decode(t *testing.T) {
+	p256 := suites.MustFind("P256")
+	point := p256.Point().Pick(p256.RandomStream())
+
+	format := newMsgFormat()
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	data, err := format.Encode(ctx, types.NewPublicKeyReply(point))
+	require.NoError(t, err)
+
+	_, err = format.Decode(ctx, data)
+	require.Error(t, err)
+
+	factory := types.NewMessageFactory(fake.AddressFactory{}, p256)
+	ctx = serde.WithFactory(ctx, types.SuiteKey{}, factory)
+
+	msg, err := format.Decode(ctx, data)
+	require.NoError(t, err)
+	require.True(t, point.Equal(msg.(types.PublicKeyReply).GetPublicKey()))
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
@@ -1,9 +1,10 @@
 package pedersen
 
 import (
+	"sync"
 	"time"
 
-	"go.dedis.ch/dela/crypto/ed25519"
+	"go.dedis.ch/dela"
 
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/dkg"
@@ -19,9 +20,12 @@ import (
 	"golang.org/x/xerrors"
 )
 
-// suite is the Kyber suite for Pedersen.
+// suite is the default Kyber suite for Pedersen.
 var suite = suites.MustFind("Ed25519")
 
+// logger is the logger of the DKG subsystem.
+var logger = dela.Logger.With().Str(dela.SubsystemKey, "dkg").Logger()
+
 var (
 	// protocolNameSetup denotes the value of the protocol span tag associated
 	// with the `dkg-setup` protocol.
@@ -29,45 +33,91 @@ var (
 	// protocolNameDecrypt denotes the value of the protocol span tag
 	// associated with the `dkg-decrypt` protocol.
 	protocolNameDecrypt = "dkg-decrypt"
+	// protocolNameReshare denotes the value of the protocol span tag
+	// associated with the `dkg-reshare` protocol.
+	protocolNameReshare = "dkg-reshare"
 )
 
 const (
-	setupTimeout   = time.Second * 300
-	decryptTimeout = time.Second * 100
+	setupTimeout     = time.Second * 300
+	decryptTimeout   = time.Second * 100
+	publicKeyTimeout = time.Second * 10
 )
 
 // Pedersen allows one to initialize a new DKG protocol.
 //
 // - implements dkg.DKG
 type Pedersen struct {
-	privKey kyber.Scalar
-	mino    mino.Mino
-	factory serde.Factory
+	privKey        kyber.Scalar
+	mino           mino.Mino
+	factory        serde.Factory
+	suite          suites.Suite
+	decryptTimeout time.Duration
+}
+
+// Option is the type of option to configure the DKG.
+type Option func(*Pedersen)
+
+// WithSuite is an option to use a Kyber suite other than Ed25519. All the
+// participants of the DKG must use the same suite.
+func WithSuite(s suites.Suite) Option {
+	return func(p *Pedersen) {
+		p.suite = s
+	}
+}
+
+// WithDecryptTimeout is an option to set the maximum amount of time to collect
+// the partial decryptions of the share-holders. A decryption succeeds as soon as
+// a threshold of them is received, so that the slow or failed share-holders
+// only delay it up to the timeout.
+func WithDecryptTimeout(timeout time.Duration) Option {
+	return func(p *Pedersen) {
+		p.decryptTimeout = timeout
+	}
 }
 
 // NewPedersen returns a new DKG Pedersen factory
-func NewPedersen(m mino.Mino) (*Pedersen, kyber.Point) {
-	factory := types.NewMessageFactory(m.GetAddressFactory())
+func NewPedersen(m mino.Mino, opts ...Option) (*Pedersen, kyber.Point) {
+	p := &Pedersen{
+		mino:           m,
+		suite:          suite,
+		decryptTimeout: decryptTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.factory = types.NewMessageFactory(m.GetAddressFactory(), p.suite)
+	p.privKey = p.suite.Scalar().Pick(p.suite.RandomStream())
 
-	privkey := suite.Scalar().Pick(suite.RandomStream())
-	pubkey := suite.Point().Mul(privkey, nil)
+	return p, p.GetPublicKey()
+}
+
+// GetPublicKey returns the public key the node is using to participate in the
+// DKG protocols.
+func (s *Pedersen) GetPublicKey() kyber.Point {
+	return s.suite.Point().Mul(s.privKey, nil)
+}
 
-	return &Pedersen{
-		privKey: privkey,
-		mino:    m,
-		factory: factory,
-	}, pubkey
+// GetSuite returns the Kyber suite of the DKG.
+func (s *Pedersen) GetSuite() suites.Suite {
+	return s.suite
 }
 
 // Listen implements dkg.DKG. It must be called on each node that participates
 // in the DKG. Creates the RPC.
 func (s *Pedersen) Listen() (dkg.Actor, error) {
-	h := NewHandler(s.privKey, s.mino.GetAddress())
+	h := NewHandler(s.privKey, s.mino.GetAddress(), s.suite)
 
 	a := &Actor{
-		rpc:      mino.MustCreateRPC(s.mino, "dkg", h, s.factory),
-		factory:  s.factory,
-		startRes: h.startRes,
+		rpc:            mino.MustCreateRPC(s.mino, "dkg", h, s.factory),
+		factory:        s.factory,
+		addrFactory:    s.mino.GetAddressFactory(),
+		handler:        h,
+		startRes:       h.startRes,
+		suite:          s.suite,
+		decryptTimeout: s.decryptTimeout,
 	}
 
 	return a, nil
@@ -77,20 +127,33 @@ func (s *Pedersen) Listen() (dkg.Actor, error) {
 //
 // - implements dkg.Actor
 type Actor struct {
-	rpc      mino.RPC
-	factory  serde.Factory
-	startRes *state
+	rpc            mino.RPC
+	factory        serde.Factory
+	addrFactory    mino.AddressFactory
+	handler        *Handler
+	startRes       *state
+	suite          suites.Suite
+	decryptTimeout time.Duration
 }
 
 // Setup implement dkg.Actor. It initializes the DKG.
 func (a *Actor) Setup(co crypto.CollectiveAuthority, threshold int) (kyber.Point, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+
+	return a.SetupContext(ctx, co, threshold)
+}
+
+// SetupContext implements dkg.Actor. It initializes the DKG and aborts when the
+// context is done. The partial state of the setup is then cleaned so that the
+// setup can be tried again.
+func (a *Actor) SetupContext(ctx context.Context, co crypto.CollectiveAuthority,
+	threshold int) (kyber.Point, error) {
 
 	if a.startRes.Done() {
 		return nil, xerrors.Errorf("startRes is already done, only one setup call is allowed")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
-	defer cancel()
 	ctx = context.WithValue(ctx, tracing.ProtocolKey, protocolNameSetup)
 
 	sender, receiver, err := a.rpc.Stream(ctx, co)
@@ -98,22 +161,9 @@ func (a *Actor) Setup(co crypto.CollectiveAuthority, threshold int) (kyber.Point
 		return nil, xerrors.Errorf("failed to stream: %v", err)
 	}
 
-	addrs := make([]mino.Address, 0, co.Len())
-	pubkeys := make([]kyber.Point, 0, co.Len())
-
-	addrIter := co.AddressIterator()
-	pubkeyIter := co.PublicKeyIterator()
-
-	for addrIter.HasNext() && pubkeyIter.HasNext() {
-		addrs = append(addrs, addrIter.GetNext())
-
-		pubkey := pubkeyIter.GetNext()
-		edKey, ok := pubkey.(ed25519.PublicKey)
-		if !ok {
-			return nil, xerrors.Errorf("expected ed25519.PublicKey, got '%T'", pubkey)
-		}
-
-		pubkeys = append(pubkeys, edKey.GetPoint())
+	addrs, pubkeys, err := readAuthority(co)
+	if err != nil {
+		return nil, err
 	}
 
 	message := types.NewStart(threshold, addrs, pubkeys)
@@ -128,7 +178,13 @@ func (a *Actor) Setup(co crypto.CollectiveAuthority, threshold int) (kyber.Point
 
 	for i := 0; i < len(addrs); i++ {
 
-		addr, msg, err := receiver.Recv(context.Background())
+		addr, msg, err := receiver.Recv(ctx)
+		if ctx.Err() != nil {
+			a.startRes.clearPartial()
+
+			return nil, xerrors.Errorf("setup aborted: %v", ctx.Err())
+		}
+
 		if err != nil {
 			return nil, xerrors.Errorf("got an error from '%s' while "+
 				"receiving: %v", addr, err)
@@ -162,6 +218,69 @@ func (a *Actor) GetPublicKey() (kyber.Point, error) {
 	return a.startRes.GetDistKey(), nil
 }
 
+// GetRemotePublicKey requests the distributed key to the node at the given
+// address. The node must have completed the setup.
+func (a *Actor) GetRemotePublicKey(addr mino.Address) (kyber.Point, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), publicKeyTimeout)
+	defer cancel()
+
+	resps, err := a.rpc.Call(ctx, types.NewPublicKeyRequest(), mino.NewAddresses(addr))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to call: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, xerrors.Errorf("no reply from '%v': %v", addr, ctx.Err())
+	case resp, more := <-resps:
+		if !more {
+			return nil, xerrors.Errorf("no reply from '%v'", addr)
+		}
+
+		msg, err := resp.GetMessageOrError()
+		if err != nil {
+			return nil, xerrors.Errorf("got an error from '%v': %v", addr, err)
+		}
+
+		reply, ok := msg.(types.PublicKeyReply)
+		if !ok {
+			return nil, xerrors.Errorf("unexpected reply of type '%T'", msg)
+		}
+
+		return reply.GetPublicKey(), nil
+	}
+}
+
+// Ping checks that the node at the given address is reachable by waiting for
+// its reply to a ping request. The node does not need to be set up.
+func (a *Actor) Ping(ctx context.Context, addr mino.Address) error {
+	resps, err := a.rpc.Call(ctx, types.NewPingRequest(), mino.NewAddresses(addr))
+	if err != nil {
+		return xerrors.Errorf("failed to call: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return xerrors.Errorf("no reply from '%v': %v", addr, ctx.Err())
+	case resp, more := <-resps:
+		if !more {
+			return xerrors.Errorf("no reply from '%v'", addr)
+		}
+
+		msg, err := resp.GetMessageOrError()
+		if err != nil {
+			return xerrors.Errorf("got an error from '%v': %v", addr, err)
+		}
+
+		_, ok := msg.(types.PingReply)
+		if !ok {
+			return xerrors.Errorf("unexpected reply of type '%T'", msg)
+		}
+
+		return nil
+	}
+}
+
 // Encrypt implements dkg.Actor. It uses the DKG public key to encrypt a
 // message.
 func (a *Actor) Encrypt(message []byte) (K, C kyber.Point, remainder []byte,
@@ -173,41 +292,87 @@ func (a *Actor) Encrypt(message []byte) (K, C kyber.Point, remainder []byte,
 	}
 
 	// Embed the message (or as much of it as will fit) into a curve point.
-	M := suite.Point().Embed(message, random.New())
-	max := suite.Point().EmbedLen()
+	M := a.suite.Point().Embed(message, random.New())
+	max := a.suite.Point().EmbedLen()
 	if max > len(message) {
 		max = len(message)
 	}
 	remainder = message[max:]
 	// ElGamal-encrypt the point to produce ciphertext (K,C).
-	k := suite.Scalar().Pick(random.New())             // ephemeral private key
-	K = suite.Point().Mul(k, nil)                      // ephemeral DH public key
-	S := suite.Point().Mul(k, a.startRes.GetDistKey()) // ephemeral DH shared secret
-	C = S.Add(S, M)                                    // message blinded with secret
+	k := a.suite.Scalar().Pick(random.New())             // ephemeral private key
+	K = a.suite.Point().Mul(k, nil)                      // ephemeral DH public key
+	S := a.suite.Point().Mul(k, a.startRes.GetDistKey()) // ephemeral DH shared secret
+	C = S.Add(S, M)                                      // message blinded with secret
 
 	return K, C, remainder, nil
 }
 
 // Decrypt implements dkg.Actor. It gets the private shares of the nodes and
-// decrypt the  message.
+// decrypt the  message. The message is recovered as soon as a threshold of
+// share-holders replied, and the others are ignored.
 // TODO: perform a re-encryption instead of gathering the private shares, which
 // should never happen.
 func (a *Actor) Decrypt(K, C kyber.Point) ([]byte, error) {
+	msg, _, err := a.decrypt(K, C, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// DecryptWithProof implements dkg.Actor. It decrypts the message and verifies
+// the partial decryptions of the share-holders, so that the invalid ones are
+// ignored. It returns the partial decryptions used to recover the message
+// alongside their proofs.
+func (a *Actor) DecryptWithProof(K, C kyber.Point) ([]byte, dkg.DecryptionProof, error) {
+	msg, partials, err := a.decrypt(K, C, true)
+	if err != nil {
+		return nil, dkg.DecryptionProof{}, err
+	}
+
+	proof := dkg.DecryptionProof{
+		Commits:  a.startRes.GetCommits(),
+		Partials: partials,
+	}
+
+	return msg, proof, nil
+}
+
+func (a *Actor) decrypt(K, C kyber.Point, verify bool) ([]byte,
+	[]dkg.PartialDecryption, error) {
 
 	if !a.startRes.Done() {
-		return nil, xerrors.Errorf("you must first initialize DKG. " +
+		return nil, nil, xerrors.Errorf("you must first initialize DKG. " +
 			"Did you call setup() first?")
 	}
 
+	var pubPoly *share.PubPoly
+
+	if verify {
+		commits := a.startRes.GetCommits()
+		if len(commits) == 0 {
+			return nil, nil, xerrors.New("missing the public commitments")
+		}
+
+		pubPoly = share.NewPubPoly(a.suite, nil, commits)
+	}
+
 	players := mino.NewAddresses(a.startRes.GetParticipants()...)
+	threshold := a.startRes.GetThreshold()
+
+	// The roster may have shrunk since the setup, in which case there is no
+	// point to even try.
+	if players.Len() < threshold {
+		return nil, nil, newThresholdError(players.Len(), threshold)
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), decryptTimeout)
+	ctx, cancel := a.newDecryptContext()
 	defer cancel()
-	ctx = context.WithValue(ctx, tracing.ProtocolKey, protocolNameDecrypt)
 
 	sender, receiver, err := a.rpc.Stream(ctx, players)
 	if err != nil {
-		return nil, xerrors.Errorf("failed to create stream: %v", err)
+		return nil, nil, xerrors.Errorf("failed to create stream: %v", err)
 	}
 
 	players = mino.NewAddresses(a.startRes.GetParticipants()...)
@@ -218,49 +383,377 @@ func (a *Actor) Decrypt(K, C kyber.Point) ([]byte, error) {
 		addrs = append(addrs, iterator.GetNext())
 	}
 
-	message := types.NewDecryptRequest(K, C)
+	available := sendToAll(sender, types.NewDecryptRequest(K, C), addrs)
 
-	err = <-sender.Send(message, addrs...)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to send decrypt request: %v", err)
+	if available < threshold {
+		return nil, nil, newThresholdError(available, threshold)
 	}
 
-	pubShares := make([]*share.PubShare, len(addrs))
+	pubShares := make([]*share.PubShare, 0, threshold)
+	partials := make([]dkg.PartialDecryption, 0, threshold)
+
+	// When the partial decryptions are verified, the invalid ones are ignored
+	// and the replies of the other share-holders are used instead.
+	for received := 0; len(pubShares) < threshold; received++ {
+		if received >= available {
+			return nil, nil, xerrors.Errorf("cannot decrypt: only %d valid "+
+				"partial decryption(s) of required %d", len(pubShares), threshold)
+		}
+
+		from, message, err := receiver.Recv(ctx)
+		if err != nil && ctx.Err() != nil {
+			return []byte{}, nil, xerrors.Errorf("cannot decrypt: only %d "+
+				"partial decryption(s) of required %d received before the deadline",
+				len(pubShares), threshold)
+		}
 
-	for i := 0; i < len(addrs); i++ {
-		_, message, err := receiver.Recv(ctx)
 		if err != nil {
-			return []byte{}, xerrors.Errorf("stream stopped unexpectedly: %v", err)
+			return []byte{}, nil, xerrors.Errorf("stream stopped unexpectedly: %v", err)
 		}
 
 		decryptReply, ok := message.(types.DecryptReply)
 		if !ok {
-			return []byte{}, xerrors.Errorf("got unexpected reply, expected "+
+			return []byte{}, nil, xerrors.Errorf("got unexpected reply, expected "+
 				"%T but got: %T", decryptReply, message)
 		}
 
-		pubShares[i] = &share.PubShare{
-			I: int(decryptReply.I),
-			V: decryptReply.V,
+		partial := dkg.PartialDecryption{
+			Index: int(decryptReply.I),
+			V:     decryptReply.V,
+			Proof: decryptReply.GetProof(),
 		}
+
+		if verify {
+			err = verifyPartial(a.suite, pubPoly, K, C, partial)
+			if err != nil {
+				logger.Warn().Err(err).Stringer("addr", from).Msg("invalid partial decryption")
+				continue
+			}
+
+			partials = append(partials, partial)
+		}
+
+		pubShares = append(pubShares, &share.PubShare{
+			I: partial.Index,
+			V: partial.V,
+		})
 	}
 
-	res, err := share.RecoverCommit(suite, pubShares, len(addrs), len(addrs))
+	res, err := share.RecoverCommit(a.suite, pubShares, threshold, len(addrs))
 	if err != nil {
-		return []byte{}, xerrors.Errorf("failed to recover commit: %v", err)
+		return []byte{}, nil, xerrors.Errorf("failed to recover commit: %v", err)
 	}
 
 	decryptedMessage, err := res.Data()
 	if err != nil {
-		return []byte{}, xerrors.Errorf("failed to get embeded data: %v", err)
+		return []byte{}, nil, xerrors.Errorf("failed to get embeded data: %v", err)
+	}
+
+	return decryptedMessage, partials, nil
+}
+
+// DecryptBatch implements dkg.Actor. It sends all the ciphertexts to the
+// share-holders in a single request so that the partial decryptions are
+// collected once for the whole batch. A ciphertext that cannot be decrypted
+// does not prevent the others to be, and it is reported in a dkg.BatchError.
+func (a *Actor) DecryptBatch(cts []dkg.Ciphertext) ([][]byte, error) {
+	if !a.startRes.Done() {
+		return nil, xerrors.Errorf("you must first initialize DKG. " +
+			"Did you call setup() first?")
+	}
+
+	msgs := make([][]byte, len(cts))
+	failures := make(dkg.BatchError)
+
+	// Only the complete ciphertexts are sent to the share-holders, and the
+	// indices are kept to report the result at the right place.
+	indices := make([]int, 0, len(cts))
+	ks := make([]kyber.Point, 0, len(cts))
+	cs := make([]kyber.Point, 0, len(cts))
+
+	for i, ct := range cts {
+		if ct.K == nil || ct.C == nil {
+			failures[i] = xerrors.New("incomplete ciphertext")
+			continue
+		}
+
+		indices = append(indices, i)
+		ks = append(ks, ct.K)
+		cs = append(cs, ct.C)
+	}
+
+	if len(indices) > 0 {
+		replies, n, err := a.collectBatch(ks, cs)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, index := range indices {
+			pubShares := make([]*share.PubShare, len(replies))
+			for k, reply := range replies {
+				pubShares[k] = &share.PubShare{
+					I: int(reply.GetI()),
+					V: reply.GetVs()[j],
+				}
+			}
+
+			res, err := share.RecoverCommit(a.suite, pubShares, len(replies), n)
+			if err != nil {
+				failures[index] = xerrors.Errorf("failed to recover commit: %v", err)
+				continue
+			}
+
+			msgs[index], err = res.Data()
+			if err != nil {
+				failures[index] = xerrors.Errorf("failed to get embeded data: %v", err)
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return msgs, failures
+	}
+
+	return msgs, nil
+}
+
+// collectBatch sends the batch decryption request to the share-holders and
+// returns a threshold of replies, alongside the number of share-holders.
+func (a *Actor) collectBatch(ks, cs []kyber.Point) ([]types.DecryptBatchReply, int, error) {
+	players := mino.NewAddresses(a.startRes.GetParticipants()...)
+	threshold := a.startRes.GetThreshold()
+
+	if players.Len() < threshold {
+		return nil, 0, newThresholdError(players.Len(), threshold)
+	}
+
+	ctx, cancel := a.newDecryptContext()
+	defer cancel()
+
+	sender, receiver, err := a.rpc.Stream(ctx, players)
+	if err != nil {
+		return nil, 0, xerrors.Errorf("failed to create stream: %v", err)
 	}
 
-	return decryptedMessage, nil
+	addrs := make([]mino.Address, 0, players.Len())
+
+	iter := players.AddressIterator()
+	for iter.HasNext() {
+		addrs = append(addrs, iter.GetNext())
+	}
+
+	available := sendToAll(sender, types.NewDecryptBatchRequest(ks, cs), addrs)
+
+	if available < threshold {
+		return nil, 0, newThresholdError(available, threshold)
+	}
+
+	replies := make([]types.DecryptBatchReply, 0, threshold)
+
+	for received := 0; len(replies) < threshold; received++ {
+		if received >= available {
+			return nil, 0, xerrors.Errorf("cannot decrypt: only %d valid "+
+				"reply(ies) of required %d", len(replies), threshold)
+		}
+
+		from, msg, err := receiver.Recv(ctx)
+		if err != nil && ctx.Err() != nil {
+			return nil, 0, xerrors.Errorf("cannot decrypt: only %d reply(ies) "+
+				"of required %d received before the deadline", len(replies), threshold)
+		}
+
+		if err != nil {
+			return nil, 0, xerrors.Errorf("stream stopped unexpectedly: %v", err)
+		}
+
+		reply, ok := msg.(types.DecryptBatchReply)
+		if !ok {
+			return nil, 0, xerrors.Errorf("got unexpected reply, expected "+
+				"%T but got: %T", reply, msg)
+		}
+
+		if len(reply.GetVs()) != len(ks) {
+			logger.Warn().Stringer("addr", from).Msg("incomplete batch reply")
+			continue
+		}
+
+		replies = append(replies, reply)
+	}
+
+	return replies, players.Len(), nil
 }
 
-// Reshare implements dkg.Actor. It recreates the DKG with an updated list of
-// participants.
-// TODO: to do
-func (a *Actor) Reshare() error {
+// newDecryptContext returns the context of a decryption, which is done when the
+// timeout is reached.
+func (a *Actor) newDecryptContext() (context.Context, context.CancelFunc) {
+	timeout := a.decryptTimeout
+	if timeout <= 0 {
+		timeout = decryptTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx = context.WithValue(ctx, tracing.ProtocolKey, protocolNameDecrypt)
+
+	return ctx, cancel
+}
+
+// sendToAll sends the request to each share-holder concurrently, so that a
+// slow one does not delay the others, and returns the number of share-holders
+// the request has been sent to.
+func sendToAll(sender mino.Sender, msg serde.Message, addrs []mino.Address) int {
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+
+	available := 0
+
+	for _, addr := range addrs {
+		wg.Add(1)
+
+		go func(addr mino.Address) {
+			defer wg.Done()
+
+			err := <-sender.Send(msg, addr)
+			if err != nil {
+				logger.Warn().Err(err).Stringer("addr", addr).Msg("share-holder unavailable")
+				return
+			}
+
+			lock.Lock()
+			available++
+			lock.Unlock()
+		}(addr)
+	}
+
+	wg.Wait()
+
+	return available
+}
+
+// GetThreshold returns the number of share-holders required to decrypt a
+// message, or zero if the setup has not been done.
+func (a *Actor) GetThreshold() int {
+	return a.startRes.GetThreshold()
+}
+
+// GetParticipants returns the addresses of the share-holders.
+func (a *Actor) GetParticipants() []mino.Address {
+	return a.startRes.GetParticipants()
+}
+
+// Reshare implements dkg.Actor. It distributes new shares of the same
+// distributed key to the collective authority, with a new threshold. The
+// previous share-holders deal their shares to the new ones, and the ones that
+// are not part of the new authority drop their share. The public key does not
+// change so that the messages encrypted before the resharing can still be
+// decrypted.
+func (a *Actor) Reshare(co crypto.CollectiveAuthority, thresholdNew int) error {
+	if !a.startRes.Done() {
+		return xerrors.Errorf("you must first initialize DKG. Did you call " +
+			"setup() first?")
+	}
+
+	if thresholdNew < 1 || thresholdNew > co.Len() {
+		return xerrors.Errorf("invalid threshold %d for %d share-holder(s)",
+			thresholdNew, co.Len())
+	}
+
+	addrsNew, pubkeysNew, err := readAuthority(co)
+	if err != nil {
+		return err
+	}
+
+	addrsOld := a.startRes.GetParticipants()
+
+	// The resharing involves both the previous and the new share-holders.
+	players := append([]mino.Address{}, addrsOld...)
+	for _, addr := range addrsNew {
+		if !containsAddress(players, addr) {
+			players = append(players, addr)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, tracing.ProtocolKey, protocolNameReshare)
+
+	sender, receiver, err := a.rpc.Stream(ctx, mino.NewAddresses(players...))
+	if err != nil {
+		return xerrors.Errorf("failed to stream: %v", err)
+	}
+
+	message := types.NewStartResharing(thresholdNew, a.startRes.GetThreshold(),
+		addrsNew, pubkeysNew, addrsOld, a.startRes.GetPublicKeys(),
+		a.startRes.GetCommits())
+
+	err = <-sender.Send(message, players...)
+	if err != nil {
+		return xerrors.Errorf("failed to send resharing: %v", err)
+	}
+
+	pubkey := a.startRes.GetDistKey()
+
+	for i := 0; i < len(players); i++ {
+		addr, msg, err := receiver.Recv(ctx)
+		if err != nil {
+			return xerrors.Errorf("got an error from '%s' while "+
+				"receiving: %v", addr, err)
+		}
+
+		doneMsg, ok := msg.(types.StartDone)
+		if !ok {
+			return xerrors.Errorf("expected to receive a Done message, but "+
+				"go the following: %T", msg)
+		}
+
+		if !pubkey.Equal(doneMsg.GetPublicKey()) {
+			return xerrors.Errorf("the public key of '%s' has changed: %v",
+				addr, doneMsg.GetPublicKey())
+		}
+	}
+
 	return nil
 }
+
+// pointKey is a public key that exposes its point, whatever its suite is.
+type pointKey interface {
+	GetPoint() kyber.Point
+}
+
+// readAuthority returns the addresses and the public keys of the collective
+// authority.
+func readAuthority(co crypto.CollectiveAuthority) ([]mino.Address, []kyber.Point, error) {
+	addrs := make([]mino.Address, 0, co.Len())
+	pubkeys := make([]kyber.Point, 0, co.Len())
+
+	addrIter := co.AddressIterator()
+	pubkeyIter := co.PublicKeyIterator()
+
+	for addrIter.HasNext() && pubkeyIter.HasNext() {
+		addrs = append(addrs, addrIter.GetNext())
+
+		pubkey := pubkeyIter.GetNext()
+		key, ok := pubkey.(pointKey)
+		if !ok {
+			return nil, nil, xerrors.Errorf("expected a public key with a point, got '%T'", pubkey)
+		}
+
+		pubkeys = append(pubkeys, key.GetPoint())
+	}
+
+	return addrs, pubkeys, nil
+}
+
+func containsAddress(addrs []mino.Address, addr mino.Address) bool {
+	for _, a := range addrs {
+		if a.Equal(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newThresholdError(available, threshold int) error {
+	return xerrors.Errorf("cannot decrypt: only %d of required %d share-holders available",
+		available, threshold)
+}
@@ -1,9 +1,12 @@
 package pedersen
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/crypto/ed25519"
 	"go.dedis.ch/dela/dkg"
@@ -12,11 +15,14 @@ import (
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/mino/minogrpc"
 	"go.dedis.ch/dela/mino/router/tree"
+	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/suites"
 )
 
 func TestPedersen_Listen(t *testing.T) {
-	pedersen, _ := NewPedersen(fake.Mino{})
+	pedersen, pubkey := NewPedersen(fake.Mino{})
+	require.True(t, pubkey.Equal(pedersen.GetPublicKey()))
 
 	actor, err := pedersen.Listen()
 	require.NoError(t, err)
@@ -24,8 +30,23 @@ func TestPedersen_Listen(t *testing.T) {
 	require.NotNil(t, actor)
 }
 
+func TestPedersen_WithSuite(t *testing.T) {
+	p256 := suites.MustFind("P256")
+
+	pedersen, pubkey := NewPedersen(fake.Mino{}, WithSuite(p256))
+	require.Equal(t, p256, pedersen.GetSuite())
+	require.True(t, pubkey.Equal(pedersen.GetPublicKey()))
+	require.Equal(t, p256.PointLen(), pubkey.MarshalSize())
+
+	actor, err := pedersen.Listen()
+	require.NoError(t, err)
+	require.Equal(t, p256, actor.(*Actor).suite)
+	require.Equal(t, p256, actor.(*Actor).handler.suite)
+}
+
 func TestPedersen_Setup(t *testing.T) {
 	actor := Actor{
+		suite:    suite,
 		rpc:      fake.NewBadRPC(),
 		startRes: &state{},
 	}
@@ -39,7 +60,7 @@ func TestPedersen_Setup(t *testing.T) {
 	actor.rpc = rpc
 
 	_, err = actor.Setup(fakeAuthority, 0)
-	require.EqualError(t, err, "expected ed25519.PublicKey, got 'fake.PublicKey'")
+	require.EqualError(t, err, "expected a public key with a point, got 'fake.PublicKey'")
 
 	rpc = fake.NewStreamRPC(fake.NewBadReceiver(), fake.Sender{})
 	actor.rpc = rpc
@@ -68,8 +89,49 @@ func TestPedersen_Setup(t *testing.T) {
 	require.Regexp(t, "^the public keys does not match:", err)
 }
 
+func TestPedersen_Timeout_Setup(t *testing.T) {
+	// The members never respond.
+	rpc := fake.NewStreamRPC(fake.NewBlockingReceiver(), fake.Sender{})
+
+	actor := Actor{
+		suite:    suite,
+		rpc:      rpc,
+		startRes: &state{},
+	}
+
+	fakeAuthority := fake.NewAuthority(2, ed25519.NewSigner)
+
+	// The local handler has started the protocol but has not completed it.
+	actor.startRes.SetParticipants([]mino.Address{fake.NewAddress(0)})
+	actor.startRes.SetThreshold(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	_, err := actor.SetupContext(ctx, fakeAuthority, 2)
+	require.EqualError(t, err, "setup aborted: context deadline exceeded")
+	require.Less(t, int64(time.Since(start)), int64(5*time.Second))
+	require.Nil(t, actor.startRes.GetParticipants())
+	require.Equal(t, 0, actor.startRes.GetThreshold())
+
+	// A retry can succeed once the members respond.
+	pubkey := suite.Point().Pick(suite.RandomStream())
+
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewStartDone(pubkey)),
+		fake.NewRecvMsg(fake.NewAddress(1), types.NewStartDone(pubkey)),
+	), fake.Sender{})
+
+	res, err := actor.Setup(fakeAuthority, 2)
+	require.NoError(t, err)
+	require.True(t, pubkey.Equal(res))
+}
+
 func TestPedersen_GetPublicKey(t *testing.T) {
 	actor := Actor{
+		suite:    suite,
 		startRes: &state{},
 	}
 
@@ -81,8 +143,86 @@ func TestPedersen_GetPublicKey(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPedersen_GetRemotePublicKey(t *testing.T) {
+	actor := Actor{
+		suite: suite,
+		rpc:   fake.NewBadRPC(),
+	}
+
+	addr := fake.NewAddress(1)
+
+	_, err := actor.GetRemotePublicKey(addr)
+	require.EqualError(t, err, fake.Err("failed to call"))
+
+	pubkey := suite.Point().Pick(suite.RandomStream())
+
+	rpc := fake.NewRPC()
+	rpc.SendResponse(addr, types.NewPublicKeyReply(pubkey))
+	actor.rpc = rpc
+
+	res, err := actor.GetRemotePublicKey(addr)
+	require.NoError(t, err)
+	require.True(t, pubkey.Equal(res))
+	require.Equal(t, 1, rpc.Calls.Len())
+	require.Equal(t, types.NewPublicKeyRequest(), rpc.Calls.Get(0, 1))
+
+	rpc.SendResponseWithError(addr, fake.GetError())
+	_, err = actor.GetRemotePublicKey(addr)
+	require.EqualError(t, err, fake.Err("got an error from 'fake.Address[1]'"))
+
+	rpc.SendResponse(addr, fake.Message{})
+	_, err = actor.GetRemotePublicKey(addr)
+	require.EqualError(t, err, "unexpected reply of type 'fake.Message'")
+
+	rpc.Done()
+	_, err = actor.GetRemotePublicKey(addr)
+	require.EqualError(t, err, "no reply from 'fake.Address[1]'")
+}
+
+func TestPedersen_Ping(t *testing.T) {
+	actor := Actor{
+		suite: suite,
+		rpc:   fake.NewBadRPC(),
+	}
+
+	addr := fake.NewAddress(1)
+
+	err := actor.Ping(context.Background(), addr)
+	require.EqualError(t, err, fake.Err("failed to call"))
+
+	rpc := fake.NewRPC()
+	rpc.SendResponse(addr, types.NewPingReply())
+	actor.rpc = rpc
+
+	err = actor.Ping(context.Background(), addr)
+	require.NoError(t, err)
+	require.Equal(t, 1, rpc.Calls.Len())
+	require.Equal(t, types.NewPingRequest(), rpc.Calls.Get(0, 1))
+
+	rpc.SendResponseWithError(addr, fake.GetError())
+	err = actor.Ping(context.Background(), addr)
+	require.EqualError(t, err, fake.Err("got an error from 'fake.Address[1]'"))
+
+	rpc.SendResponse(addr, fake.Message{})
+	err = actor.Ping(context.Background(), addr)
+	require.EqualError(t, err, "unexpected reply of type 'fake.Message'")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	actor.rpc = fake.NewRPC()
+	err = actor.Ping(ctx, addr)
+	require.EqualError(t, err, "no reply from 'fake.Address[1]': context canceled")
+
+	rpc.Done()
+	actor.rpc = rpc
+	err = actor.Ping(context.Background(), addr)
+	require.EqualError(t, err, "no reply from 'fake.Address[1]'")
+}
+
 func TestPedersen_Decrypt(t *testing.T) {
 	actor := Actor{
+		suite:    suite,
 		rpc:      fake.NewBadRPC(),
 		startRes: &state{participants: []mino.Address{fake.NewAddress(0)}, distrKey: suite.Point()},
 	}
@@ -94,7 +234,8 @@ func TestPedersen_Decrypt(t *testing.T) {
 	actor.rpc = rpc
 
 	_, err = actor.Decrypt(suite.Point(), suite.Point())
-	require.EqualError(t, err, fake.Err("failed to send decrypt request"))
+	require.EqualError(t, err,
+		"cannot decrypt: only 0 of required 1 share-holders available")
 
 	recv := fake.NewReceiver(fake.NewRecvMsg(fake.NewAddress(0), nil))
 
@@ -126,9 +267,272 @@ func TestPedersen_Decrypt(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPedersen_DecryptWithProof(t *testing.T) {
+	message := []byte("Hello world")
+	pubkey, K, C, proof := makeDecryptionProof(t, message, 2, 3)
+
+	participants := []mino.Address{fake.NewAddress(0), fake.NewAddress(1), fake.NewAddress(2)}
+
+	actor := Actor{
+		suite: suite,
+		startRes: &state{
+			participants: participants,
+			distrKey:     pubkey,
+			threshold:    2,
+		},
+	}
+
+	_, _, err := actor.DecryptWithProof(K, C)
+	require.EqualError(t, err, "missing the public commitments")
+
+	actor.startRes.SetCommits(proof.Commits)
+
+	toReply := func(partial dkg.PartialDecryption) types.DecryptReply {
+		return types.NewVerifiableDecryptReply(int64(partial.Index), partial.V, partial.Proof)
+	}
+
+	// The first share-holder is malicious and replies with the partial
+	// decryption of another share-holder.
+	malicious := proof.Partials[1]
+	malicious.Index = proof.Partials[0].Index
+
+	recv := fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), toReply(malicious)),
+		fake.NewRecvMsg(fake.NewAddress(1), toReply(proof.Partials[1])),
+		fake.NewRecvMsg(fake.NewAddress(2), toReply(proof.Partials[2])),
+	)
+
+	actor.rpc = fake.NewStreamRPC(recv, fake.Sender{})
+
+	msg, res, err := actor.DecryptWithProof(K, C)
+	require.NoError(t, err)
+	require.Equal(t, message, msg)
+	require.Len(t, res.Partials, 2)
+	require.Equal(t, 1, res.Partials[0].Index)
+	require.Equal(t, 2, res.Partials[1].Index)
+
+	verified, err := VerifyDecryption(pubkey, K, C, res)
+	require.NoError(t, err)
+	require.Equal(t, message, verified)
+
+	recv = fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), toReply(malicious)),
+		fake.NewRecvMsg(fake.NewAddress(1), types.NewDecryptReply(1, proof.Partials[1].V)),
+		fake.NewRecvMsg(fake.NewAddress(2), toReply(proof.Partials[2])),
+	)
+
+	actor.rpc = fake.NewStreamRPC(recv, fake.Sender{})
+
+	_, _, err = actor.DecryptWithProof(K, C)
+	require.EqualError(t, err,
+		"cannot decrypt: only 1 valid partial decryption(s) of required 2")
+}
+
+func TestPedersen_BelowThreshold_Decrypt(t *testing.T) {
+	participants := []mino.Address{fake.NewAddress(0), fake.NewAddress(1), fake.NewAddress(2)}
+
+	actor := Actor{
+		suite:    suite,
+		startRes: &state{participants: participants, distrKey: suite.Point(), threshold: 2},
+	}
+
+	recv := fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.DecryptReply{I: 0, V: suite.Point()}),
+		fake.NewRecvMsg(fake.NewAddress(1), types.DecryptReply{I: 1, V: suite.Point()}),
+	)
+
+	// One share-holder is unavailable but the threshold is still reached.
+	actor.rpc = fakeRPC{sender: newBadAddrSender(fake.NewAddress(2)), receiver: recv}
+
+	_, err := actor.Decrypt(suite.Point(), suite.Point())
+	require.NoError(t, err)
+
+	actor.rpc = fakeRPC{
+		sender:   newBadAddrSender(fake.NewAddress(1), fake.NewAddress(2)),
+		receiver: fake.NewReceiver(),
+	}
+
+	_, err = actor.Decrypt(suite.Point(), suite.Point())
+	require.EqualError(t, err,
+		"cannot decrypt: only 1 of required 2 share-holders available")
+
+	// The roster has shrunk below the threshold.
+	actor.startRes.SetParticipants(participants[:1])
+
+	_, err = actor.Decrypt(suite.Point(), suite.Point())
+	require.EqualError(t, err,
+		"cannot decrypt: only 1 of required 2 share-holders available")
+}
+
+func TestPedersen_Silent_Decrypt(t *testing.T) {
+	participants := []mino.Address{fake.NewAddress(0), fake.NewAddress(1), fake.NewAddress(2)}
+
+	actor := Actor{
+		suite:          suite,
+		startRes:       &state{participants: participants, distrKey: suite.Point(), threshold: 2},
+		decryptTimeout: 50 * time.Millisecond,
+	}
+
+	// The third share-holder never replies but the threshold is reached
+	// without waiting for it.
+	actor.rpc = fakeRPC{
+		sender: fake.Sender{},
+		receiver: newSilentReceiver(
+			fake.NewRecvMsg(fake.NewAddress(0), types.DecryptReply{I: 0, V: suite.Point()}),
+			fake.NewRecvMsg(fake.NewAddress(1), types.DecryptReply{I: 1, V: suite.Point()}),
+		),
+	}
+
+	_, err := actor.Decrypt(suite.Point(), suite.Point())
+	require.NoError(t, err)
+
+	actor.rpc = fakeRPC{
+		sender: fake.Sender{},
+		receiver: newSilentReceiver(
+			fake.NewRecvMsg(fake.NewAddress(0), types.DecryptReply{I: 0, V: suite.Point()}),
+		),
+	}
+
+	_, err = actor.Decrypt(suite.Point(), suite.Point())
+	require.EqualError(t, err, "cannot decrypt: only 1 partial decryption(s) "+
+		"of required 2 received before the deadline")
+
+	actor.rpc = fakeRPC{sender: fake.Sender{}, receiver: newSilentReceiver()}
+
+	_, err = actor.DecryptBatch([]dkg.Ciphertext{{K: suite.Point(), C: suite.Point()}})
+	require.EqualError(t, err, "cannot decrypt: only 0 reply(ies) of required 2 "+
+		"received before the deadline")
+}
+
+func TestPedersen_WithDecryptTimeout(t *testing.T) {
+	p, _ := NewPedersen(fake.Mino{}, WithDecryptTimeout(time.Second))
+	require.Equal(t, time.Second, p.decryptTimeout)
+
+	p, _ = NewPedersen(fake.Mino{})
+	require.Equal(t, decryptTimeout, p.decryptTimeout)
+}
+
+func TestPedersen_DecryptBatch(t *testing.T) {
+	actor := Actor{startRes: &state{}, suite: suite}
+
+	_, err := actor.DecryptBatch(nil)
+	require.EqualError(t, err, "you must first initialize DKG. Did you call setup() first?")
+
+	actor.startRes = &state{
+		participants: []mino.Address{fake.NewAddress(0)},
+		distrKey:     suite.Point(),
+		threshold:    1,
+	}
+	actor.rpc = fake.NewBadRPC()
+
+	cts := []dkg.Ciphertext{{K: suite.Point(), C: suite.Point()}}
+
+	_, err = actor.DecryptBatch(cts)
+	require.EqualError(t, err, fake.Err("failed to create stream"))
+
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(), fake.NewBadSender())
+
+	_, err = actor.DecryptBatch(cts)
+	require.EqualError(t, err,
+		"cannot decrypt: only 0 of required 1 share-holders available")
+
+	recv := fake.NewReceiver(fake.NewRecvMsg(fake.NewAddress(0), nil))
+	actor.rpc = fake.NewStreamRPC(recv, fake.Sender{})
+
+	_, err = actor.DecryptBatch(cts)
+	require.EqualError(t, err,
+		"got unexpected reply, expected types.DecryptBatchReply but got: <nil>")
+
+	// A reply without a partial decryption for each ciphertext is ignored.
+	recv = fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewDecryptBatchReply(0, nil)),
+	)
+	actor.rpc = fake.NewStreamRPC(recv, fake.Sender{})
+
+	_, err = actor.DecryptBatch(cts)
+	require.EqualError(t, err,
+		"cannot decrypt: only 0 valid reply(ies) of required 1")
+
+	// The incomplete ciphertext is reported without failing the others.
+	M := suite.Point().Embed([]byte("A"), suite.RandomStream())
+
+	recv = fake.NewReceiver(
+		fake.NewRecvMsg(fake.NewAddress(0), types.NewDecryptBatchReply(0, []kyber.Point{M})),
+	)
+	actor.rpc = fake.NewStreamRPC(recv, fake.Sender{})
+
+	cts = append(cts, dkg.Ciphertext{K: suite.Point()})
+
+	msgs, err := actor.DecryptBatch(cts)
+	require.EqualError(t, err,
+		"failed to decrypt 1 ciphertext(s): [1]: incomplete ciphertext")
+	require.Equal(t, [][]byte{[]byte("A"), nil}, msgs)
+
+	// No request is sent when none of the ciphertexts is complete.
+	actor.rpc = fake.NewBadRPC()
+
+	_, err = actor.DecryptBatch(cts[1:])
+	require.EqualError(t, err,
+		"failed to decrypt 1 ciphertext(s): [0]: incomplete ciphertext")
+}
+
+func TestPedersen_GetThreshold(t *testing.T) {
+	actor := Actor{startRes: &state{}, suite: suite}
+	require.Equal(t, 0, actor.GetThreshold())
+	require.Empty(t, actor.GetParticipants())
+
+	actor.startRes.SetParticipants([]mino.Address{fake.NewAddress(0), fake.NewAddress(1)})
+	require.Equal(t, 2, actor.GetThreshold())
+	require.Len(t, actor.GetParticipants(), 2)
+
+	actor.startRes.SetThreshold(1)
+	require.Equal(t, 1, actor.GetThreshold())
+}
+
 func TestPedersen_Reshare(t *testing.T) {
-	actor := Actor{}
-	actor.Reshare()
+	actor := Actor{
+		suite:    suite,
+		rpc:      fake.NewBadRPC(),
+		startRes: &state{},
+	}
+
+	fakeAuthority := fake.NewAuthority(2, ed25519.NewSigner)
+
+	err := actor.Reshare(fakeAuthority, 1)
+	require.EqualError(t, err, "you must first initialize DKG. Did you call setup() first?")
+
+	actor.startRes.SetDistKey(suite.Point())
+	actor.startRes.SetParticipants([]mino.Address{fake.NewAddress(0)})
+
+	err = actor.Reshare(fakeAuthority, 3)
+	require.EqualError(t, err, "invalid threshold 3 for 2 share-holder(s)")
+
+	err = actor.Reshare(fake.NewAuthority(1, fake.NewSigner), 1)
+	require.EqualError(t, err, "expected a public key with a point, got 'fake.PublicKey'")
+
+	err = actor.Reshare(fakeAuthority, 1)
+	require.EqualError(t, err, fake.Err("failed to stream"))
+
+	actor.rpc = fake.NewStreamRPC(fake.NewReceiver(), fake.NewBadSender())
+	err = actor.Reshare(fakeAuthority, 1)
+	require.EqualError(t, err, fake.Err("failed to send resharing"))
+
+	actor.rpc = fake.NewStreamRPC(fake.NewBadReceiver(), fake.Sender{})
+	err = actor.Reshare(fakeAuthority, 1)
+	require.EqualError(t, err, fake.Err("got an error from '%!s(<nil>)' while receiving"))
+
+	recv := fake.NewReceiver(fake.NewRecvMsg(fake.NewAddress(0), fake.Message{}))
+	actor.rpc = fake.NewStreamRPC(recv, fake.Sender{})
+	err = actor.Reshare(fakeAuthority, 1)
+	require.EqualError(t, err,
+		"expected to receive a Done message, but go the following: fake.Message")
+
+	recv = fake.NewReceiver(fake.NewRecvMsg(fake.NewAddress(0),
+		types.NewStartDone(suite.Point().Pick(suite.RandomStream()))))
+	actor.rpc = fake.NewStreamRPC(recv, fake.Sender{})
+	err = actor.Reshare(fakeAuthority, 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "the public key of 'fake.Address[0]' has changed: ")
 }
 
 func TestPedersen_Scenario(t *testing.T) {
@@ -188,6 +592,12 @@ func TestPedersen_Scenario(t *testing.T) {
 	_, err = actors[0].Decrypt(nil, nil)
 	require.EqualError(t, err, "you must first initialize DKG. Did you call setup() first?")
 
+	// every node is reachable before the setup
+	for _, addr := range addrs {
+		err = actors[0].(*Actor).Ping(context.Background(), addr)
+		require.NoError(t, err)
+	}
+
 	_, err = actors[0].Setup(fakeAuthority, n)
 	require.NoError(t, err)
 
@@ -202,7 +612,209 @@ func TestPedersen_Scenario(t *testing.T) {
 		decrypted, err := actors[i].Decrypt(K, C)
 		require.NoError(t, err)
 		require.Equal(t, message, decrypted)
+
+		decrypted, proof, err := actors[i].DecryptWithProof(K, C)
+		require.NoError(t, err)
+		require.Equal(t, message, decrypted)
+
+		pubkey, err := actors[i].GetPublicKey()
+		require.NoError(t, err)
+
+		verified, err := VerifyDecryption(pubkey, K, C, proof)
+		require.NoError(t, err)
+		require.Equal(t, message, verified)
+
+		// The distributed key is the same when requested by a peer.
+		remote, err := actors[(i+1)%n].(*Actor).GetRemotePublicKey(addrs[i])
+		require.NoError(t, err)
+		require.True(t, pubkey.Equal(remote))
+	}
+
+	// A batch is decrypted at once, and the invalid ciphertexts are reported
+	// without failing the others.
+	pubkey, err := actors[0].GetPublicKey()
+	require.NoError(t, err)
+
+	batch := make([]dkg.Ciphertext, 0, 4)
+	for i := 0; i < 2; i++ {
+		K, C, _, err := actors[0].Encrypt([]byte{byte(i)})
+		require.NoError(t, err)
+
+		batch = append(batch, dkg.Ciphertext{K: K, C: C})
+	}
+
+	batch = append(batch, dkg.Ciphertext{K: suite.Point().Base()}, makeInvalidCiphertext(pubkey))
+
+	msgs, err := actors[1].DecryptBatch(batch)
+	require.IsType(t, dkg.BatchError{}, err)
+	require.Len(t, err.(dkg.BatchError), 2)
+	require.Contains(t, err.(dkg.BatchError), 2)
+	require.Contains(t, err.(dkg.BatchError), 3)
+	require.Equal(t, []byte{0}, msgs[0])
+	require.Equal(t, []byte{1}, msgs[1])
+}
+
+func TestPedersen_Reshare_Scenario(t *testing.T) {
+	n := 6
+
+	minos := make([]mino.Mino, n)
+	dkgs := make([]dkg.DKG, n)
+	addrs := make([]mino.Address, n)
+
+	for i := 0; i < n; i++ {
+		addr := minogrpc.ParseAddress("127.0.0.1", 0)
+
+		minogrpc, err := minogrpc.NewMinogrpc(addr, tree.NewRouter(minogrpc.NewAddressFactory()))
+		require.NoError(t, err)
+
+		defer minogrpc.GracefulStop()
+
+		minos[i] = minogrpc
+		addrs[i] = minogrpc.GetAddress()
+	}
+
+	pubkeys := make([]kyber.Point, len(minos))
+
+	for i, mino := range minos {
+		for _, m := range minos {
+			mino.(*minogrpc.Minogrpc).GetCertificateStore().Store(m.GetAddress(), m.(*minogrpc.Minogrpc).GetCertificate())
+		}
+
+		dkg, pubkey := NewPedersen(mino.(*minogrpc.Minogrpc))
+
+		dkgs[i] = dkg
+		pubkeys[i] = pubkey
+	}
+
+	actors := make([]dkg.Actor, n)
+	for i := 0; i < n; i++ {
+		actor, err := dkgs[i].Listen()
+		require.NoError(t, err)
+
+		actors[i] = actor
+	}
+
+	// The first four nodes hold the shares, then the two first ones are
+	// replaced by the two last ones.
+	oldAuthority := NewAuthority(addrs[:4], pubkeys[:4])
+	newAuthority := NewAuthority(addrs[2:], pubkeys[2:])
+
+	pubkey, err := actors[0].Setup(oldAuthority, 3)
+	require.NoError(t, err)
+
+	message := []byte("Hello world")
+
+	K, C, _, err := actors[0].Encrypt(message)
+	require.NoError(t, err)
+
+	err = actors[0].Reshare(newAuthority, 2)
+	require.NoError(t, err)
+
+	// every node, including the ones that left, should still be able to
+	// decrypt the message encrypted before the resharing
+	for i := 0; i < n; i++ {
+		newPubkey, err := actors[i].GetPublicKey()
+		require.NoError(t, err)
+		require.True(t, pubkey.Equal(newPubkey))
+
+		decrypted, err := actors[i].Decrypt(K, C)
+		require.NoError(t, err)
+		require.Equal(t, message, decrypted)
+	}
+
+	require.Equal(t, 2, actors[5].(*Actor).GetThreshold())
+	require.Equal(t, addrs[2:], actors[5].(*Actor).GetParticipants())
+
+	// the new share-holders can reshare again
+	err = actors[5].Reshare(NewAuthority(addrs[3:], pubkeys[3:]), 3)
+	require.NoError(t, err)
+
+	decrypted, err := actors[3].Decrypt(K, C)
+	require.NoError(t, err)
+	require.Equal(t, message, decrypted)
+}
+
+func TestPedersen_Suite_Scenario(t *testing.T) {
+	n := 3
+
+	p256 := suites.MustFind("P256")
+
+	minos := make([]*minogrpc.Minogrpc, n)
+	addrs := make([]mino.Address, n)
+
+	for i := 0; i < n; i++ {
+		addr := minogrpc.ParseAddress("127.0.0.1", 0)
+
+		minogrpc, err := minogrpc.NewMinogrpc(addr, tree.NewRouter(minogrpc.NewAddressFactory()))
+		require.NoError(t, err)
+
+		defer minogrpc.GracefulStop()
+
+		minos[i] = minogrpc
+		addrs[i] = minogrpc.GetAddress()
+	}
+
+	pubkeys := make([]kyber.Point, n)
+	actors := make([]dkg.Actor, n)
+
+	for i, m := range minos {
+		for _, other := range minos {
+			m.GetCertificateStore().Store(other.GetAddress(), other.GetCertificate())
+		}
+
+		dkg, pubkey := NewPedersen(m, WithSuite(p256))
+		pubkeys[i] = pubkey
+
+		actor, err := dkg.Listen()
+		require.NoError(t, err)
+
+		actors[i] = actor
 	}
+
+	pubkey, err := actors[0].Setup(NewAuthority(addrs, pubkeys), n)
+	require.NoError(t, err)
+	require.Equal(t, p256.PointLen(), pubkey.MarshalSize())
+
+	message := []byte("Hello world")
+
+	K, C, remainder, err := actors[1].Encrypt(message)
+	require.NoError(t, err)
+	require.Len(t, remainder, 0)
+
+	decrypted, proof, err := actors[2].DecryptWithProof(K, C)
+	require.NoError(t, err)
+	require.Equal(t, message, decrypted)
+
+	verified, err := VerifyDecryptionWithSuite(p256, pubkey, K, C, proof)
+	require.NoError(t, err)
+	require.Equal(t, message, verified)
+
+	msgs, err := actors[0].DecryptBatch([]dkg.Ciphertext{{K: K, C: C}})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{message}, msgs)
+}
+
+func TestReadAuthority(t *testing.T) {
+	p256 := suites.MustFind("P256")
+
+	points := []kyber.Point{
+		p256.Point().Pick(p256.RandomStream()),
+		p256.Point().Pick(p256.RandomStream()),
+	}
+
+	// Any public key exposing its point is accepted, whatever its suite.
+	co := authority.New(
+		[]mino.Address{fake.NewAddress(0), fake.NewAddress(1)},
+		[]crypto.PublicKey{pointPublicKey{point: points[0]}, pointPublicKey{point: points[1]}},
+	)
+
+	addrs, pubkeys, err := readAuthority(co)
+	require.NoError(t, err)
+	require.Len(t, addrs, 2)
+	require.Equal(t, points, pubkeys)
+
+	_, _, err = readAuthority(fake.NewAuthority(1, fake.NewSigner))
+	require.EqualError(t, err, "expected a public key with a point, got 'fake.PublicKey'")
 }
 
 // -----------------------------------------------------------------------------
@@ -279,3 +891,97 @@ type fakeSigner struct {
 func (s fakeSigner) GetPublicKey() crypto.PublicKey {
 	return ed25519.NewPublicKeyFromPoint(s.pubkey)
 }
+
+type pointPublicKey struct {
+	crypto.PublicKey
+
+	point kyber.Point
+}
+
+func (pk pointPublicKey) GetPoint() kyber.Point {
+	return pk.point
+}
+
+// badAddrSender is a sender that fails to send to some of the addresses.
+type badAddrSender struct {
+	mino.Sender
+
+	bad []mino.Address
+}
+
+func newBadAddrSender(bad ...mino.Address) badAddrSender {
+	return badAddrSender{bad: bad}
+}
+
+func (s badAddrSender) Send(msg serde.Message, addrs ...mino.Address) <-chan error {
+	errs := make(chan error, 1)
+
+	for _, addr := range addrs {
+		for _, bad := range s.bad {
+			if addr.Equal(bad) {
+				errs <- fake.GetError()
+			}
+		}
+	}
+
+	close(errs)
+
+	return errs
+}
+
+// silentReceiver is a receiver that returns the messages, and then waits for
+// the context to be done as if the other share-holders never replied.
+type silentReceiver struct {
+	mino.Receiver
+
+	msgs []fake.ReceiverMessage
+}
+
+func newSilentReceiver(msgs ...fake.ReceiverMessage) *silentReceiver {
+	return &silentReceiver{msgs: msgs}
+}
+
+func (r *silentReceiver) Recv(ctx context.Context) (mino.Address, serde.Message, error) {
+	if len(r.msgs) == 0 {
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	}
+
+	msg := r.msgs[0]
+	r.msgs = r.msgs[1:]
+
+	return msg.Address, msg.Message, nil
+}
+
+type fakeRPC struct {
+	mino.RPC
+
+	sender   mino.Sender
+	receiver mino.Receiver
+}
+
+func (rpc fakeRPC) Stream(context.Context, mino.Players) (mino.Sender, mino.Receiver, error) {
+	return rpc.sender, rpc.receiver, nil
+}
+
+// makeInvalidCiphertext returns a ciphertext of the distributed key that does
+// not embed any data.
+func makeInvalidCiphertext(pubkey kyber.Point) dkg.Ciphertext {
+	M := suite.Point().Pick(suite.RandomStream())
+
+	for {
+		_, err := M.Data()
+		if err != nil {
+			break
+		}
+
+		M = suite.Point().Pick(suite.RandomStream())
+	}
+
+	k := suite.Scalar().Pick(suite.RandomStream())
+
+	return dkg.Ciphertext{
+		K: suite.Point().Mul(k, nil),
+		C: suite.Point().Add(suite.Point().Mul(k, pubkey), M),
+	}
+}
@@ -0,0 +1,187 @@
+// This file contains the export and the import of the share of a node.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package pedersen
+
+import (
+	"encoding/json"
+	"io"
+
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	pedersen "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v3/suites"
+	"golang.org/x/xerrors"
+)
+
+// shareFile is the description of the share of a node and of the group it
+// belongs to. The binary values are encoded in base64 by the JSON encoder so
+// that the share is never written as raw bytes.
+type shareFile struct {
+	Index        int
+	Share        []byte
+	Commits      [][]byte
+	Threshold    int
+	Participants [][]byte
+	PublicKeys   [][]byte
+}
+
+// Export writes the share of the node, alongside the metadata of the group, so
+// that it can be imported after a restart. The output is sensitive as it
+// contains the private share.
+func (a *Actor) Export(w io.Writer) error {
+	if !a.startRes.Done() {
+		return xerrors.Errorf("DKG has not been initialized")
+	}
+
+	a.handler.RLock()
+	distShare := a.handler.distShare
+	a.handler.RUnlock()
+
+	if distShare == nil {
+		return xerrors.New("no share to export")
+	}
+
+	buffer, err := distShare.Share.V.MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal share: %v", err)
+	}
+
+	file := shareFile{
+		Index:     distShare.Share.I,
+		Share:     buffer,
+		Threshold: a.startRes.GetThreshold(),
+	}
+
+	file.Commits, err = marshalPoints(distShare.Commits)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal commits: %v", err)
+	}
+
+	file.PublicKeys, err = marshalPoints(a.startRes.GetPublicKeys())
+	if err != nil {
+		return xerrors.Errorf("failed to marshal public keys: %v", err)
+	}
+
+	for _, addr := range a.startRes.GetParticipants() {
+		buffer, err := addr.MarshalText()
+		if err != nil {
+			return xerrors.Errorf("failed to marshal address: %v", err)
+		}
+
+		file.Participants = append(file.Participants, buffer)
+	}
+
+	err = json.NewEncoder(w).Encode(file)
+	if err != nil {
+		return xerrors.Errorf("failed to encode: %v", err)
+	}
+
+	return nil
+}
+
+// Import reads a share previously exported and restores the state of the DKG
+// so that the node can participate to the decryptions again. It fails if the
+// DKG is already set up.
+func (a *Actor) Import(r io.Reader) error {
+	if a.startRes.Done() {
+		return xerrors.New("DKG is already set up")
+	}
+
+	var file shareFile
+
+	err := json.NewDecoder(r).Decode(&file)
+	if err != nil {
+		return xerrors.Errorf("failed to decode: %v", err)
+	}
+
+	if len(file.Commits) == 0 {
+		return xerrors.New("missing the public commitments")
+	}
+
+	if len(file.Participants) != len(file.PublicKeys) {
+		return xerrors.Errorf("there should be as many players as "+
+			"pubKey: %d := %d", len(file.Participants), len(file.PublicKeys))
+	}
+
+	priShare := &share.PriShare{
+		I: file.Index,
+		V: a.suite.Scalar(),
+	}
+
+	err = priShare.V.UnmarshalBinary(file.Share)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal share: %v", err)
+	}
+
+	commits, err := unmarshalPoints(a.suite, file.Commits)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal commits: %v", err)
+	}
+
+	pubkeys, err := unmarshalPoints(a.suite, file.PublicKeys)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal public keys: %v", err)
+	}
+
+	// The public share of the node is checked against the commitments so that
+	// a corrupted file is detected now rather than during a decryption.
+	pubPoly := share.NewPubPoly(a.suite, nil, commits)
+	if !pubPoly.Check(priShare) {
+		return xerrors.New("share does not match the commitments")
+	}
+
+	participants := make([]mino.Address, len(file.Participants))
+	for i, buffer := range file.Participants {
+		participants[i] = a.addrFactory.FromText(buffer)
+	}
+
+	a.handler.Lock()
+	a.handler.privShare = priShare
+	a.handler.distShare = &pedersen.DistKeyShare{
+		Commits: commits,
+		Share:   priShare,
+	}
+	a.handler.Unlock()
+
+	a.startRes.SetThreshold(file.Threshold)
+	a.startRes.SetPublicKeys(pubkeys)
+	a.startRes.SetCommits(commits)
+	a.startRes.SetDistKey(commits[0])
+	a.startRes.SetParticipants(participants)
+
+	return nil
+}
+
+func marshalPoints(points []kyber.Point) ([][]byte, error) {
+	buffers := make([][]byte, len(points))
+
+	for i, point := range points {
+		buffer, err := point.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		buffers[i] = buffer
+	}
+
+	return buffers, nil
+}
+
+func unmarshalPoints(suite suites.Suite, buffers [][]byte) ([]kyber.Point, error) {
+	points := make([]kyber.Point, len(buffers))
+
+	for i, buffer := range buffers {
+		points[i] = suite.Point()
+
+		err := points[i].UnmarshalBinary(buffer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return points, nil
+}
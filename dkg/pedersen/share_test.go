@@ -0,0 +1,185 @@
+package pedersen
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/internal/testing/fake"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	pedersen "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+)
+
+func TestActor_Export(t *testing.T) {
+	actor := makeSharedActor(t)
+
+	buffer := new(bytes.Buffer)
+	err := actor.Export(buffer)
+	require.NoError(t, err)
+
+	imported := &Actor{
+		suite:       suite,
+		addrFactory: fake.AddressFactory{},
+		handler:     &Handler{suite: suite},
+		startRes:    &state{},
+	}
+
+	err = imported.Import(buffer)
+	require.NoError(t, err)
+
+	pubkey, err := imported.GetPublicKey()
+	require.NoError(t, err)
+	require.True(t, pubkey.Equal(actor.startRes.GetDistKey()))
+	require.Equal(t, 2, imported.GetThreshold())
+	require.Equal(t, actor.GetParticipants(), imported.GetParticipants())
+	require.Len(t, imported.startRes.GetPublicKeys(), 3)
+	require.Equal(t, actor.handler.privShare.I, imported.handler.privShare.I)
+	require.True(t, actor.handler.privShare.V.Equal(imported.handler.privShare.V))
+	require.Len(t, imported.handler.distShare.Commits, 2)
+}
+
+func TestActor_NoShare_Export(t *testing.T) {
+	actor := &Actor{
+		suite:    suite,
+		handler:  &Handler{suite: suite},
+		startRes: &state{},
+	}
+
+	err := actor.Export(new(bytes.Buffer))
+	require.EqualError(t, err, "DKG has not been initialized")
+
+	actor.startRes.SetDistKey(suite.Point())
+	actor.startRes.SetParticipants([]mino.Address{fake.NewAddress(0)})
+
+	err = actor.Export(new(bytes.Buffer))
+	require.EqualError(t, err, "no share to export")
+
+	actor = makeSharedActor(t)
+
+	err = actor.Export(fake.NewBadHash())
+	require.EqualError(t, err, fake.Err("failed to encode"))
+
+	actor.startRes.SetParticipants([]mino.Address{fake.NewBadAddress()})
+
+	err = actor.Export(new(bytes.Buffer))
+	require.EqualError(t, err, fake.Err("failed to marshal address"))
+}
+
+func TestActor_Import(t *testing.T) {
+	actor := makeSharedActor(t)
+
+	err := actor.Import(new(bytes.Buffer))
+	require.EqualError(t, err, "DKG is already set up")
+
+	actor = &Actor{
+		suite:       suite,
+		addrFactory: fake.AddressFactory{},
+		handler:     &Handler{suite: suite},
+		startRes:    &state{},
+	}
+
+	err = actor.Import(bytes.NewBufferString("{"))
+	require.EqualError(t, err, "failed to decode: unexpected EOF")
+
+	file := exportShare(t)
+	file.Commits = nil
+
+	err = actor.Import(encodeShare(t, file))
+	require.EqualError(t, err, "missing the public commitments")
+
+	file = exportShare(t)
+	file.PublicKeys = nil
+
+	err = actor.Import(encodeShare(t, file))
+	require.EqualError(t, err, "there should be as many players as pubKey: 3 := 0")
+
+	file = exportShare(t)
+	file.Share = []byte{1}
+
+	err = actor.Import(encodeShare(t, file))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to unmarshal share: ")
+
+	file = exportShare(t)
+	file.Commits = [][]byte{{1}}
+
+	err = actor.Import(encodeShare(t, file))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to unmarshal commits: ")
+
+	file = exportShare(t)
+	file.PublicKeys[0] = []byte{1}
+
+	err = actor.Import(encodeShare(t, file))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to unmarshal public keys: ")
+
+	file = exportShare(t)
+	file.Index++
+
+	err = actor.Import(encodeShare(t, file))
+	require.EqualError(t, err, "share does not match the commitments")
+
+	require.False(t, actor.startRes.Done())
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func makeSharedActor(t *testing.T) *Actor {
+	priPoly := share.NewPriPoly(suite, 2, nil, suite.RandomStream())
+	_, commits := priPoly.Commit(nil).Info()
+
+	distShare := &pedersen.DistKeyShare{
+		Commits: commits,
+		Share:   priPoly.Shares(3)[1],
+	}
+
+	pubkeys := make([]kyber.Point, 3)
+	for i := range pubkeys {
+		pubkeys[i] = suite.Point().Pick(suite.RandomStream())
+	}
+
+	actor := &Actor{
+		suite:       suite,
+		addrFactory: fake.AddressFactory{},
+		handler: &Handler{
+			suite:     suite,
+			privShare: distShare.Share,
+			distShare: distShare,
+		},
+		startRes: &state{},
+	}
+
+	actor.startRes.SetThreshold(2)
+	actor.startRes.SetPublicKeys(pubkeys)
+	actor.startRes.SetCommits(commits)
+	actor.startRes.SetDistKey(commits[0])
+	actor.startRes.SetParticipants([]mino.Address{
+		fake.NewAddress(0), fake.NewAddress(1), fake.NewAddress(2),
+	})
+
+	return actor
+}
+
+func exportShare(t *testing.T) shareFile {
+	buffer := new(bytes.Buffer)
+
+	err := makeSharedActor(t).Export(buffer)
+	require.NoError(t, err)
+
+	var file shareFile
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &file))
+
+	return file
+}
+
+func encodeShare(t *testing.T, file shareFile) *bytes.Buffer {
+	data, err := json.Marshal(file)
+	require.NoError(t, err)
+
+	return bytes.NewBuffer(data)
+}
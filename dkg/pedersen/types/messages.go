@@ -5,6 +5,8 @@ import (
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/dela/serde/registry"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/suites"
 	"golang.org/x/xerrors"
 )
 
@@ -65,6 +67,86 @@ func (s Start) Serialize(ctx serde.Context) ([]byte, error) {
 	return data, nil
 }
 
+// StartResharing is the message the initiator of the resharing protocol sends
+// to the old and the new share-holders.
+//
+// - implements serde.Message
+type StartResharing struct {
+	thresholdNew int
+	thresholdOld int
+	addrsNew     []mino.Address
+	pubkeysNew   []kyber.Point
+	addrsOld     []mino.Address
+	pubkeysOld   []kyber.Point
+	// commits are the public coefficients of the distributed polynomial that
+	// the new share-holders need to verify their shares.
+	commits []kyber.Point
+}
+
+// NewStartResharing creates a new start resharing message.
+func NewStartResharing(thresholdNew, thresholdOld int, addrsNew []mino.Address,
+	pubkeysNew []kyber.Point, addrsOld []mino.Address, pubkeysOld []kyber.Point,
+	commits []kyber.Point) StartResharing {
+
+	return StartResharing{
+		thresholdNew: thresholdNew,
+		thresholdOld: thresholdOld,
+		addrsNew:     addrsNew,
+		pubkeysNew:   pubkeysNew,
+		addrsOld:     addrsOld,
+		pubkeysOld:   pubkeysOld,
+		commits:      commits,
+	}
+}
+
+// GetThresholdNew returns the threshold of the new share-holders.
+func (s StartResharing) GetThresholdNew() int {
+	return s.thresholdNew
+}
+
+// GetThresholdOld returns the threshold of the old share-holders.
+func (s StartResharing) GetThresholdOld() int {
+	return s.thresholdOld
+}
+
+// GetAddrsNew returns the addresses of the new share-holders.
+func (s StartResharing) GetAddrsNew() []mino.Address {
+	return append([]mino.Address{}, s.addrsNew...)
+}
+
+// GetPubkeysNew returns the public keys of the new share-holders.
+func (s StartResharing) GetPubkeysNew() []kyber.Point {
+	return append([]kyber.Point{}, s.pubkeysNew...)
+}
+
+// GetAddrsOld returns the addresses of the old share-holders.
+func (s StartResharing) GetAddrsOld() []mino.Address {
+	return append([]mino.Address{}, s.addrsOld...)
+}
+
+// GetPubkeysOld returns the public keys of the old share-holders.
+func (s StartResharing) GetPubkeysOld() []kyber.Point {
+	return append([]kyber.Point{}, s.pubkeysOld...)
+}
+
+// GetCommits returns the public coefficients of the distributed polynomial.
+func (s StartResharing) GetCommits() []kyber.Point {
+	return append([]kyber.Point{}, s.commits...)
+}
+
+// Serialize implements serde.Message. It looks up the format and returns the
+// serialized data for the start resharing message.
+func (s StartResharing) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, s)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode message: %v", err)
+	}
+
+	return data, nil
+}
+
 // EncryptedDeal contains the different parameters and data of an encrypted
 // deal.
 type EncryptedDeal struct {
@@ -261,6 +343,104 @@ func (s StartDone) Serialize(ctx serde.Context) ([]byte, error) {
 	return data, nil
 }
 
+// PublicKeyRequest is a message sent to request the distributed key of a node.
+//
+// - implements serde.Message
+type PublicKeyRequest struct{}
+
+// NewPublicKeyRequest creates a new request for the distributed key.
+func NewPublicKeyRequest() PublicKeyRequest {
+	return PublicKeyRequest{}
+}
+
+// Serialize implements serde.Message.
+func (req PublicKeyRequest) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, req)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode public key request: %v", err)
+	}
+
+	return data, nil
+}
+
+// PublicKeyReply is the response of a request for the distributed key.
+//
+// - implements serde.Message
+type PublicKeyReply struct {
+	pubkey kyber.Point
+}
+
+// NewPublicKeyReply creates a new reply with the distributed key.
+func NewPublicKeyReply(pubkey kyber.Point) PublicKeyReply {
+	return PublicKeyReply{
+		pubkey: pubkey,
+	}
+}
+
+// GetPublicKey returns the distributed key.
+func (resp PublicKeyReply) GetPublicKey() kyber.Point {
+	return resp.pubkey
+}
+
+// Serialize implements serde.Message.
+func (resp PublicKeyReply) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, resp)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode public key reply: %v", err)
+	}
+
+	return data, nil
+}
+
+// PingRequest is a message sent to check that a node is reachable. It does not
+// require the DKG to be set up.
+//
+// - implements serde.Message
+type PingRequest struct{}
+
+// NewPingRequest creates a new ping request.
+func NewPingRequest() PingRequest {
+	return PingRequest{}
+}
+
+// Serialize implements serde.Message.
+func (req PingRequest) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, req)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode ping request: %v", err)
+	}
+
+	return data, nil
+}
+
+// PingReply is the response of a ping request.
+//
+// - implements serde.Message
+type PingReply struct{}
+
+// NewPingReply creates a new ping reply.
+func NewPingReply() PingReply {
+	return PingReply{}
+}
+
+// Serialize implements serde.Message.
+func (resp PingReply) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, resp)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode ping reply: %v", err)
+	}
+
+	return data, nil
+}
+
 // DecryptRequest is a message sent to request a decryption.
 //
 // - implements serde.Message
@@ -303,8 +483,9 @@ func (req DecryptRequest) Serialize(ctx serde.Context) ([]byte, error) {
 //
 // - implements serde.Message
 type DecryptReply struct {
-	V kyber.Point
-	I int64
+	V     kyber.Point
+	I     int64
+	Proof *dleq.Proof
 }
 
 // NewDecryptReply returns a new decryption reply.
@@ -315,6 +496,16 @@ func NewDecryptReply(i int64, v kyber.Point) DecryptReply {
 	}
 }
 
+// NewVerifiableDecryptReply returns a new decryption reply with the proof that
+// the partial decryption has been computed with the private share of the node.
+func NewVerifiableDecryptReply(i int64, v kyber.Point, proof *dleq.Proof) DecryptReply {
+	return DecryptReply{
+		I:     i,
+		V:     v,
+		Proof: proof,
+	}
+}
+
 // GetV returns V.
 func (resp DecryptReply) GetV() kyber.Point {
 	return resp.V
@@ -325,6 +516,12 @@ func (resp DecryptReply) GetI() int64 {
 	return resp.I
 }
 
+// GetProof returns the proof of the partial decryption, or nil if it is not
+// defined.
+func (resp DecryptReply) GetProof() *dleq.Proof {
+	return resp.Proof
+}
+
 // Serialize implements serde.Message.
 func (resp DecryptReply) Serialize(ctx serde.Context) ([]byte, error) {
 	format := msgFormats.Get(ctx.GetFormat())
@@ -337,28 +534,128 @@ func (resp DecryptReply) Serialize(ctx serde.Context) ([]byte, error) {
 	return data, nil
 }
 
+// DecryptBatchRequest is a message sent to request the partial decryptions of
+// several ciphertexts at once.
+//
+// - implements serde.Message
+type DecryptBatchRequest struct {
+	Ks []kyber.Point
+	Cs []kyber.Point
+}
+
+// NewDecryptBatchRequest creates a new request for the decryption of the
+// ciphertexts (K, C) at the same index of both lists.
+func NewDecryptBatchRequest(ks, cs []kyber.Point) DecryptBatchRequest {
+	return DecryptBatchRequest{
+		Ks: ks,
+		Cs: cs,
+	}
+}
+
+// GetKs returns the list of K.
+func (req DecryptBatchRequest) GetKs() []kyber.Point {
+	return append([]kyber.Point{}, req.Ks...)
+}
+
+// GetCs returns the list of C.
+func (req DecryptBatchRequest) GetCs() []kyber.Point {
+	return append([]kyber.Point{}, req.Cs...)
+}
+
+// Serialize implements serde.Message.
+func (req DecryptBatchRequest) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, req)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode decrypt batch request: %v", err)
+	}
+
+	return data, nil
+}
+
+// DecryptBatchReply is the response of a batch decryption request. It contains
+// the partial decryption of each ciphertext in the order of the request.
+//
+// - implements serde.Message
+type DecryptBatchReply struct {
+	I  int64
+	Vs []kyber.Point
+}
+
+// NewDecryptBatchReply returns a new batch decryption reply.
+func NewDecryptBatchReply(i int64, vs []kyber.Point) DecryptBatchReply {
+	return DecryptBatchReply{
+		I:  i,
+		Vs: vs,
+	}
+}
+
+// GetI returns I.
+func (resp DecryptBatchReply) GetI() int64 {
+	return resp.I
+}
+
+// GetVs returns the partial decryptions.
+func (resp DecryptBatchReply) GetVs() []kyber.Point {
+	return append([]kyber.Point{}, resp.Vs...)
+}
+
+// Serialize implements serde.Message.
+func (resp DecryptBatchReply) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, resp)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode decrypt batch reply: %v", err)
+	}
+
+	return data, nil
+}
+
 // AddrKey is the key for the address factory.
 type AddrKey struct{}
 
+// SuiteKey is the key for the factory of the Kyber suite.
+type SuiteKey struct{}
+
+// SuiteFactory is the interface of a factory that provides the Kyber suite the
+// points of the messages belong to.
+type SuiteFactory interface {
+	serde.Factory
+
+	GetSuite() suites.Suite
+}
+
 // MessageFactory is a message factory for the different DKG messages.
 //
-// - implements serde.Factory
+// - implements types.SuiteFactory
 type MessageFactory struct {
 	addrFactory mino.AddressFactory
+	suite       suites.Suite
 }
 
-// NewMessageFactory returns a message factory for the DKG.
-func NewMessageFactory(f mino.AddressFactory) MessageFactory {
+// NewMessageFactory returns a message factory for the DKG that decodes the
+// points of the given suite.
+func NewMessageFactory(f mino.AddressFactory, suite suites.Suite) MessageFactory {
 	return MessageFactory{
 		addrFactory: f,
+		suite:       suite,
 	}
 }
 
+// GetSuite implements types.SuiteFactory. It returns the Kyber suite of the
+// DKG.
+func (f MessageFactory) GetSuite() suites.Suite {
+	return f.suite
+}
+
 // Deserialize implements serde.Factory.
 func (f MessageFactory) Deserialize(ctx serde.Context, data []byte) (serde.Message, error) {
 	format := msgFormats.Get(ctx.GetFormat())
 
 	ctx = serde.WithFactory(ctx, AddrKey{}, f.addrFactory)
+	ctx = serde.WithFactory(ctx, SuiteKey{}, f)
 
 	msg, err := format.Decode(ctx, data)
 	if err != nil {
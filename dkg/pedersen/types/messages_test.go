@@ -10,6 +10,8 @@ import (
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/suites"
 )
 
 var testCalls = &fake.Call{}
@@ -48,6 +50,36 @@ func TestStart_Serialize(t *testing.T) {
 	require.EqualError(t, err, fake.Err("couldn't encode message"))
 }
 
+func TestStartResharing_Getters(t *testing.T) {
+	start := NewStartResharing(
+		2, 3,
+		[]mino.Address{fake.NewAddress(0)},
+		[]kyber.Point{nil},
+		[]mino.Address{fake.NewAddress(1), fake.NewAddress(2)},
+		[]kyber.Point{nil, nil},
+		[]kyber.Point{nil, nil, nil},
+	)
+
+	require.Equal(t, 2, start.GetThresholdNew())
+	require.Equal(t, 3, start.GetThresholdOld())
+	require.Len(t, start.GetAddrsNew(), 1)
+	require.Len(t, start.GetPubkeysNew(), 1)
+	require.Len(t, start.GetAddrsOld(), 2)
+	require.Len(t, start.GetPubkeysOld(), 2)
+	require.Len(t, start.GetCommits(), 3)
+}
+
+func TestStartResharing_Serialize(t *testing.T) {
+	start := StartResharing{}
+
+	data, err := start.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = start.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode message"))
+}
+
 func TestEncryptedDeal_Getters(t *testing.T) {
 	f := func(key, sig, nonce, cipher []byte) bool {
 		e := NewEncryptedDeal(key, sig, nonce, cipher)
@@ -164,6 +196,56 @@ func TestStartDone_Serialize(t *testing.T) {
 	require.EqualError(t, err, fake.Err("couldn't encode ack"))
 }
 
+func TestPublicKeyRequest_Serialize(t *testing.T) {
+	req := NewPublicKeyRequest()
+
+	data, err := req.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = req.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode public key request"))
+}
+
+func TestPublicKeyReply_GetPublicKey(t *testing.T) {
+	resp := NewPublicKeyReply(fakePoint{})
+
+	require.Equal(t, fakePoint{}, resp.GetPublicKey())
+}
+
+func TestPublicKeyReply_Serialize(t *testing.T) {
+	resp := PublicKeyReply{}
+
+	data, err := resp.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = resp.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode public key reply"))
+}
+
+func TestPingRequest_Serialize(t *testing.T) {
+	req := NewPingRequest()
+
+	data, err := req.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = req.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode ping request"))
+}
+
+func TestPingReply_Serialize(t *testing.T) {
+	resp := NewPingReply()
+
+	data, err := resp.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = resp.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode ping reply"))
+}
+
 func TestDecryptRequest_GetK(t *testing.T) {
 	req := NewDecryptRequest(fakePoint{}, nil)
 
@@ -199,6 +281,18 @@ func TestDecryptReply_GetI(t *testing.T) {
 	require.Equal(t, int64(1), resp.GetI())
 }
 
+func TestDecryptReply_GetProof(t *testing.T) {
+	resp := NewDecryptReply(1, nil)
+	require.Nil(t, resp.GetProof())
+
+	proof := &dleq.Proof{}
+
+	resp = NewVerifiableDecryptReply(1, fakePoint{}, proof)
+	require.Equal(t, int64(1), resp.GetI())
+	require.Equal(t, fakePoint{}, resp.GetV())
+	require.Same(t, proof, resp.GetProof())
+}
+
 func TestDecryptReply_Serialize(t *testing.T) {
 	resp := DecryptReply{}
 
@@ -210,8 +304,46 @@ func TestDecryptReply_Serialize(t *testing.T) {
 	require.EqualError(t, err, fake.Err("couldn't encode decrypt reply"))
 }
 
+func TestDecryptBatchRequest_Getters(t *testing.T) {
+	req := NewDecryptBatchRequest([]kyber.Point{fakePoint{}}, []kyber.Point{fakePoint{}, nil})
+
+	require.Equal(t, []kyber.Point{fakePoint{}}, req.GetKs())
+	require.Equal(t, []kyber.Point{fakePoint{}, nil}, req.GetCs())
+}
+
+func TestDecryptBatchRequest_Serialize(t *testing.T) {
+	req := DecryptBatchRequest{}
+
+	data, err := req.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = req.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode decrypt batch request"))
+}
+
+func TestDecryptBatchReply_Getters(t *testing.T) {
+	resp := NewDecryptBatchReply(2, []kyber.Point{fakePoint{}})
+
+	require.Equal(t, int64(2), resp.GetI())
+	require.Equal(t, []kyber.Point{fakePoint{}}, resp.GetVs())
+}
+
+func TestDecryptBatchReply_Serialize(t *testing.T) {
+	resp := DecryptBatchReply{}
+
+	data, err := resp.Serialize(fake.NewContext())
+	require.NoError(t, err)
+	require.Equal(t, fake.GetFakeFormatValue(), data)
+
+	_, err = resp.Serialize(fake.NewBadContext())
+	require.EqualError(t, err, fake.Err("couldn't encode decrypt batch reply"))
+}
+
 func TestMessageFactory(t *testing.T) {
-	factory := NewMessageFactory(fake.AddressFactory{})
+	suite := suites.MustFind("P256")
+	factory := NewMessageFactory(fake.AddressFactory{}, suite)
+	require.Equal(t, suite, factory.GetSuite())
 
 	testCalls.Clear()
 
@@ -222,6 +354,7 @@ func TestMessageFactory(t *testing.T) {
 	require.Equal(t, 1, testCalls.Len())
 	ctx := testCalls.Get(0, 0).(serde.Context)
 	require.Equal(t, fake.AddressFactory{}, ctx.GetFactory(AddrKey{}))
+	require.Equal(t, factory, ctx.GetFactory(SuiteKey{}))
 
 	_, err = factory.Deserialize(fake.NewBadContext(), nil)
 	require.EqualError(t, err, fake.Err("couldn't decode message"))
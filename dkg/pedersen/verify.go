@@ -0,0 +1,103 @@
+// This file contains the verification of the decryption proofs.
+//
+// Documentation Last Review: 15.10.2026
+//
+
+package pedersen
+
+import (
+	"go.dedis.ch/dela/dkg"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/suites"
+	"golang.org/x/xerrors"
+)
+
+// VerifyDecryption verifies the proof of the decryption of the ciphertext (K,
+// C) for the distributed key, and returns the message recovered from the
+// partial decryptions. The caller is expected to compare it with the claimed
+// plaintext. It fails if any of the partial decryptions is invalid.
+func VerifyDecryption(pubkey, K, C kyber.Point, proof dkg.DecryptionProof) ([]byte, error) {
+	return VerifyDecryptionWithSuite(suite, pubkey, K, C, proof)
+}
+
+// VerifyDecryptionWithSuite is the same as VerifyDecryption for a DKG that uses
+// the given Kyber suite.
+func VerifyDecryptionWithSuite(suite suites.Suite, pubkey, K, C kyber.Point,
+	proof dkg.DecryptionProof) ([]byte, error) {
+
+	if len(proof.Commits) == 0 {
+		return nil, xerrors.New("missing the public commitments")
+	}
+
+	if !proof.Commits[0].Equal(pubkey) {
+		return nil, xerrors.New("commitments do not match the public key")
+	}
+
+	// The threshold is the number of coefficients of the public polynomial.
+	threshold := len(proof.Commits)
+
+	if len(proof.Partials) < threshold {
+		return nil, xerrors.Errorf("not enough partial decryptions: %d < %d",
+			len(proof.Partials), threshold)
+	}
+
+	pubPoly := share.NewPubPoly(suite, nil, proof.Commits)
+
+	indices := make(map[int]struct{})
+	pubShares := make([]*share.PubShare, len(proof.Partials))
+
+	for i, partial := range proof.Partials {
+		_, found := indices[partial.Index]
+		if found {
+			return nil, xerrors.Errorf("duplicate partial decryption %d", partial.Index)
+		}
+
+		indices[partial.Index] = struct{}{}
+
+		err := verifyPartial(suite, pubPoly, K, C, partial)
+		if err != nil {
+			return nil, xerrors.Errorf("partial decryption %d: %v", partial.Index, err)
+		}
+
+		pubShares[i] = &share.PubShare{I: partial.Index, V: partial.V}
+	}
+
+	res, err := share.RecoverCommit(suite, pubShares, threshold, len(pubShares))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to recover commit: %v", err)
+	}
+
+	msg, err := res.Data()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get embeded data: %v", err)
+	}
+
+	return msg, nil
+}
+
+// verifyPartial verifies that the partial decryption has been computed with the
+// private share that matches the public share of the share-holder.
+func verifyPartial(suite suites.Suite, pubPoly *share.PubPoly, K, C kyber.Point,
+	partial dkg.PartialDecryption) error {
+
+	if partial.Proof == nil {
+		return xerrors.New("missing proof")
+	}
+
+	if partial.V == nil {
+		return xerrors.New("missing partial decryption")
+	}
+
+	pubShare := pubPoly.Eval(partial.Index)
+
+	// The partial decryption is V = C - xK, thus xK = C - V.
+	S := suite.Point().Sub(C, partial.V)
+
+	err := partial.Proof.Verify(suite, suite.Point().Base(), K, pubShare.V, S)
+	if err != nil {
+		return xerrors.Errorf("invalid proof: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package pedersen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/dkg"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestVerifyDecryption(t *testing.T) {
+	message := []byte("Hello world")
+
+	pubkey, K, C, proof := makeDecryptionProof(t, message, 2, 3)
+
+	msg, err := VerifyDecryption(pubkey, K, C, proof)
+	require.NoError(t, err)
+	require.Equal(t, message, msg)
+
+	// A partial decryption computed with another share is rejected even if its
+	// proof is valid for that other share.
+	swapped := copyProof(proof)
+	swapped.Partials[0].Index = proof.Partials[1].Index
+	swapped.Partials[1].Index = proof.Partials[0].Index
+
+	_, err = VerifyDecryption(pubkey, K, C, swapped)
+	require.EqualError(t, err, "partial decryption 1: invalid proof: invalid proof")
+
+	tampered := copyProof(proof)
+	tampered.Partials[2].V = suite.Point().Pick(suite.RandomStream())
+
+	_, err = VerifyDecryption(pubkey, K, C, tampered)
+	require.EqualError(t, err, "partial decryption 2: invalid proof: invalid proof")
+}
+
+func TestVerifyDecryption_Invalid(t *testing.T) {
+	pubkey, K, C, proof := makeDecryptionProof(t, []byte("abc"), 2, 3)
+
+	_, err := VerifyDecryption(pubkey, K, C, dkg.DecryptionProof{})
+	require.EqualError(t, err, "missing the public commitments")
+
+	_, err = VerifyDecryption(suite.Point(), K, C, proof)
+	require.EqualError(t, err, "commitments do not match the public key")
+
+	invalid := copyProof(proof)
+	invalid.Partials = invalid.Partials[:1]
+
+	_, err = VerifyDecryption(pubkey, K, C, invalid)
+	require.EqualError(t, err, "not enough partial decryptions: 1 < 2")
+
+	invalid = copyProof(proof)
+	invalid.Partials[1] = invalid.Partials[0]
+
+	_, err = VerifyDecryption(pubkey, K, C, invalid)
+	require.EqualError(t, err, "duplicate partial decryption 0")
+
+	invalid = copyProof(proof)
+	invalid.Partials[0].Proof = nil
+
+	_, err = VerifyDecryption(pubkey, K, C, invalid)
+	require.EqualError(t, err, "partial decryption 0: missing proof")
+
+	invalid = copyProof(proof)
+	invalid.Partials[0].V = nil
+
+	_, err = VerifyDecryption(pubkey, K, C, invalid)
+	require.EqualError(t, err, "partial decryption 0: missing partial decryption")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+// makeDecryptionProof creates a distributed key of n shares with a threshold t,
+// encrypts the message and returns the proof of the decryption by every
+// share-holder.
+func makeDecryptionProof(t *testing.T, message []byte, threshold, n int) (kyber.Point,
+	kyber.Point, kyber.Point, dkg.DecryptionProof) {
+
+	priPoly := share.NewPriPoly(suite, threshold, nil, suite.RandomStream())
+	_, commits := priPoly.Commit(nil).Info()
+
+	pubkey := commits[0]
+
+	M := suite.Point().Embed(message, random.New())
+	k := suite.Scalar().Pick(random.New())
+	K := suite.Point().Mul(k, nil)
+	S := suite.Point().Mul(k, pubkey)
+	C := S.Add(S, M)
+
+	proof := dkg.DecryptionProof{Commits: commits}
+
+	for _, priShare := range priPoly.Shares(n) {
+		p, _, xK, err := dleq.NewDLEQProof(suite, suite.Point().Base(), K, priShare.V)
+		require.NoError(t, err)
+
+		proof.Partials = append(proof.Partials, dkg.PartialDecryption{
+			Index: priShare.I,
+			V:     suite.Point().Sub(C, xK),
+			Proof: p,
+		})
+	}
+
+	return pubkey, K, C, proof
+}
+
+func copyProof(proof dkg.DecryptionProof) dkg.DecryptionProof {
+	return dkg.DecryptionProof{
+		Commits:  proof.Commits,
+		Partials: append([]dkg.PartialDecryption{}, proof.Partials...),
+	}
+}
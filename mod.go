@@ -9,15 +9,25 @@
 //   LLVL=trace go test ./...
 //   LLVL=info go test ./...
 //
+// Each module tags its logs with the subsystem it belongs to, which allows the
+// output to be restricted to a few of them.
+//
 package dela
 
 import (
+	"encoding/json"
+	"io"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 )
 
+// SubsystemKey is the name of the log field that identifies the subsystem
+// emitting the log.
+const SubsystemKey = "subsystem"
+
 // EnvLogLevel is the name of the environment variable to change the logging
 // level.
 const EnvLogLevel = "LLVL"
@@ -54,8 +64,61 @@ var logout = zerolog.ConsoleWriter{
 	TimeFormat: time.RFC3339,
 }
 
+var filter = &subsystemFilter{out: logout}
+
+// SetSubsystems restricts the logs to the ones emitted by the given subsystems.
+// Calling it without any name disables the filter.
+func SetSubsystems(names ...string) {
+	filter.set(names...)
+}
+
+// subsystemFilter is a log writer that drops the logs that are not tagged with
+// one of the allowed subsystems.
+//
+// - implements io.Writer
+type subsystemFilter struct {
+	sync.RWMutex
+	out   io.Writer
+	names map[string]struct{}
+}
+
+func (f *subsystemFilter) set(names ...string) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.names = make(map[string]struct{})
+	for _, name := range names {
+		f.names[name] = struct{}{}
+	}
+}
+
+// Write implements io.Writer. It forwards the log to the output only if the
+// filter is disabled or if the subsystem is allowed.
+func (f *subsystemFilter) Write(p []byte) (int, error) {
+	f.RLock()
+	defer f.RUnlock()
+
+	if len(f.names) == 0 {
+		return f.out.Write(p)
+	}
+
+	var evt struct {
+		Subsystem string `json:"subsystem"`
+	}
+
+	// Logs that can't be decoded are dropped as their origin is unknown.
+	_ = json.Unmarshal(p, &evt)
+
+	_, found := f.names[evt.Subsystem]
+	if !found {
+		return len(p), nil
+	}
+
+	return f.out.Write(p)
+}
+
 // Logger is a globally available logger instance. By default, it only prints
 // error level messages but it can be changed through a environment variable.
-var Logger = zerolog.New(logout).Level(defaultLevel).
+var Logger = zerolog.New(filter).Level(defaultLevel).
 	With().Timestamp().Logger().
 	With().Caller().Logger()
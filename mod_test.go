@@ -0,0 +1,39 @@
+package dela
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubsystemFilter_Write(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	filter := &subsystemFilter{out: buffer}
+
+	logger := zerolog.New(filter)
+
+	logger.Info().Str(SubsystemKey, "pool").Msg("A")
+	require.Contains(t, buffer.String(), `"A"`)
+
+	buffer.Reset()
+	filter.set("dkg", "cosipbft")
+
+	logger.Info().Str(SubsystemKey, "pool").Msg("B")
+	logger.Info().Str(SubsystemKey, "dkg").Msg("C")
+	sublogger := logger.With().Str(SubsystemKey, "cosipbft").Logger()
+	sublogger.Info().Msg("D")
+	logger.Info().Msg("E")
+
+	require.NotContains(t, buffer.String(), `"B"`)
+	require.Contains(t, buffer.String(), `"C"`)
+	require.Contains(t, buffer.String(), `"D"`)
+	require.NotContains(t, buffer.String(), `"E"`)
+
+	buffer.Reset()
+	filter.set()
+
+	logger.Info().Str(SubsystemKey, "pool").Msg("F")
+	require.Contains(t, buffer.String(), `"F"`)
+}